@@ -0,0 +1,66 @@
+package shrinkmap
+
+// Number is satisfied by the integer and floating-point types and named
+// types based on them
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of all values in the map. The values are read in one
+// pass under the read lock, so the result reflects a consistent state.
+func Sum[K comparable, V Number](sm *ShrinkableMap[K, V]) V {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var sum V
+	for _, v := range sm.data {
+		sum += v
+	}
+	return sum
+}
+
+// Min returns the entry with the smallest value, or false if the map is empty.
+// Among equal values an arbitrary entry is returned.
+func Min[K comparable, V Number](sm *ShrinkableMap[K, V]) (KeyValue[K, V], bool) {
+	return extreme(sm, func(a, b V) bool { return a < b })
+}
+
+// Max returns the entry with the largest value, or false if the map is empty.
+// Among equal values an arbitrary entry is returned.
+func Max[K comparable, V Number](sm *ShrinkableMap[K, V]) (KeyValue[K, V], bool) {
+	return extreme(sm, func(a, b V) bool { return a > b })
+}
+
+// Average returns the mean of all values, or false if the map is empty
+func Average[K comparable, V Number](sm *ShrinkableMap[K, V]) (float64, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if len(sm.data) == 0 {
+		return 0, false
+	}
+	// Accumulate in float64 so large integer maps cannot overflow
+	var sum float64
+	for _, v := range sm.data {
+		sum += float64(v)
+	}
+	return sum / float64(len(sm.data)), true
+}
+
+// extreme returns the entry whose value is preferred over all others by better
+func extreme[K comparable, V Number](sm *ShrinkableMap[K, V], better func(a, b V) bool) (KeyValue[K, V], bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var result KeyValue[K, V]
+	found := false
+	for k, v := range sm.data {
+		if !found || better(v, result.Value) {
+			result = KeyValue[K, V]{Key: k, Value: v}
+			found = true
+		}
+	}
+	return result, found
+}
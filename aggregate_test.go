@@ -0,0 +1,56 @@
+package shrinkmap
+
+import "testing"
+
+func TestAggregates(t *testing.T) {
+	t.Run("Values", func(t *testing.T) {
+		sm := New[string, float64](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("alice", 91.5)
+		sm.Set("bob", 78)
+		sm.Set("carol", 85.5)
+
+		if s := Sum(sm); s != 255 {
+			t.Errorf("Expected sum 255, got %v", s)
+		}
+		if kv, ok := Min(sm); !ok || kv.Key != "bob" || kv.Value != 78 {
+			t.Errorf("Expected min bob=78, got %v", kv)
+		}
+		if kv, ok := Max(sm); !ok || kv.Key != "alice" {
+			t.Errorf("Expected max alice, got %v", kv)
+		}
+		if avg, ok := Average(sm); !ok || avg != 85 {
+			t.Errorf("Expected average 85, got %v", avg)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		type score int8
+		sm := New[int, score](DefaultConfig())
+		defer sm.Stop()
+
+		if Sum(sm) != 0 {
+			t.Error("Expected zero sum")
+		}
+		if _, ok := Min(sm); ok {
+			t.Error("Expected no minimum")
+		}
+		if _, ok := Max(sm); ok {
+			t.Error("Expected no maximum")
+		}
+		if _, ok := Average(sm); ok {
+			t.Error("Expected no average")
+		}
+	})
+
+	t.Run("Average Does Not Overflow", func(t *testing.T) {
+		sm := New[int, int8](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, 100)
+		}
+		if avg, _ := Average(sm); avg != 100 {
+			t.Errorf("Expected average 100, got %v", avg)
+		}
+	})
+}
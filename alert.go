@@ -0,0 +1,148 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// AlertKind identifies the events counted by an AlertRule
+type AlertKind int
+
+const (
+	// AlertError counts errors recorded in the map's metrics, such as failed sink deliveries or backups
+	AlertError AlertKind = iota
+	// AlertPanic counts recovered panics, such as panics during shrinking
+	AlertPanic
+)
+
+// String returns the name of the alert kind
+func (k AlertKind) String() string {
+	switch k {
+	case AlertError:
+		return "error"
+	case AlertPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("AlertKind(%d)", int(k))
+	}
+}
+
+// AlertRule fires Notify when Threshold events of Kind are recorded within Window.
+// After firing, counting starts over, so a sustained failure alerts at most once per Threshold events.
+type AlertRule struct {
+	// Name identifying the rule in the Alert
+	Name string
+
+	// Events counted by the rule
+	Kind AlertKind
+
+	// Error codes counted by the rule, see ErrorCodeOf; empty counts events of
+	// any code. Panics have the code ErrCodePanic.
+	Codes []ErrorCode
+
+	// Number of events within Window that fires the alert
+	Threshold int
+
+	// Sliding window events are counted in; zero counts events regardless of age
+	Window time.Duration
+
+	// Called on a separate goroutine, in the order alerts fire; may read the
+	// map, but should return quickly as it delays later alerts
+	Notify func(Alert)
+}
+
+// Alert describes a fired AlertRule
+type Alert struct {
+	Rule      string
+	Kind      AlertKind
+	Count     int
+	Window    time.Duration
+	Last      interface{} // the error or panic value that fired the alert
+	Timestamp time.Time
+}
+
+// Validate checks if the alert rule is valid
+func (r AlertRule) Validate() error {
+	if r.Threshold <= 0 {
+		return fmt.Errorf("alert rule %q: threshold must be positive", r.Name)
+	}
+	if r.Window < 0 {
+		return fmt.Errorf("alert rule %q: window must be non-negative", r.Name)
+	}
+	if r.Notify == nil {
+		return fmt.Errorf("alert rule %q: notify function must not be nil", r.Name)
+	}
+	return nil
+}
+
+// alerter evaluates alert rules as events are recorded
+type alerter struct {
+	mu     sync.Mutex
+	rules  []AlertRule
+	events [][]time.Time // per rule, timestamps of events within the window
+}
+
+func newAlerter(rules []AlertRule) *alerter {
+	if len(rules) == 0 {
+		return nil
+	}
+	return &alerter{
+		rules:  copyRules(rules),
+		events: make([][]time.Time, len(rules)),
+	}
+}
+
+// copyRules copies rules and their codes, so later changes by the caller do
+// not affect the alerter
+func copyRules(rules []AlertRule) []AlertRule {
+	copied := append([]AlertRule(nil), rules...)
+	for i := range copied {
+		copied[i].Codes = slices.Clone(copied[i].Codes)
+	}
+	return copied
+}
+
+// record counts an event with the given error code and posts a notification
+// to n for every rule whose threshold it reaches
+func (a *alerter) record(kind AlertKind, code ErrorCode, value interface{}, n *notifier) {
+	if a == nil {
+		return
+	}
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for i, rule := range a.rules {
+		if rule.Kind != kind || (len(rule.Codes) > 0 && !slices.Contains(rule.Codes, code)) {
+			continue
+		}
+		events := a.events[i]
+		if rule.Window > 0 {
+			cutoff := now.Add(-rule.Window)
+			kept := events[:0]
+			for _, t := range events {
+				if t.After(cutoff) {
+					kept = append(kept, t)
+				}
+			}
+			events = kept
+		}
+		events = append(events, now)
+
+		if len(events) >= rule.Threshold {
+			notify, alert := rule.Notify, Alert{
+				Rule:      rule.Name,
+				Kind:      kind,
+				Count:     len(events),
+				Window:    rule.Window,
+				Last:      value,
+				Timestamp: now,
+			}
+			n.post(func() { notify(alert) })
+			events = events[:0]
+		}
+		a.events[i] = events
+	}
+}
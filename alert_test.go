@@ -0,0 +1,150 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlerts(t *testing.T) {
+	t.Run("Error Threshold", func(t *testing.T) {
+		var alerts []Alert
+		config := DefaultConfig().WithAlert(AlertRule{
+			Name:      "sink-failures",
+			Kind:      AlertError,
+			Threshold: 3,
+			Window:    time.Minute,
+			Notify:    func(a Alert) { alerts = append(alerts, a) },
+		})
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		for i := 0; i < 2; i++ {
+			sm.metrics.RecordError(errors.New("failure"), "")
+		}
+		sm.metrics.notifier.wait()
+		if len(alerts) != 0 {
+			t.Fatalf("Expected no alert below the threshold, got %d", len(alerts))
+		}
+		sm.metrics.RecordError(errors.New("last failure"), "")
+		sm.metrics.notifier.wait()
+		if len(alerts) != 1 {
+			t.Fatalf("Expected 1 alert, got %d", len(alerts))
+		}
+		if alerts[0].Rule != "sink-failures" || alerts[0].Count != 3 || alerts[0].Last.(error).Error() != "last failure" {
+			t.Errorf("Unexpected alert: %+v", alerts[0])
+		}
+
+		// Counting starts over after the alert fires
+		sm.metrics.RecordError(errors.New("failure"), "")
+		sm.metrics.notifier.wait()
+		if len(alerts) != 1 {
+			t.Errorf("Expected counting to restart, got %d alerts", len(alerts))
+		}
+		// Panics are not counted by an error rule
+		sm.metrics.RecordPanic("boom", "")
+		sm.metrics.RecordPanic("boom", "")
+		sm.metrics.notifier.wait()
+		if len(alerts) != 1 {
+			t.Errorf("Expected panics to be ignored, got %d alerts", len(alerts))
+		}
+	})
+
+	t.Run("Shrink Panic", func(t *testing.T) {
+		fired := make(chan Alert, 1)
+		config := DefaultConfig().WithAlert(AlertRule{
+			Name:      "shrink-panic",
+			Kind:      AlertPanic,
+			Threshold: 1,
+			Notify:    func(a Alert) { fired <- a },
+		})
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		// The shrink loop records recovered panics through RecordPanic
+		sm.metrics.RecordPanic("shrink failed", "")
+		select {
+		case a := <-fired:
+			if a.Kind != AlertPanic || a.Last != "shrink failed" {
+				t.Errorf("Unexpected alert: %+v", a)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected alert for shrink panic")
+		}
+	})
+
+	t.Run("Notify Reads Map", func(t *testing.T) {
+		fired := make(chan []int, 1)
+		var sm *ShrinkableMap[string, []int]
+		config := DefaultConfig().WithVerifyImmutable(true).WithAlert(AlertRule{
+			Name:      "mutation",
+			Kind:      AlertError,
+			Threshold: 1,
+			Notify: func(Alert) {
+				value, _ := sm.Get("a")
+				fired <- value
+			},
+		})
+		sm = New[string, []int](config)
+		defer sm.Stop()
+
+		value := []int{1}
+		sm.Set("a", value)
+		value[0] = 2
+
+		// The mutation is recorded while Set holds the map's lock
+		done := make(chan struct{})
+		go func() {
+			sm.Set("a", []int{3})
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Set deadlocked on an alert reading the map")
+		}
+		select {
+		case value := <-fired:
+			if len(value) != 1 || value[0] != 3 {
+				t.Errorf("Expected Notify to read the new value, got %v", value)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected alert for mutated value")
+		}
+	})
+
+	t.Run("Codes", func(t *testing.T) {
+		var alerts []Alert
+		config := DefaultConfig().WithAlert(AlertRule{
+			Name:      "sink-backlog",
+			Kind:      AlertError,
+			Codes:     []ErrorCode{ErrCodeSinkBufferFull, ErrCodeWatchChannelFull},
+			Threshold: 2,
+			Notify:    func(a Alert) { alerts = append(alerts, a) },
+		})
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		sm.metrics.RecordError(ErrSinkBufferFull, "")
+		sm.metrics.RecordError(errors.New("failure"), "")
+		sm.metrics.RecordError(ErrValueTooLarge, "")
+		sm.metrics.notifier.wait()
+		if len(alerts) != 0 {
+			t.Fatalf("Expected errors with other codes to be ignored, got %d alerts", len(alerts))
+		}
+		sm.metrics.RecordError(ErrWatchChannelFull, "")
+		sm.metrics.notifier.wait()
+		if len(alerts) != 1 || alerts[0].Count != 2 {
+			t.Errorf("Expected 1 alert counting 2 matching errors, got %+v", alerts)
+		}
+	})
+
+	t.Run("Invalid Rule", func(t *testing.T) {
+		if err := DefaultConfig().WithAlert(AlertRule{Name: "x", Threshold: 1}).Validate(); err == nil {
+			t.Error("Expected error for rule without Notify")
+		}
+		if err := DefaultConfig().WithAlert(AlertRule{Name: "x", Notify: func(Alert) {}}).Validate(); err == nil {
+			t.Error("Expected error for rule without threshold")
+		}
+	})
+}
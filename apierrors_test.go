@@ -0,0 +1,84 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecordAPIErrors(t *testing.T) {
+	t.Run("Records Returned Errors", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithRecordAPIErrors(true).WithMaxValueBytes(1, nil))
+		defer sm.Stop()
+
+		sm.Set("a", "too long")
+		sm.Rename("missing", "b", false)
+		batch := BatchOperations[string, string]{
+			Operations: []BatchOperation[string, string]{{Type: BatchSet, Key: "c", Value: "too long"}},
+		}
+		sm.ApplyBatch(batch)
+
+		metrics := sm.GetMetrics()
+		if metrics.ErrorCount(ErrCodeValueTooLarge) != 2 || metrics.ErrorCount(ErrCodeKeyNotFound) != 1 {
+			t.Errorf("Unexpected counts: %v", metrics.ErrorsByCode())
+		}
+		if last := metrics.LastError(); last == nil || !errors.Is(last.Error.(error), ErrValueTooLarge) {
+			t.Errorf("Expected the batch failure as last error, got %+v", last)
+		}
+	})
+
+	t.Run("Set Always Records", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithMaxValueBytes(1, nil))
+		defer sm.Stop()
+
+		sm.Set("a", "too long")
+		if err := sm.SetChecked("b", "too long"); !errors.Is(err, ErrValueTooLarge) {
+			t.Fatalf("Expected ErrValueTooLarge, got %v", err)
+		}
+		metrics := sm.GetMetrics()
+		if metrics.ErrorCount(ErrCodeValueTooLarge) != 1 {
+			t.Errorf("Expected only the write dropped by Set recorded, got %v", metrics.ErrorsByCode())
+		}
+		if sm.Contains("a") {
+			t.Error("Rejected write should be dropped")
+		}
+	})
+
+	t.Run("History Rate Limited", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithRecordAPIErrors(true).WithMaxValueBytes(1, nil))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			sm.SetChecked("a", "too long")
+		}
+		metrics := sm.GetMetrics()
+		if metrics.TotalErrors() != 100 || metrics.ErrorCount(ErrCodeValueTooLarge) != 100 {
+			t.Errorf("Expected every error counted, got %d", metrics.TotalErrors())
+		}
+
+		var limiter apiErrorLimiter
+		now := time.Now()
+		for i := 0; i < apiErrorsPerSecond; i++ {
+			if !limiter.allow(now) {
+				t.Fatalf("Expected error %d to be allowed", i)
+			}
+		}
+		if limiter.allow(now.Add(999 * time.Millisecond)) {
+			t.Error("Expected errors beyond the limit to be dropped")
+		}
+		if !limiter.allow(now.Add(time.Second)) {
+			t.Error("Expected the limit to reset after a second")
+		}
+	})
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig())
+		sm.Stop()
+
+		sm.Set("a", "b")
+		metrics := sm.GetMetrics()
+		if metrics.TotalErrors() != 0 {
+			t.Errorf("Expected no recorded errors, got %d", metrics.TotalErrors())
+		}
+	})
+}
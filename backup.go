@@ -0,0 +1,312 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrSnapshotNotFound is returned when no snapshot exists in a storage
+var ErrSnapshotNotFound = errors.New("shrinkmap: snapshot not found")
+
+// ObjectInfo describes a snapshot object held by a Storage
+type ObjectInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage is a destination for snapshot backups, such as a local directory
+// or an S3-compatible object store
+type Storage interface {
+	// Put stores the object under the given name, replacing any existing one
+	Put(ctx context.Context, name string, r io.Reader, size int64) error
+
+	// Get opens the named object for reading; returns ErrSnapshotNotFound if missing
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+
+	// List returns all objects whose names start with prefix. Snapshots of
+	// other maps whose prefix extends this one may be included; the map
+	// selects its own snapshots by their name format.
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+
+	// Delete removes the named object
+	Delete(ctx context.Context, name string) error
+}
+
+// DirStorage stores snapshots as files in a local directory
+type DirStorage struct {
+	Dir string
+}
+
+// NewDirStorage creates a DirStorage, creating the directory if needed
+func NewDirStorage(dir string) (*DirStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create snapshot directory: %w", err)
+	}
+	return &DirStorage{Dir: dir}, nil
+}
+
+// Put writes the object to a temporary file and renames it into place
+func (s *DirStorage) Put(_ context.Context, name string, r io.Reader, _ int64) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.Dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the named snapshot file
+func (s *DirStorage) Get(_ context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+	return f, err
+}
+
+// List returns the snapshot files whose names start with prefix
+func (s *DirStorage) List(_ context.Context, prefix string) ([]ObjectInfo, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []ObjectInfo
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), prefix) || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, ObjectInfo{Name: entry.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+	return objects, nil
+}
+
+// Delete removes the named snapshot file
+func (s *DirStorage) Delete(_ context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *DirStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) {
+		return "", fmt.Errorf("invalid snapshot name %q", name)
+	}
+	return filepath.Join(s.Dir, name), nil
+}
+
+// RetentionPolicy decides which old snapshots are deleted after a backup.
+// Zero values disable the respective rule; the newest snapshot is always kept.
+type RetentionPolicy struct {
+	// Number of most recent snapshots to keep
+	KeepLast int
+
+	// Snapshots older than this are deleted
+	MaxAge time.Duration
+}
+
+// BackupConfig defines periodic snapshot backups
+type BackupConfig struct {
+	// Destination of the snapshots
+	Storage Storage
+
+	// Name prefix identifying this map's snapshots within the storage
+	Prefix string
+
+	// How often a snapshot is taken
+	Interval time.Duration
+
+	// Which old snapshots to delete after every successful backup
+	Retention RetentionPolicy
+}
+
+// Validate checks if the backup configuration is valid
+func (c BackupConfig) Validate() error {
+	if c.Storage == nil {
+		return fmt.Errorf("backup storage must not be nil")
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("backup interval must be positive")
+	}
+	if c.Retention.KeepLast < 0 {
+		return fmt.Errorf("retention keep last must be non-negative")
+	}
+	if c.Retention.MaxAge < 0 {
+		return fmt.Errorf("retention max age must be non-negative")
+	}
+	return nil
+}
+
+// snapshotTimeFormat sorts lexicographically in chronological order
+const snapshotTimeFormat = "20060102T150405.000000000Z"
+
+const snapshotSuffix = ".snap"
+
+// Backup saves a snapshot of the map to storage and returns the object name
+func (sm *ShrinkableMap[K, V]) Backup(ctx context.Context, storage Storage, prefix string) (string, error) {
+	var buf bytes.Buffer
+	if err := sm.SaveTo(&buf); err != nil {
+		return "", err
+	}
+
+	name := prefix + time.Now().UTC().Format(snapshotTimeFormat) + snapshotSuffix
+	if err := storage.Put(ctx, name, &buf, int64(buf.Len())); err != nil {
+		return "", fmt.Errorf("store snapshot %s: %w", name, err)
+	}
+	return name, nil
+}
+
+// RestoreLatest loads the most recent snapshot with the given prefix into the map
+// and returns its name
+func (sm *ShrinkableMap[K, V]) RestoreLatest(ctx context.Context, storage Storage, prefix string) (string, error) {
+	snapshots, err := listSnapshots(ctx, storage, prefix)
+	if err != nil {
+		return "", err
+	}
+	if len(snapshots) == 0 {
+		return "", ErrSnapshotNotFound
+	}
+
+	name := snapshots[len(snapshots)-1].Name
+	return name, sm.Restore(ctx, storage, name)
+}
+
+// Restore loads the named snapshot from storage into the map
+func (sm *ShrinkableMap[K, V]) Restore(ctx context.Context, storage Storage, name string) error {
+	rc, err := storage.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return sm.LoadFrom(rc)
+}
+
+// ApplyRetention deletes the snapshots with the given prefix that fall outside the policy
+func ApplyRetention(ctx context.Context, storage Storage, prefix string, policy RetentionPolicy) error {
+	snapshots, err := listSnapshots(ctx, storage, prefix)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	now := time.Now()
+	for i, obj := range snapshots {
+		newest := i == len(snapshots)-1
+		tooMany := policy.KeepLast > 0 && len(snapshots)-i > policy.KeepLast
+		tooOld := policy.MaxAge > 0 && now.Sub(obj.ModTime) > policy.MaxAge
+		if newest || !(tooMany || tooOld) {
+			continue
+		}
+		if err := storage.Delete(ctx, obj.Name); err != nil {
+			errs = append(errs, fmt.Errorf("delete snapshot %s: %w", obj.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartBackups periodically backs up the map until the returned stop function
+// is called or the map is stopped. Failures are recorded in the map's metrics.
+func (sm *ShrinkableMap[K, V]) StartBackups(config BackupConfig) (func(), error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(sm.ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := sm.Backup(ctx, config.Storage, config.Prefix); err != nil {
+					sm.metrics.RecordError(err, "")
+					continue
+				}
+				if err := ApplyRetention(ctx, config.Storage, config.Prefix, config.Retention); err != nil {
+					sm.metrics.RecordError(err, "")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+	}, nil
+}
+
+// isSnapshotName reports whether name is a snapshot written by Backup with
+// prefix, i.e. prefix directly followed by a timestamp and the suffix. Names
+// of snapshots whose prefix merely starts with prefix do not match.
+func isSnapshotName(name, prefix string) bool {
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, snapshotSuffix) {
+		return false
+	}
+	stamp := strings.TrimSuffix(name[len(prefix):], snapshotSuffix)
+	_, err := time.Parse(snapshotTimeFormat, stamp)
+	return err == nil
+}
+
+// listSnapshots returns the snapshots with the given prefix, oldest first
+func listSnapshots(ctx context.Context, storage Storage, prefix string) ([]ObjectInfo, error) {
+	objects, err := storage.List(ctx, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots: %w", err)
+	}
+
+	snapshots := objects[:0]
+	for _, obj := range objects {
+		if isSnapshotName(obj.Name, prefix) {
+			snapshots = append(snapshots, obj)
+		}
+	}
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Name < snapshots[j].Name
+	})
+	return snapshots, nil
+}
@@ -0,0 +1,137 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackup(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Dir Storage Backup And Restore", func(t *testing.T) {
+		storage, err := NewDirStorage(t.TempDir())
+		if err != nil {
+			t.Fatalf("NewDirStorage failed: %v", err)
+		}
+
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		if _, err := sm.Backup(ctx, storage, "test-"); err != nil {
+			t.Fatalf("Backup failed: %v", err)
+		}
+		sm.Set("a", 2)
+		if _, err := sm.Backup(ctx, storage, "test-"); err != nil {
+			t.Fatalf("Backup failed: %v", err)
+		}
+
+		restored := New[string, int](DefaultConfig())
+		defer restored.Stop()
+		if _, err := restored.RestoreLatest(ctx, storage, "test-"); err != nil {
+			t.Fatalf("RestoreLatest failed: %v", err)
+		}
+		if v, _ := restored.Get("a"); v != 2 {
+			t.Errorf("Expected latest snapshot to be restored, got a=%d", v)
+		}
+	})
+
+	t.Run("Restore Without Snapshots", func(t *testing.T) {
+		storage, _ := NewDirStorage(t.TempDir())
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		if _, err := sm.RestoreLatest(ctx, storage, "test-"); !errors.Is(err, ErrSnapshotNotFound) {
+			t.Errorf("Expected ErrSnapshotNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Retention Keeps Newest", func(t *testing.T) {
+		storage, _ := NewDirStorage(t.TempDir())
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		for i := 0; i < 4; i++ {
+			if _, err := sm.Backup(ctx, storage, "test-"); err != nil {
+				t.Fatalf("Backup failed: %v", err)
+			}
+		}
+
+		if err := ApplyRetention(ctx, storage, "test-", RetentionPolicy{KeepLast: 2}); err != nil {
+			t.Fatalf("ApplyRetention failed: %v", err)
+		}
+		objects, _ := storage.List(ctx, "test-")
+		if len(objects) != 2 {
+			t.Errorf("Expected 2 snapshots, got %d", len(objects))
+		}
+
+		if err := ApplyRetention(ctx, storage, "test-", RetentionPolicy{MaxAge: time.Nanosecond}); err != nil {
+			t.Fatalf("ApplyRetention failed: %v", err)
+		}
+		objects, _ = storage.List(ctx, "test-")
+		if len(objects) != 1 {
+			t.Errorf("Expected newest snapshot to be kept, got %d", len(objects))
+		}
+	})
+
+	t.Run("Prefix Extending Another", func(t *testing.T) {
+		storage, _ := NewDirStorage(t.TempDir())
+		users := New[string, int](DefaultConfig())
+		defer users.Stop()
+		archive := New[string, int](DefaultConfig())
+		defer archive.Stop()
+		users.Set("map", 1)
+		archive.Set("map", 2)
+
+		if _, err := users.Backup(ctx, storage, "users"); err != nil {
+			t.Fatalf("Backup failed: %v", err)
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := archive.Backup(ctx, storage, "users_archive_"); err != nil {
+				t.Fatalf("Backup failed: %v", err)
+			}
+		}
+
+		restored := New[string, int](DefaultConfig())
+		defer restored.Stop()
+		if _, err := restored.RestoreLatest(ctx, storage, "users"); err != nil {
+			t.Fatalf("RestoreLatest failed: %v", err)
+		}
+		if v, _ := restored.Get("map"); v != 1 {
+			t.Errorf("Expected the users snapshot to be restored, got map=%d", v)
+		}
+
+		if err := ApplyRetention(ctx, storage, "users", RetentionPolicy{KeepLast: 1}); err != nil {
+			t.Fatalf("ApplyRetention failed: %v", err)
+		}
+		if objects, _ := storage.List(ctx, "users_archive_"); len(objects) != 2 {
+			t.Errorf("Expected retention of users to keep the archive snapshots, got %d", len(objects))
+		}
+	})
+
+	t.Run("Periodic Backups", func(t *testing.T) {
+		storage, _ := NewDirStorage(t.TempDir())
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		stop, err := sm.StartBackups(BackupConfig{
+			Storage:   storage,
+			Prefix:    "periodic-",
+			Interval:  5 * time.Millisecond,
+			Retention: RetentionPolicy{KeepLast: 2},
+		})
+		if err != nil {
+			t.Fatalf("StartBackups failed: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+		stop()
+
+		objects, _ := storage.List(ctx, "periodic-")
+		if len(objects) == 0 || len(objects) > 2 {
+			t.Errorf("Expected 1-2 retained snapshots, got %d", len(objects))
+		}
+	})
+}
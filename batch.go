@@ -1,5 +1,13 @@
 package shrinkmap
 
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
 // BatchOperations provides batch operation capabilities
 type BatchOperations[K comparable, V any] struct {
 	Operations []BatchOperation[K, V]
@@ -9,6 +17,15 @@ type BatchOperation[K comparable, V any] struct {
 	Type  BatchOpType
 	Key   K
 	Value V
+
+	// Condition, if set, must return true for the operation to be applied. It
+	// is called with the key's value as left by the preceding operations of
+	// the batch; otherwise the operation fails with ErrConditionFailed.
+	// Not supported for BatchDeletePrefix and BatchDeleteMatch.
+	Condition func(current V, exists bool) bool
+
+	// Match selects the entries removed by BatchDeleteMatch
+	Match func(key K, value V) bool
 }
 
 type BatchOpType int
@@ -16,32 +33,232 @@ type BatchOpType int
 const (
 	BatchSet BatchOpType = iota
 	BatchDelete
+	// BatchDeletePrefix removes every key starting with Key; requires string keys
+	BatchDeletePrefix
+	// BatchDeleteMatch removes every entry for which Match returns true
+	BatchDeleteMatch
+	// BatchGet reads Key as left by the preceding operations; only supported
+	// by ApplyBatchWithReads. A Condition makes it an assertion on the value.
+	BatchGet
 )
 
+// BatchMode selects how ApplyBatchMode handles failing operations
+type BatchMode int
+
+const (
+	// BatchAtomic stages all operations and applies none if any fails
+	BatchAtomic BatchMode = iota
+	// BatchBestEffort applies every operation that succeeds and reports the others
+	BatchBestEffort
+)
+
+// ErrConditionFailed is returned for batch operations whose Condition returned false
+var ErrConditionFailed = errors.New("shrinkmap: batch condition failed")
+
+// BatchError records the batch operation that failed
+type BatchError struct {
+	Index int
+	Key   interface{}
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch operation %d (key %v): %v", e.Index, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error, such as ErrConditionFailed
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// BatchResult reports the outcome of ApplyBatchMode
+type BatchResult struct {
+	// Number of operations applied; range deletes count once per removed key
+	Applied int
+
+	// Operations that failed, ordered by index. In atomic mode this holds
+	// the first failure and nothing was applied.
+	Failed []*BatchError
+}
+
 // ApplyBatch applies multiple operations atomically
 func (sm *ShrinkableMap[K, V]) ApplyBatch(batch BatchOperations[K, V]) error {
+	_, err := sm.ApplyBatchMode(batch, BatchAtomic)
+	return err
+}
+
+// BatchRead is the result of a BatchGet operation
+type BatchRead[V any] struct {
+	Index  int // index of the BatchGet operation in the batch
+	Value  V
+	Exists bool
+}
+
+// ApplyBatchWithReads applies the batch atomically like ApplyBatch and returns
+// the results of its BatchGet operations in order. Reads observe the writes of
+// preceding operations in the same batch, and the whole batch runs under one
+// lock, so a read followed by conditional writes forms an atomic transition.
+func (sm *ShrinkableMap[K, V]) ApplyBatchWithReads(batch BatchOperations[K, V]) ([]BatchRead[V], error) {
+	reads := []BatchRead[V]{}
+	if _, err := sm.applyBatch(batch, BatchAtomic, &reads); err != nil {
+		return nil, sm.apiError(err)
+	}
+	for i := range reads {
+		reads[i].Value = sm.copyOnRead(reads[i].Value)
+	}
+	return reads, nil
+}
+
+// ApplyBatchMode applies the operations in order. All operations are staged
+// and checked before the map is modified, so in BatchAtomic mode a failing
+// operation leaves the map untouched and its *BatchError is returned. In
+// BatchBestEffort mode failing operations are skipped and reported in the
+// result; the error is only set if the batch could not be attempted at all.
+func (sm *ShrinkableMap[K, V]) ApplyBatchMode(batch BatchOperations[K, V], mode BatchMode) (BatchResult, error) {
+	result, err := sm.applyBatch(batch, mode, nil)
+	return result, sm.apiError(err)
+}
+
+// applyBatch applies the batch, collecting the results of BatchGet
+// operations into reads. BatchGet operations fail if reads is nil.
+func (sm *ShrinkableMap[K, V]) applyBatch(batch BatchOperations[K, V], mode BatchMode, reads *[]BatchRead[V]) (BatchResult, error) {
+	defer sm.finishOp("batch", sm.startOp())
+	prepared, failed := sm.prepareBatchOps(batch, mode)
+	if mode == BatchAtomic && len(failed) > 0 {
+		return BatchResult{Failed: failed}, failed[0]
+	}
+
 	sm.mu.Lock()
-	defer sm.mu.Unlock()
+	staged, failed := sm.stageBatchLocked(prepared, failed, mode, reads)
+	if mode == BatchAtomic && len(failed) > 0 {
+		sm.mu.Unlock()
+		return BatchResult{Failed: failed}, failed[0]
+	}
+	sm.applyBatchLocked(staged)
+	sm.mu.Unlock()
 
+	if sm.config.AutoShrinkEnabled {
+		sm.requestShrink()
+	}
+	return BatchResult{Applied: len(staged.Operations), Failed: failed}, nil
+}
+
+// stageBatchLocked checks every operation not already in failed against the
+// map as modified by the preceding operations and returns the operations to
+// apply. The results of BatchGet operations are appended to reads. In atomic
+// mode it stops at the first failure. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) stageBatchLocked(batch BatchOperations[K, V], failed []*BatchError, mode BatchMode, reads *[]BatchRead[V]) (BatchOperations[K, V], []*BatchError) {
+	skip := make(map[int]bool, len(failed))
+	for _, f := range failed {
+		skip[f.Index] = true
+	}
+	// Conditions, range deletes and reads need the map as modified by preceding operations
+	conditional := false
 	for _, op := range batch.Operations {
+		conditional = conditional || op.Condition != nil || op.Type == BatchDeletePrefix || op.Type == BatchDeleteMatch || op.Type == BatchGet
+	}
+
+	type stagedValue struct {
+		value  V
+		exists bool
+	}
+	var staged map[K]stagedValue
+	if conditional {
+		staged = make(map[K]stagedValue)
+	}
+
+	result := BatchOperations[K, V]{Operations: make([]BatchOperation[K, V], 0, len(batch.Operations))}
+	for i, op := range batch.Operations {
+		if skip[i] {
+			continue
+		}
+
+		var err error
+		var match func(key K, value V) bool
 		switch op.Type {
 		case BatchSet:
-			_, exists := sm.data[op.Key]
-			sm.data[op.Key] = op.Value
-			if !exists {
-				sm.itemCount.Add(1)
-				sm.updateMetrics(1)
-			}
+			err = sm.checkInsertLocked("batch", op.Key)
 		case BatchDelete:
-			if _, exists := sm.data[op.Key]; exists {
-				delete(sm.data, op.Key)
-				sm.deletedCount.Add(1)
+		case BatchGet:
+			if reads == nil {
+				err = fmt.Errorf("reads require ApplyBatchWithReads")
+			}
+		case BatchDeletePrefix:
+			if err = requireStringKeys[K](); err == nil {
+				prefix := reflect.ValueOf(op.Key).String()
+				match = func(key K, _ V) bool {
+					return strings.HasPrefix(reflect.ValueOf(key).String(), prefix)
+				}
+			}
+		case BatchDeleteMatch:
+			if match = op.Match; match == nil {
+				err = fmt.Errorf("match function must not be nil")
+			}
+		default:
+			err = fmt.Errorf("unknown batch operation type %d", op.Type)
+		}
+		if err == nil && match != nil && op.Condition != nil {
+			err = fmt.Errorf("conditions are not supported for range deletes")
+		}
+		if err == nil && match != nil {
+			// Expand into deletes of the keys currently matching
+			for k, v := range sm.data {
+				if _, ok := staged[k]; !ok && match(k, v) {
+					staged[k] = stagedValue{}
+					result.Operations = append(result.Operations, BatchOperation[K, V]{Type: BatchDelete, Key: k})
+				}
 			}
+			for k, s := range staged {
+				if s.exists && match(k, s.value) {
+					staged[k] = stagedValue{}
+					result.Operations = append(result.Operations, BatchOperation[K, V]{Type: BatchDelete, Key: k})
+				}
+			}
+			continue
+		}
+		var current V
+		var exists bool
+		if err == nil && (op.Condition != nil || op.Type == BatchGet) {
+			current, exists = sm.data[op.Key]
+			if s, ok := staged[op.Key]; ok {
+				current, exists = s.value, s.exists
+			}
+			if op.Condition != nil && !op.Condition(current, exists) {
+				err = ErrConditionFailed
+			}
+		}
+		if err != nil {
+			failed = append(failed, &BatchError{Index: i, Key: sm.errorKey(op.Key), Err: err})
+			if mode == BatchAtomic {
+				return result, failed
+			}
+			continue
+		}
+
+		if op.Type == BatchGet {
+			*reads = append(*reads, BatchRead[V]{Index: i, Value: current, Exists: exists})
+			continue
 		}
+		if conditional {
+			staged[op.Key] = stagedValue{value: op.Value, exists: op.Type == BatchSet}
+		}
+		result.Operations = append(result.Operations, op)
 	}
+	sort.Slice(failed, func(i, j int) bool { return failed[i].Index < failed[j].Index })
+	return result, failed
+}
 
-	if sm.config.AutoShrinkEnabled {
-		go sm.TryShrink()
+// applyBatchLocked applies the operations in order and reports whether the
+// map reached MaxMapSize. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) applyBatchLocked(batch BatchOperations[K, V]) bool {
+	needsShrink := false
+	for _, op := range batch.Operations {
+		switch op.Type {
+		case BatchSet:
+			needsShrink = sm.setLocked(op.Key, op.Value) || needsShrink
+		case BatchDelete:
+			sm.deleteLocked(op.Key)
+		}
 	}
-	return nil
+	return needsShrink
 }
@@ -1,6 +1,7 @@
 package shrinkmap
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -214,3 +215,195 @@ func TestBatchOperations(t *testing.T) {
 		}
 	})
 }
+
+func TestBatchModes(t *testing.T) {
+	absent := func(current int, exists bool) bool { return !exists }
+	batch := BatchOperations[string, int]{
+		Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "a", Value: 1, Condition: absent},
+			{Type: BatchSet, Key: "b", Value: 2},
+			{Type: BatchSet, Key: "a", Value: 3, Condition: absent}, // sees the staged a
+			{Type: BatchDelete, Key: "b"},
+		},
+	}
+
+	t.Run("Atomic Rolls Back", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		result, err := sm.ApplyBatchMode(batch, BatchAtomic)
+		var batchErr *BatchError
+		if !errors.Is(err, ErrConditionFailed) || !errors.As(err, &batchErr) || batchErr.Index != 2 {
+			t.Fatalf("Expected condition failure at operation 2, got %v", err)
+		}
+		if result.Applied != 0 || sm.Len() != 0 {
+			t.Errorf("Expected nothing to be applied, got applied=%d len=%d", result.Applied, sm.Len())
+		}
+		if err := sm.ApplyBatch(batch); !errors.Is(err, ErrConditionFailed) {
+			t.Errorf("Expected ApplyBatch to be atomic, got %v", err)
+		}
+	})
+
+	t.Run("Best Effort Reports Failures", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithValidators(nil, func(value any) error {
+			if value.(int) == 2 {
+				return errors.New("two is not allowed")
+			}
+			return nil
+		}))
+		defer sm.Stop()
+
+		result, err := sm.ApplyBatchMode(batch, BatchBestEffort)
+		if err != nil {
+			t.Fatalf("ApplyBatchMode failed: %v", err)
+		}
+		if result.Applied != 2 || len(result.Failed) != 2 {
+			t.Fatalf("Expected 2 applied and 2 failed, got %+v", result)
+		}
+		if result.Failed[0].Index != 1 || !errors.Is(result.Failed[0].Err, ErrValidation) {
+			t.Errorf("Expected validation failure at 1, got %v", result.Failed[0])
+		}
+		if result.Failed[1].Index != 2 || !errors.Is(result.Failed[1].Err, ErrConditionFailed) {
+			t.Errorf("Expected condition failure at 2, got %v", result.Failed[1])
+		}
+		if v, _ := sm.Get("a"); v != 1 || sm.Len() != 1 {
+			t.Errorf("Expected only a=1, got a=%d len=%d", v, sm.Len())
+		}
+	})
+
+	t.Run("Conditions In ApplyAtomic", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		err := ApplyAtomic(WithBatch(sm, BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "a", Value: 2, Condition: absent},
+		}}))
+		if !errors.Is(err, ErrConditionFailed) {
+			t.Errorf("Expected ErrConditionFailed, got %v", err)
+		}
+	})
+}
+
+func TestBatchRangeDeletes(t *testing.T) {
+	t.Run("Prefix", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("user:1", 1)
+		sm.Set("user:2", 2)
+		sm.Set("group:1", 3)
+
+		result, err := sm.ApplyBatchMode(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "user:3", Value: 4},
+			{Type: BatchDeletePrefix, Key: "user:"},
+			{Type: BatchSet, Key: "user:4", Value: 5},
+		}}, BatchAtomic)
+		if err != nil {
+			t.Fatalf("ApplyBatchMode failed: %v", err)
+		}
+		if result.Applied != 5 {
+			t.Errorf("Expected 5 applied operations, got %d", result.Applied)
+		}
+		if sm.Len() != 2 || !sm.Contains("group:1") || !sm.Contains("user:4") {
+			t.Errorf("Expected only group:1 and user:4 to remain, got %v", sm.Snapshot())
+		}
+	})
+
+	t.Run("Predicate", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i*10)
+		}
+
+		err := sm.ApplyBatch(BatchOperations[int, int]{Operations: []BatchOperation[int, int]{
+			{Type: BatchDeleteMatch, Match: func(key, value int) bool { return value >= 50 }},
+		}})
+		if err != nil {
+			t.Fatalf("ApplyBatch failed: %v", err)
+		}
+		if sm.Len() != 5 || sm.Contains(5) {
+			t.Errorf("Expected values >= 50 to be deleted, got len=%d", sm.Len())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set(1, 1)
+
+		for _, op := range []BatchOperation[int, int]{
+			{Type: BatchDeletePrefix, Key: 1},
+			{Type: BatchDeleteMatch},
+		} {
+			if err := sm.ApplyBatch(BatchOperations[int, int]{Operations: []BatchOperation[int, int]{op}}); err == nil {
+				t.Errorf("Expected error for %+v", op)
+			}
+		}
+		if sm.Len() != 1 {
+			t.Error("Failed range deletes must not modify the map")
+		}
+	})
+}
+
+func TestBatchReads(t *testing.T) {
+	t.Run("ReadYourWrites", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+
+		reads, err := sm.ApplyBatchWithReads(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchGet, Key: "a"},
+			{Type: BatchSet, Key: "a", Value: 10},
+			{Type: BatchGet, Key: "a"},
+			{Type: BatchDelete, Key: "b"},
+			{Type: BatchGet, Key: "b"},
+		}})
+		if err != nil {
+			t.Fatalf("ApplyBatchWithReads failed: %v", err)
+		}
+		want := []BatchRead[int]{{Index: 0, Value: 1, Exists: true}, {Index: 2, Value: 10, Exists: true}, {Index: 4}}
+		if len(reads) != len(want) {
+			t.Fatalf("Expected %d reads, got %v", len(want), reads)
+		}
+		for i := range want {
+			if reads[i] != want[i] {
+				t.Errorf("Read %d: expected %+v, got %+v", i, want[i], reads[i])
+			}
+		}
+		if v, _ := sm.Get("a"); v != 10 || sm.Contains("b") {
+			t.Errorf("Expected writes to be applied, got %v", sm.Snapshot())
+		}
+	})
+
+	t.Run("FailedAssertion", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		reads, err := sm.ApplyBatchWithReads(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "a", Value: 2},
+			{Type: BatchGet, Key: "a", Condition: func(v int, _ bool) bool { return v == 1 }},
+		}})
+		if !errors.Is(err, ErrConditionFailed) || reads != nil {
+			t.Errorf("Expected ErrConditionFailed and no reads, got %v, %v", reads, err)
+		}
+		if v, _ := sm.Get("a"); v != 1 {
+			t.Errorf("Failed batch must not modify the map, got %d", v)
+		}
+	})
+
+	t.Run("RequiresApplyBatchWithReads", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		err := sm.ApplyBatch(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "a", Value: 1},
+			{Type: BatchGet, Key: "a"},
+		}})
+		if err == nil || sm.Len() != 0 {
+			t.Errorf("Expected BatchGet to be rejected, got err=%v len=%d", err, sm.Len())
+		}
+	})
+}
@@ -0,0 +1,324 @@
+// Package bench runs configurable workloads against a ShrinkableMap, so
+// candidate configurations can be compared on the caller's own key
+// distribution and read/write mix without copying the package benchmarks.
+package bench
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// Distribution chooses key indexes for reads and updates
+type Distribution interface {
+	// Sampler returns a function drawing key indexes using r. Each worker
+	// gets its own sampler, so it need not be safe for concurrent use.
+	Sampler(r *rand.Rand) func() uint64
+}
+
+type uniform struct{ n uint64 }
+
+// Uniform draws key indexes in [0, n) with equal probability
+func Uniform(n uint64) Distribution {
+	return uniform{n}
+}
+
+func (u uniform) Sampler(r *rand.Rand) func() uint64 {
+	return func() uint64 { return uint64(r.Int63n(int64(u.n))) }
+}
+
+type zipf struct {
+	n uint64
+	s float64
+}
+
+// Zipf draws key indexes in [0, n) following a Zipf distribution with
+// exponent s > 1, so a few hot keys receive most operations
+func Zipf(n uint64, s float64) Distribution {
+	return zipf{n, s}
+}
+
+func (z zipf) Sampler(r *rand.Rand) func() uint64 {
+	return rand.NewZipf(r, z.s, 1, z.n-1).Uint64
+}
+
+type sequential struct{ next atomic.Uint64 }
+
+// Sequential hands out increasing key indexes shared by all workers, so every
+// write inserts a new key
+func Sequential() Distribution {
+	return &sequential{}
+}
+
+func (s *sequential) Sampler(*rand.Rand) func() uint64 {
+	return func() uint64 { return s.next.Add(1) - 1 }
+}
+
+// Mix weighs the kinds of operations; the weights need not sum to 100
+type Mix struct {
+	Read, Write, Delete int
+}
+
+// Common operation mixes
+var (
+	ReadHeavy  = Mix{Read: 90, Write: 10}
+	Balanced   = Mix{Read: 50, Write: 50}
+	WriteHeavy = Mix{Read: 10, Write: 90}
+	Churning   = Mix{Read: 40, Write: 30, Delete: 30}
+)
+
+// Workload describes the operations a run performs
+type Workload[K comparable] struct {
+	// Name identifying the workload in reports
+	Name string
+
+	// Distribution of the keys read, written and deleted
+	Keys Distribution
+
+	// Converts a key index into a key, e.g. strconv.FormatUint for string keys
+	Key func(index uint64) K
+
+	// Relative frequency of reads, writes and deletes
+	Mix Mix
+
+	// With a positive Window, writes insert fresh sequential keys and delete
+	// the oldest key once Window keys are live, modeling a sliding window such
+	// as a session or dedup cache. Keys then only applies to reads. Each
+	// worker slides its own share of the window, so at most Window keys are
+	// live however writes interleave; Window must be at least Workers.
+	Window uint64
+
+	// Keys 0 to Preload-1 are inserted before the measurement starts
+	Preload uint64
+
+	// Total number of measured operations, split across Workers goroutines
+	Ops     int
+	Workers int
+
+	// Seed for the per-worker random sources
+	Seed int64
+}
+
+// Validate checks if the workload is valid
+func (w Workload[K]) Validate() error {
+	if w.Keys == nil || w.Key == nil {
+		return fmt.Errorf("key distribution and key function must be set")
+	}
+	if w.Mix.Read < 0 || w.Mix.Write < 0 || w.Mix.Delete < 0 || w.Mix.Read+w.Mix.Write+w.Mix.Delete == 0 {
+		return fmt.Errorf("operation mix must have non-negative weights with a positive sum")
+	}
+	if z, ok := w.Keys.(zipf); ok && (z.s <= 1 || z.n < 2) {
+		return fmt.Errorf("zipf distribution requires s > 1 and at least 2 keys")
+	}
+	if u, ok := w.Keys.(uniform); ok && u.n == 0 {
+		return fmt.Errorf("uniform distribution requires at least 1 key")
+	}
+	if w.Ops <= 0 || w.Workers <= 0 {
+		return fmt.Errorf("ops and workers must be positive")
+	}
+	if w.Window > 0 && w.Window < uint64(w.Workers) {
+		return fmt.Errorf("window must be at least the number of workers")
+	}
+	return nil
+}
+
+// Target is the map a workload runs against
+type Target[K comparable] interface {
+	Get(key K) bool
+	Set(key K, value int)
+	Delete(key K)
+}
+
+type shrinkMapTarget[K comparable] struct {
+	sm *shrinkmap.ShrinkableMap[K, int]
+}
+
+// ShrinkMap returns a target running against sm
+func ShrinkMap[K comparable](sm *shrinkmap.ShrinkableMap[K, int]) Target[K] {
+	return shrinkMapTarget[K]{sm}
+}
+
+func (t shrinkMapTarget[K]) Get(key K) bool {
+	_, ok := t.sm.Get(key)
+	return ok
+}
+
+func (t shrinkMapTarget[K]) Set(key K, value int) {
+	t.sm.Set(key, value)
+}
+
+func (t shrinkMapTarget[K]) Delete(key K) {
+	t.sm.Delete(key)
+}
+
+// Report summarizes a run
+type Report struct {
+	Workload string
+	Target   string
+
+	Ops       int
+	Reads     int
+	Hits      int // reads that found their key
+	Writes    int
+	Deletes   int
+	Duration  time.Duration
+	OpsPerSec float64
+
+	// Allocations during the measured operations, from runtime.MemStats
+	Allocs     uint64
+	AllocBytes uint64
+
+	// Heap in use after the run and a forced garbage collection
+	HeapInuse uint64
+
+	// Resident set size of the process after the run; set by Compare
+	RSS uint64
+}
+
+// HitRate returns the fraction of reads that found their key
+func (r Report) HitRate() float64 {
+	if r.Reads == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(r.Reads)
+}
+
+// Run preloads the target, performs the workload's operations and reports
+// throughput and allocations. name identifies the target in the report.
+func Run[K comparable](name string, target Target[K], w Workload[K]) (Report, error) {
+	if err := w.Validate(); err != nil {
+		return Report{}, err
+	}
+	// Workers only delete keys from their own share of the window, so a key
+	// is never deleted before the worker inserting it has stored it
+	rings := make([]*windowRing, w.Workers)
+	if w.Window > 0 {
+		for worker := range rings {
+			share := w.Window / uint64(w.Workers)
+			if uint64(worker) < w.Window%uint64(w.Workers) {
+				share++
+			}
+			rings[worker] = newWindowRing(share)
+		}
+	}
+	for i := uint64(0); i < w.Preload; i++ {
+		target.Set(w.Key(i), int(i))
+		if w.Window > 0 {
+			if old, full := rings[i%uint64(w.Workers)].push(i); full {
+				target.Delete(w.Key(old))
+			}
+		}
+	}
+
+	var window atomic.Uint64
+	window.Store(w.Preload)
+	total := w.Mix.Read + w.Mix.Write + w.Mix.Delete
+	counts := make([]Report, w.Workers)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < w.Workers; worker++ {
+		ops := w.Ops / w.Workers
+		if worker < w.Ops%w.Workers {
+			ops++
+		}
+		wg.Add(1)
+		go func(c *Report, r *rand.Rand, ring *windowRing, ops int) {
+			defer wg.Done()
+			next := w.Keys.Sampler(r)
+			for i := 0; i < ops; i++ {
+				switch op := r.Intn(total); {
+				case op < w.Mix.Read:
+					c.Reads++
+					if target.Get(w.Key(next())) {
+						c.Hits++
+					}
+				case op < w.Mix.Read+w.Mix.Write:
+					c.Writes++
+					if w.Window == 0 {
+						target.Set(w.Key(next()), i)
+						continue
+					}
+					k := window.Add(1) - 1
+					target.Set(w.Key(k), i)
+					if old, full := ring.push(k); full {
+						target.Delete(w.Key(old))
+					}
+				default:
+					c.Deletes++
+					target.Delete(w.Key(next()))
+				}
+			}
+		}(&counts[worker], rand.New(rand.NewSource(w.Seed+int64(worker))), rings[worker], ops)
+	}
+	wg.Wait()
+
+	report := Report{Workload: w.Name, Target: name, Ops: w.Ops, Duration: time.Since(start)}
+	runtime.ReadMemStats(&after)
+	runtime.GC()
+	var settled runtime.MemStats
+	runtime.ReadMemStats(&settled)
+
+	for _, c := range counts {
+		report.Reads += c.Reads
+		report.Hits += c.Hits
+		report.Writes += c.Writes
+		report.Deletes += c.Deletes
+	}
+	report.OpsPerSec = float64(report.Ops) / report.Duration.Seconds()
+	report.Allocs = after.Mallocs - before.Mallocs
+	report.AllocBytes = after.TotalAlloc - before.TotalAlloc
+	report.HeapInuse = settled.HeapInuse
+	return report, nil
+}
+
+// WriteReports writes the reports to w as an aligned table
+func WriteReports(w io.Writer, reports ...Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "workload\ttarget\tops/sec\thit rate\tallocs/op\tbytes/op\theap inuse\trss\t")
+	for _, r := range reports {
+		rss := "-"
+		if r.RSS > 0 {
+			rss = strconv.FormatUint(r.RSS, 10)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.0f\t%.1f%%\t%.2f\t%.1f\t%d\t%s\t\n",
+			r.Workload, r.Target, r.OpsPerSec, r.HitRate()*100,
+			float64(r.Allocs)/float64(r.Ops), float64(r.AllocBytes)/float64(r.Ops), r.HeapInuse, rss)
+	}
+	return tw.Flush()
+}
+
+// windowRing holds the keys of a worker's share of a sliding window
+type windowRing struct {
+	keys    []uint64
+	head, n int
+}
+
+func newWindowRing(size uint64) *windowRing {
+	return &windowRing{keys: make([]uint64, size)}
+}
+
+// push adds key k and returns the oldest key if it left the window
+func (r *windowRing) push(k uint64) (uint64, bool) {
+	if r.n < len(r.keys) {
+		r.keys[(r.head+r.n)%len(r.keys)] = k
+		r.n++
+		return 0, false
+	}
+	old := r.keys[r.head]
+	r.keys[r.head] = k
+	r.head = (r.head + 1) % len(r.keys)
+	return old, true
+}
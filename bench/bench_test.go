@@ -0,0 +1,142 @@
+package bench
+
+import (
+	"bytes"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func TestRun(t *testing.T) {
+	newTarget := func(t *testing.T) (*shrinkmap.ShrinkableMap[string, int], Target[string]) {
+		sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+		t.Cleanup(sm.Stop)
+		return sm, ShrinkMap(sm)
+	}
+	key := func(i uint64) string { return strconv.FormatUint(i, 10) }
+
+	t.Run("Mix", func(t *testing.T) {
+		sm, target := newTarget(t)
+		report, err := Run("shrinkmap", target, Workload[string]{
+			Name:    "read-heavy",
+			Keys:    Zipf(1000, 1.1),
+			Key:     key,
+			Mix:     ReadHeavy,
+			Preload: 1000,
+			Ops:     10000,
+			Workers: 4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report.Reads+report.Writes+report.Deletes != 10000 || report.Deletes != 0 {
+			t.Errorf("Unexpected operation counts: %+v", report)
+		}
+		if report.Reads < 8500 || report.HitRate() != 1 {
+			t.Errorf("Expected about 90%% reads all hitting preloaded keys, got %+v", report)
+		}
+		if sm.Len() != 1000 || report.OpsPerSec <= 0 {
+			t.Errorf("Expected 1000 entries and positive throughput, got %d, %v", sm.Len(), report.OpsPerSec)
+		}
+	})
+
+	t.Run("Sliding Window", func(t *testing.T) {
+		sm, target := newTarget(t)
+		_, err := Run("shrinkmap", target, Workload[string]{
+			Keys:    Uniform(100),
+			Key:     key,
+			Mix:     Mix{Write: 1},
+			Window:  100,
+			Preload: 150,
+			Ops:     5000,
+			Workers: 3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sm.Len() != 100 {
+			t.Errorf("Expected the window to keep 100 entries, got %d", sm.Len())
+		}
+	})
+
+	t.Run("Report", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteReports(&buf, Report{Workload: "w", Target: "shrinkmap", Ops: 10, Reads: 4, Hits: 2, OpsPerSec: 100})
+		if out := buf.String(); !strings.Contains(out, "shrinkmap") || !strings.Contains(out, "50.0%") {
+			t.Errorf("Unexpected report:\n%s", out)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, target := newTarget(t)
+		for _, w := range []Workload[string]{
+			{Key: key, Mix: Balanced, Ops: 1, Workers: 1},
+			{Keys: Uniform(10), Key: key, Ops: 1, Workers: 1},
+			{Keys: Zipf(10, 1), Key: key, Mix: Balanced, Ops: 1, Workers: 1},
+			{Keys: Uniform(10), Key: key, Mix: Balanced, Workers: 1},
+			{Keys: Uniform(10), Key: key, Mix: Balanced, Window: 1, Ops: 1, Workers: 2},
+		} {
+			if _, err := Run("shrinkmap", target, w); err == nil {
+				t.Errorf("Expected error for %+v", w)
+			}
+		}
+	})
+}
+
+func TestDistributions(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	next := Zipf(1000, 1.5).Sampler(r)
+	hot := 0
+	for i := 0; i < 10000; i++ {
+		if next() < 10 {
+			hot++
+		}
+	}
+	if hot < 5000 {
+		t.Errorf("Expected most zipf draws among the 10 hottest keys, got %d", hot)
+	}
+
+	seq := Sequential()
+	a, b := seq.Sampler(r), seq.Sampler(r)
+	if a() != 0 || b() != 1 || a() != 2 {
+		t.Error("Expected sequential keys shared across samplers")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	w := Workload[uint64]{
+		Name:    "balanced",
+		Keys:    Uniform(500),
+		Key:     func(i uint64) uint64 { return i },
+		Mix:     Churning,
+		Preload: 500,
+		Ops:     5000,
+		Workers: 2,
+	}
+	candidates := append([]Candidate[uint64]{ShrinkMapCandidate[uint64]("shrinkmap", shrinkmap.DefaultConfig())}, Baselines[uint64]()...)
+
+	reports, err := Compare(w, candidates...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("Expected 3 reports, got %d", len(reports))
+	}
+	for i, r := range reports {
+		if r.Target != candidates[i].Name || r.Ops != 5000 || r.RSS == 0 {
+			t.Errorf("Unexpected report: %+v", r)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := WriteReports(&buf, reports...); err != nil {
+		t.Fatal(err)
+	}
+	if out := buf.String(); !strings.Contains(out, "sync.Map") || !strings.Contains(out, "locked map") {
+		t.Errorf("Expected every target in the report:\n%s", out)
+	}
+}
@@ -0,0 +1,127 @@
+package bench
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+type syncMapTarget[K comparable] struct {
+	m sync.Map
+}
+
+// SyncMap returns a target backed by a sync.Map
+func SyncMap[K comparable]() Target[K] {
+	return &syncMapTarget[K]{}
+}
+
+func (t *syncMapTarget[K]) Get(key K) bool {
+	_, ok := t.m.Load(key)
+	return ok
+}
+
+func (t *syncMapTarget[K]) Set(key K, value int) {
+	t.m.Store(key, value)
+}
+
+func (t *syncMapTarget[K]) Delete(key K) {
+	t.m.Delete(key)
+}
+
+type lockedMapTarget[K comparable] struct {
+	mu sync.RWMutex
+	m  map[K]int
+}
+
+// LockedMap returns a target backed by a built-in map guarded by a sync.RWMutex
+func LockedMap[K comparable]() Target[K] {
+	return &lockedMapTarget[K]{m: make(map[K]int)}
+}
+
+func (t *lockedMapTarget[K]) Get(key K) bool {
+	t.mu.RLock()
+	_, ok := t.m[key]
+	t.mu.RUnlock()
+	return ok
+}
+
+func (t *lockedMapTarget[K]) Set(key K, value int) {
+	t.mu.Lock()
+	t.m[key] = value
+	t.mu.Unlock()
+}
+
+func (t *lockedMapTarget[K]) Delete(key K) {
+	t.mu.Lock()
+	delete(t.m, key)
+	t.mu.Unlock()
+}
+
+// Candidate is a target compared by Compare. New is called once per
+// comparison so every candidate starts empty; the returned function, if not
+// nil, releases the target afterwards.
+type Candidate[K comparable] struct {
+	Name string
+	New  func() (Target[K], func())
+}
+
+// ShrinkMapCandidate returns a candidate running against a new ShrinkableMap
+// created with config
+func ShrinkMapCandidate[K comparable](name string, config shrinkmap.Config) Candidate[K] {
+	return Candidate[K]{Name: name, New: func() (Target[K], func()) {
+		sm := shrinkmap.New[K, int](config)
+		return ShrinkMap(sm), sm.Stop
+	}}
+}
+
+// Baselines returns candidates for sync.Map and a mutex-protected map
+func Baselines[K comparable]() []Candidate[K] {
+	return []Candidate[K]{
+		{Name: "sync.Map", New: func() (Target[K], func()) { return SyncMap[K](), nil }},
+		{Name: "locked map", New: func() (Target[K], func()) { return LockedMap[K](), nil }},
+	}
+}
+
+// Compare runs the workload against each candidate in turn and returns one
+// report per candidate, including the process RSS after each run. Candidates
+// are kept alive until their report is taken, so the heap and RSS figures
+// include the data they retain.
+func Compare[K comparable](w Workload[K], candidates ...Candidate[K]) ([]Report, error) {
+	reports := make([]Report, 0, len(candidates))
+	for _, c := range candidates {
+		target, release := c.New()
+		report, err := Run(c.Name, target, w)
+		if err != nil {
+			if release != nil {
+				release()
+			}
+			return reports, err
+		}
+		report.RSS = rss()
+		runtime.KeepAlive(target)
+		if release != nil {
+			release()
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// rss returns the resident set size of the process. Where /proc is not
+// available it falls back to the memory obtained from the OS by the runtime.
+func rss() uint64 {
+	if statm, err := os.ReadFile("/proc/self/statm"); err == nil {
+		if fields := bytes.Fields(statm); len(fields) > 1 {
+			if pages, err := strconv.ParseUint(string(fields[1]), 10, 64); err == nil {
+				return pages * uint64(os.Getpagesize())
+			}
+		}
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.Sys
+}
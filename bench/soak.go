@@ -0,0 +1,266 @@
+package bench
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// ErrHeapNotReclaimed is returned by RunSoak when the heap did not return
+// below the threshold after a cycle's deletions
+var ErrHeapNotReclaimed = errors.New("heap not reclaimed after deletions")
+
+// Soak describes a long-running churn test checking that memory is given
+// back after deletions. Each cycle inserts Entries keys, deletes all but
+// Retain of them and then waits for the heap to settle.
+type Soak[K comparable] struct {
+	// Name identifying the soak test in reports
+	Name string
+
+	// Converts a key index into a key; every cycle uses fresh indexes
+	Key func(index uint64) K
+
+	// Size in bytes of the value stored with each key
+	ValueSize int
+
+	// Keys inserted per cycle and how many of them survive the cycle
+	Entries uint64
+	Retain  uint64
+
+	// Number of cycles and goroutines inserting and deleting keys
+	Cycles  int
+	Workers int
+
+	// After a cycle's deletions the heap in use may exceed the heap in use
+	// before the first cycle by at most MaxHeapGrowth bytes, plus the values
+	// of the retained keys
+	MaxHeapGrowth uint64
+
+	// How long to wait for the heap to drop below the threshold
+	Settle time.Duration
+
+	// Interval between samples of runtime.MemStats
+	SampleInterval time.Duration
+}
+
+// Validate checks if the soak test is valid
+func (s Soak[K]) Validate() error {
+	if s.Key == nil {
+		return fmt.Errorf("key function must be set")
+	}
+	if s.Entries == 0 || s.Retain >= s.Entries {
+		return fmt.Errorf("entries must be positive and greater than retain")
+	}
+	if s.Cycles <= 0 || s.Workers <= 0 {
+		return fmt.Errorf("cycles and workers must be positive")
+	}
+	if s.ValueSize < 0 {
+		return fmt.Errorf("value size must not be negative")
+	}
+	if s.MaxHeapGrowth == 0 {
+		return fmt.Errorf("max heap growth must be positive")
+	}
+	if s.Settle <= 0 || s.SampleInterval <= 0 {
+		return fmt.Errorf("settle time and sample interval must be positive")
+	}
+	return nil
+}
+
+// SoakSample is a point-in-time reading taken during a soak test
+type SoakSample struct {
+	Elapsed   time.Duration `json:"elapsed"`
+	Cycle     int           `json:"cycle"`
+	Len       int64         `json:"len"`
+	HeapAlloc uint64        `json:"heap_alloc"`
+	HeapInuse uint64        `json:"heap_inuse"`
+	Sys       uint64        `json:"sys"`
+	NumGC     uint32        `json:"num_gc"`
+}
+
+// SoakCycle summarizes one cycle of a soak test
+type SoakCycle struct {
+	Cycle int `json:"cycle"`
+
+	// Heap in use after the inserts and after the heap settled
+	PeakHeap    uint64 `json:"peak_heap"`
+	SettledHeap uint64 `json:"settled_heap"`
+
+	// Heap in use the cycle had to return below
+	Limit uint64 `json:"limit"`
+
+	// Time from the last deletion until the heap dropped below Limit, or
+	// the full settle time if it never did
+	SettleTime time.Duration `json:"settle_time"`
+
+	// Shrinks performed by the map during the cycle
+	Shrinks   int64 `json:"shrinks"`
+	Reclaimed bool  `json:"reclaimed"`
+}
+
+// SoakReport is the outcome of a soak test. It is meant to be kept as a test
+// artifact, see WriteJSON.
+type SoakReport struct {
+	Name     string        `json:"name"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+
+	// Heap in use before the first cycle
+	Baseline uint64 `json:"baseline"`
+
+	Cycles  []SoakCycle  `json:"cycles"`
+	Samples []SoakSample `json:"samples"`
+	Passed  bool         `json:"passed"`
+}
+
+// WriteJSON writes the report to w as indented JSON
+func (r SoakReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// RunSoak runs the soak test against sm and returns its report. The harness
+// never shrinks the map itself, so sm should be configured with automatic
+// shrinking and intervals short enough to shrink within s.Settle. The error
+// wraps ErrHeapNotReclaimed if any cycle failed to reclaim its memory; the
+// report is complete either way.
+func RunSoak[K comparable](sm *shrinkmap.ShrinkableMap[K, []byte], s Soak[K]) (SoakReport, error) {
+	if err := s.Validate(); err != nil {
+		return SoakReport{}, err
+	}
+
+	report := SoakReport{Name: s.Name, Start: time.Now(), Passed: true}
+	report.Baseline = settledHeap()
+
+	var (
+		mu    sync.Mutex
+		cycle int
+		done  = make(chan struct{})
+		wg    sync.WaitGroup
+	)
+	sample := func() {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		mu.Lock()
+		report.Samples = append(report.Samples, SoakSample{
+			Elapsed:   time.Since(report.Start),
+			Cycle:     cycle,
+			Len:       sm.Len(),
+			HeapAlloc: stats.HeapAlloc,
+			HeapInuse: stats.HeapInuse,
+			Sys:       stats.Sys,
+			NumGC:     stats.NumGC,
+		})
+		mu.Unlock()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(s.SampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				sample()
+			}
+		}
+	}()
+
+	var failed []int
+	for c := 0; c < s.Cycles; c++ {
+		mu.Lock()
+		cycle = c
+		mu.Unlock()
+
+		result := runSoakCycle(sm, s, c, report.Baseline)
+		report.Cycles = append(report.Cycles, result)
+		if !result.Reclaimed {
+			failed = append(failed, c)
+		}
+	}
+	close(done)
+	wg.Wait()
+	sample()
+
+	report.Duration = time.Since(report.Start)
+	if len(failed) > 0 {
+		report.Passed = false
+		return report, fmt.Errorf("%w: cycles %v", ErrHeapNotReclaimed, failed)
+	}
+	return report, nil
+}
+
+// runSoakCycle inserts and deletes one cycle's keys and waits for the heap
+// to settle below the cycle's limit
+func runSoakCycle[K comparable](sm *shrinkmap.ShrinkableMap[K, []byte], s Soak[K], c int, baseline uint64) SoakCycle {
+	result := SoakCycle{Cycle: c}
+	metrics := sm.GetMetrics()
+	shrinks := metrics.TotalShrinks()
+
+	// Every cycle uses fresh indexes, so keys retained by earlier cycles
+	// accumulate and are accounted for in the limit
+	first := uint64(c) * s.Entries
+	forEachKey(s, func(i uint64) {
+		sm.Set(s.Key(first+i), make([]byte, s.ValueSize))
+	})
+	result.PeakHeap = settledHeap()
+
+	forEachKey(s, func(i uint64) {
+		if i >= s.Retain {
+			sm.Delete(s.Key(first + i))
+		}
+	})
+
+	retained := uint64(c+1) * s.Retain * uint64(s.ValueSize)
+	result.Limit = baseline + s.MaxHeapGrowth + retained
+	start := time.Now()
+	for {
+		result.SettledHeap = settledHeap()
+		result.SettleTime = time.Since(start)
+		if result.SettledHeap <= result.Limit {
+			result.Reclaimed = true
+			break
+		}
+		if result.SettleTime >= s.Settle {
+			break
+		}
+		time.Sleep(s.SampleInterval)
+	}
+
+	metrics = sm.GetMetrics()
+	result.Shrinks = metrics.TotalShrinks() - shrinks
+	return result
+}
+
+// forEachKey calls fn with every key index of a cycle, split across the
+// soak test's workers
+func forEachKey[K comparable](s Soak[K], fn func(i uint64)) {
+	var wg sync.WaitGroup
+	workers := uint64(s.Workers)
+	for w := uint64(0); w < workers; w++ {
+		wg.Add(1)
+		go func(w uint64) {
+			defer wg.Done()
+			for i := w; i < s.Entries; i += workers {
+				fn(i)
+			}
+		}(w)
+	}
+	wg.Wait()
+}
+
+// settledHeap returns the heap in use after a forced garbage collection
+func settledHeap() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
@@ -0,0 +1,83 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func TestRunSoak(t *testing.T) {
+	soak := Soak[string]{
+		Name:           "churn",
+		Key:            func(i uint64) string { return "key-" + strconv.FormatUint(i, 10) },
+		ValueSize:      64,
+		Entries:        50_000,
+		Retain:         100,
+		Cycles:         3,
+		Workers:        4,
+		MaxHeapGrowth:  1 << 20,
+		Settle:         2 * time.Second,
+		SampleInterval: 10 * time.Millisecond,
+	}
+
+	t.Run("Reclaimed", func(t *testing.T) {
+		sm := shrinkmap.New[string, []byte](shrinkmap.DefaultConfig().
+			WithShrinkInterval(10 * time.Millisecond).
+			WithMinShrinkInterval(10 * time.Millisecond))
+		defer sm.Stop()
+
+		report, err := RunSoak(sm, soak)
+		if err != nil {
+			t.Fatalf("Soak failed: %v\n%+v", err, report.Cycles)
+		}
+		if !report.Passed || len(report.Cycles) != 3 || len(report.Samples) == 0 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		for _, c := range report.Cycles {
+			if c.Shrinks == 0 || c.PeakHeap <= c.SettledHeap {
+				t.Errorf("Expected the map to shrink and the heap to drop in cycle %d: %+v", c.Cycle, c)
+			}
+		}
+		if sm.Len() != 300 {
+			t.Errorf("Expected 300 retained entries, got %d", sm.Len())
+		}
+
+		var buf bytes.Buffer
+		if err := report.WriteJSON(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var decoded SoakReport
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Cycles) != 3 {
+			t.Errorf("Expected the report to round-trip through JSON, got %v", err)
+		}
+	})
+
+	t.Run("Not Reclaimed", func(t *testing.T) {
+		sm := shrinkmap.New[string, []byte](shrinkmap.DefaultConfig().WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+
+		s := soak
+		s.Cycles = 1
+		s.Settle = 50 * time.Millisecond
+		report, err := RunSoak(sm, s)
+		if !errors.Is(err, ErrHeapNotReclaimed) || report.Passed {
+			t.Errorf("Expected ErrHeapNotReclaimed without shrinking, got %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		sm := shrinkmap.New[string, []byte](shrinkmap.DefaultConfig())
+		defer sm.Stop()
+
+		s := soak
+		s.Retain = s.Entries
+		if _, err := RunSoak(sm, s); err == nil {
+			t.Error("Expected an error when every key is retained")
+		}
+	})
+}
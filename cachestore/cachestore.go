@@ -0,0 +1,118 @@
+// Package cachestore adapts a ShrinkableMap[string, []byte] to the byte-oriented
+// cache interface shared by groupcache- and gocache-style stores, so shrinkmap
+// can be plugged in wherever such a store is expected.
+package cachestore
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// ErrCacheMiss is returned by Get when the key is absent or expired
+var ErrCacheMiss = errors.New("cachestore: cache miss")
+
+// Cache is the byte-oriented cache interface implemented by Store
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+// Store implements Cache on top of a ShrinkableMap.
+// Expiration deadlines are kept in a second ShrinkableMap; expired entries are
+// removed lazily on Get and in bulk by DeleteExpired.
+type Store struct {
+	data     *shrinkmap.ShrinkableMap[string, []byte]
+	deadline *shrinkmap.ShrinkableMap[string, time.Time]
+	now      func() time.Time
+}
+
+var _ Cache = (*Store)(nil)
+
+// New creates a Store backed by data. The deadline map used for TTLs is
+// created with the same configuration as data and is stopped by Stop.
+func New(data *shrinkmap.ShrinkableMap[string, []byte], config shrinkmap.Config) *Store {
+	return &Store{
+		data:     data,
+		deadline: shrinkmap.New[string, time.Time](config),
+		now:      time.Now,
+	}
+}
+
+// Get returns the value stored for key, or ErrCacheMiss
+func (s *Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if s.expired(key) {
+		s.deleteIfExpired(key)
+		return nil, ErrCacheMiss
+	}
+	value, ok := s.data.Get(key)
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return value, nil
+}
+
+// Set stores value for key. A positive ttl expires the entry after that
+// duration; zero or negative means the entry never expires.
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if ttl > 0 {
+		if err := s.deadline.SetChecked(key, s.now().Add(ttl)); err != nil {
+			return err
+		}
+	} else {
+		s.deadline.Delete(key)
+	}
+	return s.data.SetChecked(key, value)
+}
+
+// Delete removes key from the cache
+func (s *Store) Delete(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.data.Delete(key)
+	s.deadline.Delete(key)
+	return nil
+}
+
+// DeleteExpired removes all expired entries and returns how many were removed
+func (s *Store) DeleteExpired() int {
+	now := s.now()
+	removed := 0
+	for _, kv := range s.deadline.Snapshot() {
+		if !now.Before(kv.Value) && s.deleteIfExpired(kv.Key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Stop stops the internal deadline map; the data map is owned by the caller
+func (s *Store) Stop() {
+	s.deadline.Stop()
+}
+
+func (s *Store) expired(key string) bool {
+	deadline, ok := s.deadline.Get(key)
+	return ok && !s.now().Before(deadline)
+}
+
+// deleteIfExpired removes key if its deadline has passed, re-checking so a
+// concurrent Set with a fresh TTL is not lost
+func (s *Store) deleteIfExpired(key string) bool {
+	if !s.expired(key) {
+		return false
+	}
+	s.data.Delete(key)
+	s.deadline.Delete(key)
+	return true
+}
@@ -0,0 +1,92 @@
+package cachestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func newTestStore(t *testing.T) (*Store, *time.Time) {
+	config := shrinkmap.DefaultConfig()
+	data := shrinkmap.New[string, []byte](config)
+	store := New(data, config)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	t.Cleanup(func() {
+		store.Stop()
+		data.Stop()
+	})
+	return store, &now
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get And Set", func(t *testing.T) {
+		store, _ := newTestStore(t)
+
+		if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("Expected ErrCacheMiss, got %v", err)
+		}
+		if err := store.Set(ctx, "a", []byte("value"), 0); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if v, err := store.Get(ctx, "a"); err != nil || string(v) != "value" {
+			t.Errorf("Expected value, got %q, err=%v", v, err)
+		}
+		if err := store.Delete(ctx, "a"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, err := store.Get(ctx, "a"); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("Expected ErrCacheMiss after delete, got %v", err)
+		}
+	})
+
+	t.Run("TTL Expiry", func(t *testing.T) {
+		store, now := newTestStore(t)
+
+		_ = store.Set(ctx, "short", []byte("1"), time.Minute)
+		_ = store.Set(ctx, "long", []byte("2"), time.Hour)
+		_ = store.Set(ctx, "forever", []byte("3"), 0)
+
+		*now = now.Add(2 * time.Minute)
+		if _, err := store.Get(ctx, "short"); !errors.Is(err, ErrCacheMiss) {
+			t.Errorf("Expected expired entry to miss, got %v", err)
+		}
+		if store.data.Contains("short") {
+			t.Error("Expired entry should be removed on Get")
+		}
+
+		*now = now.Add(2 * time.Hour)
+		if removed := store.DeleteExpired(); removed != 1 {
+			t.Errorf("Expected 1 expired entry removed, got %d", removed)
+		}
+		if v, err := store.Get(ctx, "forever"); err != nil || string(v) != "3" {
+			t.Errorf("Entry without TTL should not expire, got %q, err=%v", v, err)
+		}
+	})
+
+	t.Run("Reset TTL", func(t *testing.T) {
+		store, now := newTestStore(t)
+
+		_ = store.Set(ctx, "a", []byte("1"), time.Minute)
+		_ = store.Set(ctx, "a", []byte("2"), 0)
+		*now = now.Add(time.Hour)
+		if v, err := store.Get(ctx, "a"); err != nil || string(v) != "2" {
+			t.Errorf("Setting without TTL should clear the deadline, got %q, err=%v", v, err)
+		}
+	})
+
+	t.Run("Canceled Context", func(t *testing.T) {
+		store, _ := newTestStore(t)
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+
+		if err := store.Set(canceled, "a", nil, 0); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
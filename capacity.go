@@ -0,0 +1,43 @@
+package shrinkmap
+
+import "math/bits"
+
+// TargetCapacityFunc returns the number of entries a shrink allocates the new
+// map for, given the live entries and the largest number of entries the
+// current map has been allocated for or has held (see Stats.PeakLen).
+// Results below live are raised to live.
+type TargetCapacityFunc func(live, peak int64, config Config) int
+
+// GrowthFactorCapacity allocates CapacityGrowthFactor times the live entries,
+// but at least InitialCapacity. It is the default policy.
+func GrowthFactorCapacity(live, _ int64, config Config) int {
+	return max(int(float64(live)*config.CapacityGrowthFactor), config.InitialCapacity)
+}
+
+// PowerOfTwoCapacity rounds the GrowthFactorCapacity up to the next power of
+// two, matching how maps grow, so a shrunk map does not regrow at the first
+// few inserts
+func PowerOfTwoCapacity(live, peak int64, config Config) int {
+	n := GrowthFactorCapacity(live, peak, config)
+	if n <= 1 {
+		return n
+	}
+	return 1 << bits.Len(uint(n-1))
+}
+
+// PeakFractionCapacity returns a policy keeping room for at least fraction
+// of the peak, for maps that are expected to refill to a similar size
+func PeakFractionCapacity(fraction float64) TargetCapacityFunc {
+	return func(live, peak int64, config Config) int {
+		return max(GrowthFactorCapacity(live, peak, config), int(float64(peak)*fraction))
+	}
+}
+
+// shrinkTargetSize returns the capacity a shrink allocates for n entries
+func (sm *ShrinkableMap[K, V]) shrinkTargetSize(n int64) int {
+	policy := sm.config.TargetCapacity
+	if policy == nil {
+		policy = GrowthFactorCapacity
+	}
+	return max(policy(n, sm.sizeHint.Load(), sm.config), int(n))
+}
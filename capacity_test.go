@@ -0,0 +1,65 @@
+package shrinkmap
+
+import "testing"
+
+func TestTargetCapacity(t *testing.T) {
+	config := DefaultConfig()
+
+	t.Run("Policies", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			policy TargetCapacityFunc
+			live   int64
+			peak   int64
+			want   int
+		}{
+			{"Growth Factor", GrowthFactorCapacity, 100, 1000, 120},
+			{"Growth Factor Minimum", GrowthFactorCapacity, 1, 1000, 16},
+			{"Power Of Two", PowerOfTwoCapacity, 100, 1000, 128},
+			{"Power Of Two Exact", PowerOfTwoCapacity, 20, 1000, 32},
+			{"Peak Fraction", PeakFractionCapacity(0.5), 100, 1000, 500},
+			{"Peak Fraction Below Growth", PeakFractionCapacity(0.1), 100, 500, 120},
+		}
+		for _, tt := range tests {
+			if got := tt.policy(tt.live, tt.peak, config); got != tt.want {
+				t.Errorf("%s: expected %d, got %d", tt.name, tt.want, got)
+			}
+		}
+	})
+
+	t.Run("Used By Shrink", func(t *testing.T) {
+		var gotLive, gotPeak int64
+		sm := New[int, int](config.WithAutoShrinkEnabled(false).WithTargetCapacity(func(live, peak int64, _ Config) int {
+			gotLive, gotPeak = live, peak
+			return 1000
+		}))
+		defer sm.Stop()
+
+		for i := 0; i < 500; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 400; i++ {
+			sm.Delete(i)
+		}
+		sm.ForceShrink()
+		if gotLive != 100 || gotPeak != 500 {
+			t.Errorf("Expected policy called with 100 live of 500 peak, got %d of %d", gotLive, gotPeak)
+		}
+		if stats := sm.Stats(); stats.PeakLen != 1000 {
+			t.Errorf("Expected the shrunk map to be allocated for 1000 entries, got %d", stats.PeakLen)
+		}
+	})
+
+	t.Run("Never Below Live", func(t *testing.T) {
+		sm := New[int, int](config.WithAutoShrinkEnabled(false).WithTargetCapacity(func(int64, int64, Config) int { return 0 }))
+		defer sm.Stop()
+
+		for i := 0; i < 50; i++ {
+			sm.Set(i, i)
+		}
+		sm.ForceShrink()
+		if stats := sm.Stats(); stats.PeakLen != 50 {
+			t.Errorf("Expected capacity raised to the 50 live entries, got %d", stats.PeakLen)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package shrinkmap
+
+import "fmt"
+
+// CloneFunc returns a deep copy of a value for Config.CopyOnRead and
+// Config.CopyOnWrite. It must return a value of the map's value type.
+type CloneFunc func(value any) any
+
+// Clone adapts a typed copy function to a CloneFunc, e.g.
+// Clone(func(s []int) []int { return slices.Clone(s) })
+func Clone[V any](fn func(V) V) CloneFunc {
+	return func(value any) any { return fn(value.(V)) }
+}
+
+// cloneValue copies value with Config.CloneValue
+func (sm *ShrinkableMap[K, V]) cloneValue(value V) (V, error) {
+	result := sm.config.CloneValue(value)
+	cloned, ok := result.(V)
+	if !ok {
+		return value, fmt.Errorf("clone returned %T, want %T", result, value)
+	}
+	return cloned, nil
+}
+
+// copyOnRead returns a copy of a value about to be handed to a caller if
+// Config.CopyOnRead is set. A clone of the wrong type is recorded as an error
+// and the stored value returned instead.
+func (sm *ShrinkableMap[K, V]) copyOnRead(value V) V {
+	if !sm.config.CopyOnRead {
+		return value
+	}
+	cloned, err := sm.cloneValue(value)
+	if err != nil {
+		sm.metrics.RecordError(err, "")
+	}
+	return cloned
+}
+
+// copyEntriesOnRead replaces the values of entries by copies if Config.CopyOnRead is set
+func (sm *ShrinkableMap[K, V]) copyEntriesOnRead(entries []KeyValue[K, V]) {
+	if !sm.config.CopyOnRead {
+		return
+	}
+	for i := range entries {
+		entries[i].Value = sm.copyOnRead(entries[i].Value)
+	}
+}
@@ -0,0 +1,132 @@
+package shrinkmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValueCopies(t *testing.T) {
+	clone := Clone(func(s []int) []int { return slices.Clone(s) })
+
+	t.Run("Copy On Write", func(t *testing.T) {
+		sm := New[string, []int](DefaultConfig().WithValueCopies(true, false, clone))
+		defer sm.Stop()
+
+		value := []int{1, 2, 3}
+		sm.Set("a", value)
+		value[0] = 100
+		if got, _ := sm.Get("a"); got[0] != 1 {
+			t.Errorf("Expected the stored value to be unaffected, got %v", got)
+		}
+
+		batch := []int{4}
+		sm.ApplyBatch(BatchOperations[string, []int]{Operations: []BatchOperation[string, []int]{
+			{Type: BatchSet, Key: "b", Value: batch},
+		}})
+		batch[0] = 400
+		if got, _ := sm.Get("b"); got[0] != 4 {
+			t.Errorf("Expected batch writes to be copied, got %v", got)
+		}
+
+		ops := []BatchOperation[string, []int]{{Type: BatchSet, Key: "c", Value: []int{5}}}
+		sm.ApplyBatch(BatchOperations[string, []int]{Operations: ops})
+		ops[0].Value[0] = 500
+		if got, _ := sm.Get("c"); got[0] != 5 {
+			t.Errorf("Expected the caller's batch to hold no reference to the stored copy, got %v", got)
+		}
+	})
+
+	t.Run("Copy On Read", func(t *testing.T) {
+		sm := New[string, []int](DefaultConfig().WithValueCopies(false, true, clone))
+		defer sm.Stop()
+		sm.Set("a", []int{1, 2, 3})
+
+		got, _ := sm.Get("a")
+		got[0] = 100
+		snapshot := sm.Snapshot()
+		snapshot[0].Value[1] = 200
+		it := sm.NewIterator()
+		for it.Next() {
+			_, v := it.Get()
+			v[2] = 300
+		}
+		it.Release()
+		entries, _ := sm.Scan(ScanFilter{})
+		entries[0].Value[0] = 400
+
+		if got, _ := sm.Get("a"); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("Expected the stored value to be unaffected, got %v", got)
+		}
+	})
+
+	t.Run("Copy On Read Other Paths", func(t *testing.T) {
+		sm := New[string, []int](DefaultConfig().WithValueCopies(false, true, clone).WithHistory(time.Hour, 0))
+		defer sm.Stop()
+		events := make(chan ChangeEvent[string, []int], 1)
+		defer sm.Watch(events, nil)()
+		sm.Set("a", []int{1, 2, 3})
+		now := time.Now()
+
+		event := <-events
+		event.Value[0] = 100
+		c := sm.NewLockedCursor(0)
+		for c.Next() {
+			c.Value()[1] = 200
+		}
+		c.Close()
+		sample := sm.SampleWeighted(1, func(string, []int) float64 { return 1 })
+		sample[0].Value[2] = 300
+		old, _, _ := sm.GetAt("a", now)
+		old[0] = 400
+		snapshot, _ := sm.SnapshotAt(now)
+		snapshot[0].Value[1] = 500
+
+		if got, _ := sm.Get("a"); !slices.Equal(got, []int{1, 2, 3}) {
+			t.Errorf("Expected the stored value to be unaffected, got %v", got)
+		}
+		if old, _, _ := sm.GetAt("a", now); !slices.Equal(old, []int{1, 2, 3}) {
+			t.Errorf("Expected the retained value to be unaffected, got %v", old)
+		}
+	})
+
+	t.Run("Wrong Type", func(t *testing.T) {
+		bad := func(any) any { return "not a slice" }
+		sm := New[string, []int](DefaultConfig().WithValueCopies(true, false, bad))
+		defer sm.Stop()
+		var keyErr *KeyError
+		if err := sm.SetChecked("a", []int{1}); !errors.As(err, &keyErr) {
+			t.Errorf("Expected KeyError for mismatched clone, got %v", err)
+		}
+
+		reader := New[string, []int](DefaultConfig().WithValueCopies(false, true, bad))
+		defer reader.Stop()
+		reader.Set("a", []int{1})
+		if got, ok := reader.Get("a"); !ok || got[0] != 1 {
+			t.Errorf("Expected the stored value on clone failure, got %v", got)
+		}
+		metrics := reader.GetMetrics()
+		if metrics.TotalErrors() != 1 {
+			t.Errorf("Expected clone failure to be recorded, got %d errors", metrics.TotalErrors())
+		}
+	})
+
+	t.Run("Linked Map", func(t *testing.T) {
+		lm := NewLinked[string, []int](DefaultConfig().WithValueCopies(true, true, clone))
+		defer lm.Stop()
+
+		value := []int{1}
+		lm.Set("a", value)
+		value[0] = 100
+		if got, _ := lm.Get("a"); got[0] != 1 {
+			t.Errorf("Expected linked map writes to be copied, got %v", got)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := DefaultConfig().WithValueCopies(true, false, nil).Validate(); err == nil {
+			t.Error("Expected error for copies without a clone function")
+		}
+	})
+}
@@ -0,0 +1,64 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Codec serializes single map entries.
+// Persistence, export and network adapters take a Codec so the wire format is a
+// configuration choice; framing of consecutive entries is done by the caller.
+// Subpackages under codec/ provide MessagePack, CBOR and Protobuf implementations.
+type Codec[K comparable, V any] interface {
+	EncodeEntry(key K, value V) ([]byte, error)
+	DecodeEntry(data []byte) (K, V, error)
+}
+
+// GobCodec encodes entries with encoding/gob. It is the default codec used by SaveTo and LoadFrom.
+type GobCodec[K comparable, V any] struct{}
+
+// EncodeEntry encodes the entry as a gob-encoded KeyValue
+func (GobCodec[K, V]) EncodeEntry(key K, value V) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(KeyValue[K, V]{Key: key, Value: value}); err != nil {
+		return nil, fmt.Errorf("gob encode entry: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DecodeEntry decodes an entry produced by EncodeEntry
+func (GobCodec[K, V]) DecodeEntry(data []byte) (K, V, error) {
+	var kv KeyValue[K, V]
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&kv); err != nil {
+		return kv.Key, kv.Value, fmt.Errorf("gob decode entry: %w", err)
+	}
+	return kv.Key, kv.Value, nil
+}
+
+// JSONCodec encodes entries as JSON objects of the form {"key":...,"value":...}
+type JSONCodec[K comparable, V any] struct{}
+
+type jsonEntry[K comparable, V any] struct {
+	Key   K `json:"key"`
+	Value V `json:"value"`
+}
+
+// EncodeEntry encodes the entry as a single-line JSON object
+func (JSONCodec[K, V]) EncodeEntry(key K, value V) ([]byte, error) {
+	data, err := json.Marshal(jsonEntry[K, V]{Key: key, Value: value})
+	if err != nil {
+		return nil, fmt.Errorf("json encode entry: %w", err)
+	}
+	return data, nil
+}
+
+// DecodeEntry decodes an entry produced by EncodeEntry
+func (JSONCodec[K, V]) DecodeEntry(data []byte) (K, V, error) {
+	var e jsonEntry[K, V]
+	if err := json.Unmarshal(data, &e); err != nil {
+		return e.Key, e.Value, fmt.Errorf("json decode entry: %w", err)
+	}
+	return e.Key, e.Value, nil
+}
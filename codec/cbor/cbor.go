@@ -0,0 +1,215 @@
+// Package cbor provides a shrinkmap.Codec encoding entries as CBOR (RFC 8949).
+//
+// Each entry is a definite-length two-element array [key, value]. Keys and
+// values must be booleans, integers, floats, strings or byte slices (or named
+// types based on them); other types are rejected with an error.
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/jongyunha/shrinkmap"
+	"github.com/jongyunha/shrinkmap/internal/scalar"
+)
+
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+)
+
+// Codec implements shrinkmap.Codec using CBOR
+type Codec[K comparable, V any] struct{}
+
+var _ shrinkmap.Codec[string, int] = Codec[string, int]{}
+
+// New returns a CBOR codec, failing if K or V is not a supported scalar type
+func New[K comparable, V any]() (Codec[K, V], error) {
+	if _, err := scalar.KindOf[K](); err != nil {
+		return Codec[K, V]{}, fmt.Errorf("cbor: key: %w", err)
+	}
+	if _, err := scalar.KindOf[V](); err != nil {
+		return Codec[K, V]{}, fmt.Errorf("cbor: value: %w", err)
+	}
+	return Codec[K, V]{}, nil
+}
+
+// EncodeEntry encodes the entry as a CBOR array [key, value]
+func (Codec[K, V]) EncodeEntry(key K, value V) ([]byte, error) {
+	k, err := scalar.Of(key)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: key: %w", err)
+	}
+	v, err := scalar.Of(value)
+	if err != nil {
+		return nil, fmt.Errorf("cbor: value: %w", err)
+	}
+
+	buf := appendHead(nil, majorArray, 2)
+	buf = appendValue(buf, k)
+	buf = appendValue(buf, v)
+	return buf, nil
+}
+
+// DecodeEntry decodes an entry produced by EncodeEntry
+func (Codec[K, V]) DecodeEntry(data []byte) (K, V, error) {
+	var key K
+	var value V
+
+	d := decoder{data: data}
+	major, n, err := d.head()
+	if err != nil || major != majorArray || n != 2 {
+		return key, value, fmt.Errorf("cbor: expected 2-element array")
+	}
+
+	k, err := d.value()
+	if err != nil {
+		return key, value, fmt.Errorf("cbor: key: %w", err)
+	}
+	v, err := d.value()
+	if err != nil {
+		return key, value, fmt.Errorf("cbor: value: %w", err)
+	}
+	if len(d.data) != 0 {
+		return key, value, fmt.Errorf("cbor: %d trailing bytes", len(d.data))
+	}
+
+	if err := scalar.Assign(&key, k); err != nil {
+		return key, value, fmt.Errorf("cbor: key: %w", err)
+	}
+	if err := scalar.Assign(&value, v); err != nil {
+		return key, value, fmt.Errorf("cbor: value: %w", err)
+	}
+	return key, value, nil
+}
+
+// appendHead encodes a major type with its argument in the shortest form
+func appendHead(buf []byte, major byte, arg uint64) []byte {
+	m := major << 5
+	switch {
+	case arg < 24:
+		return append(buf, m|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(buf, m|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, m|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, m|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, m|27), arg)
+	}
+}
+
+func appendValue(buf []byte, v scalar.Value) []byte {
+	switch v.Kind {
+	case scalar.Bool:
+		if v.Bool {
+			return append(buf, 0xf5)
+		}
+		return append(buf, 0xf4)
+	case scalar.Int:
+		if v.Int >= 0 {
+			return appendHead(buf, majorUint, uint64(v.Int))
+		}
+		return appendHead(buf, majorNegInt, uint64(-(v.Int + 1)))
+	case scalar.Uint:
+		return appendHead(buf, majorUint, v.Uint)
+	case scalar.Float32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xfa), math.Float32bits(float32(v.Float)))
+	case scalar.Float64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xfb), math.Float64bits(v.Float))
+	case scalar.String:
+		return append(appendHead(buf, majorText, uint64(len(v.Str))), v.Str...)
+	case scalar.Bytes:
+		return append(appendHead(buf, majorBytes, uint64(len(v.Bytes))), v.Bytes...)
+	default:
+		return append(buf, 0xf6) // null
+	}
+}
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if uint64(len(d.data)) < n {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+// head reads an initial byte and its argument
+func (d *decoder) head() (byte, uint64, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, err
+	}
+	major, info := b[0]>>5, b[0]&0x1f
+
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info <= 27:
+		arg, err := d.next(1 << (info - 24))
+		if err != nil {
+			return 0, 0, err
+		}
+		var u uint64
+		for _, c := range arg {
+			u = u<<8 | uint64(c)
+		}
+		return major, u, nil
+	}
+	return 0, 0, fmt.Errorf("unsupported additional information %d", info)
+}
+
+func (d *decoder) value() (scalar.Value, error) {
+	if len(d.data) > 0 {
+		switch d.data[0] {
+		case 0xf4, 0xf5:
+			b, _ := d.next(1)
+			return scalar.Value{Kind: scalar.Bool, Bool: b[0] == 0xf5}, nil
+		case 0xfa:
+			d.data = d.data[1:]
+			b, err := d.next(4)
+			if err != nil {
+				return scalar.Value{}, err
+			}
+			return scalar.Value{Kind: scalar.Float32, Float: float64(math.Float32frombits(binary.BigEndian.Uint32(b)))}, nil
+		case 0xfb:
+			d.data = d.data[1:]
+			b, err := d.next(8)
+			if err != nil {
+				return scalar.Value{}, err
+			}
+			return scalar.Value{Kind: scalar.Float64, Float: math.Float64frombits(binary.BigEndian.Uint64(b))}, nil
+		}
+	}
+
+	major, arg, err := d.head()
+	if err != nil {
+		return scalar.Value{}, err
+	}
+	switch major {
+	case majorUint:
+		return scalar.Value{Kind: scalar.Uint, Uint: arg}, nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return scalar.Value{}, fmt.Errorf("negative integer overflows int64")
+		}
+		return scalar.Value{Kind: scalar.Int, Int: -1 - int64(arg)}, nil
+	case majorBytes:
+		b, err := d.next(arg)
+		return scalar.Value{Kind: scalar.Bytes, Bytes: b}, err
+	case majorText:
+		b, err := d.next(arg)
+		return scalar.Value{Kind: scalar.String, Str: string(b)}, err
+	}
+	return scalar.Value{}, fmt.Errorf("unsupported major type %d", major)
+}
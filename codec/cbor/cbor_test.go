@@ -0,0 +1,63 @@
+package cbor
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	t.Run("Known Encoding", func(t *testing.T) {
+		data, err := Codec[string, int]{}.EncodeEntry("a", -500)
+		if err != nil {
+			t.Fatalf("EncodeEntry failed: %v", err)
+		}
+		// [ "a", -500 ] as specified by RFC 8949
+		expected := []byte{0x82, 0x61, 'a', 0x39, 0x01, 0xf3}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Expected % x, got % x", expected, data)
+		}
+	})
+
+	t.Run("Round Trip Integers", func(t *testing.T) {
+		codec := Codec[int64, uint64]{}
+		for _, k := range []int64{0, 23, 24, -24, -25, 65536, math.MaxInt64, math.MinInt64} {
+			for _, v := range []uint64{0, 255, 256, math.MaxUint32 + 1, math.MaxUint64} {
+				data, err := codec.EncodeEntry(k, v)
+				if err != nil {
+					t.Fatalf("EncodeEntry(%d, %d) failed: %v", k, v, err)
+				}
+				gotK, gotV, err := codec.DecodeEntry(data)
+				if err != nil || gotK != k || gotV != v {
+					t.Errorf("Round trip of (%d, %d) gave (%d, %d), err=%v", k, v, gotK, gotV, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Round Trip Other Types", func(t *testing.T) {
+		codec := Codec[bool, float64]{}
+		data, _ := codec.EncodeEntry(false, math.Pi)
+		if b, f, err := codec.DecodeEntry(data); err != nil || b || f != math.Pi {
+			t.Errorf("Expected (false, pi), got (%v, %v), err=%v", b, f, err)
+		}
+
+		bin := Codec[string, []byte]{}
+		data, _ = bin.EncodeEntry("key", []byte("value"))
+		if k, v, err := bin.DecodeEntry(data); err != nil || k != "key" || string(v) != "value" {
+			t.Errorf("Expected (key, value), got (%v, %s), err=%v", k, v, err)
+		}
+	})
+
+	t.Run("Invalid Data", func(t *testing.T) {
+		if _, err := New[struct{ A int }, int](); err == nil {
+			t.Error("Expected error for struct key type")
+		}
+		if _, _, err := (Codec[string, int]{}).DecodeEntry([]byte{0x83}); err == nil {
+			t.Error("Expected error for wrong array length")
+		}
+		if _, _, err := (Codec[string, int]{}).DecodeEntry([]byte{0x82, 0x61, 'a', 0x01, 0x00}); err == nil {
+			t.Error("Expected error for trailing bytes")
+		}
+	})
+}
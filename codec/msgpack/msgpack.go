@@ -0,0 +1,259 @@
+// Package msgpack provides a shrinkmap.Codec encoding entries as MessagePack.
+//
+// Each entry is a two-element array [key, value]. Keys and values must be
+// booleans, integers, floats, strings or byte slices (or named types based on
+// them); other types are rejected with an error.
+package msgpack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/jongyunha/shrinkmap"
+	"github.com/jongyunha/shrinkmap/internal/scalar"
+)
+
+// Codec implements shrinkmap.Codec using MessagePack
+type Codec[K comparable, V any] struct{}
+
+var _ shrinkmap.Codec[string, int] = Codec[string, int]{}
+
+// New returns a MessagePack codec, failing if K or V is not a supported scalar type
+func New[K comparable, V any]() (Codec[K, V], error) {
+	if _, err := scalar.KindOf[K](); err != nil {
+		return Codec[K, V]{}, fmt.Errorf("msgpack: key: %w", err)
+	}
+	if _, err := scalar.KindOf[V](); err != nil {
+		return Codec[K, V]{}, fmt.Errorf("msgpack: value: %w", err)
+	}
+	return Codec[K, V]{}, nil
+}
+
+// EncodeEntry encodes the entry as a MessagePack array [key, value]
+func (Codec[K, V]) EncodeEntry(key K, value V) ([]byte, error) {
+	k, err := scalar.Of(key)
+	if err != nil {
+		return nil, fmt.Errorf("msgpack: key: %w", err)
+	}
+	v, err := scalar.Of(value)
+	if err != nil {
+		return nil, fmt.Errorf("msgpack: value: %w", err)
+	}
+
+	buf := []byte{0x92} // fixarray of length 2
+	buf = appendValue(buf, k)
+	buf = appendValue(buf, v)
+	return buf, nil
+}
+
+// DecodeEntry decodes an entry produced by EncodeEntry
+func (Codec[K, V]) DecodeEntry(data []byte) (K, V, error) {
+	var key K
+	var value V
+
+	if len(data) == 0 || data[0] != 0x92 {
+		return key, value, fmt.Errorf("msgpack: expected 2-element array")
+	}
+	d := decoder{data: data[1:]}
+
+	k, err := d.value()
+	if err != nil {
+		return key, value, fmt.Errorf("msgpack: key: %w", err)
+	}
+	v, err := d.value()
+	if err != nil {
+		return key, value, fmt.Errorf("msgpack: value: %w", err)
+	}
+	if len(d.data) != 0 {
+		return key, value, fmt.Errorf("msgpack: %d trailing bytes", len(d.data))
+	}
+
+	if err := scalar.Assign(&key, k); err != nil {
+		return key, value, fmt.Errorf("msgpack: key: %w", err)
+	}
+	if err := scalar.Assign(&value, v); err != nil {
+		return key, value, fmt.Errorf("msgpack: value: %w", err)
+	}
+	return key, value, nil
+}
+
+func appendValue(buf []byte, v scalar.Value) []byte {
+	switch v.Kind {
+	case scalar.Bool:
+		if v.Bool {
+			return append(buf, 0xc3)
+		}
+		return append(buf, 0xc2)
+	case scalar.Int:
+		if v.Int >= 0 {
+			return appendUint(buf, uint64(v.Int))
+		}
+		return appendInt(buf, v.Int)
+	case scalar.Uint:
+		return appendUint(buf, v.Uint)
+	case scalar.Float32:
+		buf = append(buf, 0xca)
+		return binary.BigEndian.AppendUint32(buf, math.Float32bits(float32(v.Float)))
+	case scalar.Float64:
+		buf = append(buf, 0xcb)
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(v.Float))
+	case scalar.String:
+		n := len(v.Str)
+		switch {
+		case n < 32:
+			buf = append(buf, 0xa0|byte(n))
+		case n <= math.MaxUint8:
+			buf = append(buf, 0xd9, byte(n))
+		case n <= math.MaxUint16:
+			buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+		default:
+			buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+		}
+		return append(buf, v.Str...)
+	case scalar.Bytes:
+		n := len(v.Bytes)
+		switch {
+		case n <= math.MaxUint8:
+			buf = append(buf, 0xc4, byte(n))
+		case n <= math.MaxUint16:
+			buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+		default:
+			buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+		}
+		return append(buf, v.Bytes...)
+	default:
+		return append(buf, 0xc0)
+	}
+}
+
+func appendUint(buf []byte, u uint64) []byte {
+	switch {
+	case u <= 0x7f:
+		return append(buf, byte(u))
+	case u <= math.MaxUint8:
+		return append(buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(u))
+	case u <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(u))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), u)
+	}
+}
+
+func appendInt(buf []byte, i int64) []byte {
+	switch {
+	case i >= -32:
+		return append(buf, byte(i))
+	case i >= math.MinInt8:
+		return append(buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(i))
+	case i >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(i))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(i))
+	}
+}
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data) < n {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b, nil
+}
+
+func (d *decoder) length(n int) (int, error) {
+	b, err := d.next(n)
+	if err != nil {
+		return 0, err
+	}
+	switch n {
+	case 1:
+		return int(b[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(b)), nil
+	default:
+		return int(binary.BigEndian.Uint32(b)), nil
+	}
+}
+
+func (d *decoder) value() (scalar.Value, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return scalar.Value{}, err
+	}
+	tag := b[0]
+
+	switch {
+	case tag <= 0x7f:
+		return scalar.Value{Kind: scalar.Uint, Uint: uint64(tag)}, nil
+	case tag >= 0xe0:
+		return scalar.Value{Kind: scalar.Int, Int: int64(int8(tag))}, nil
+	case tag&0xe0 == 0xa0:
+		s, err := d.next(int(tag & 0x1f))
+		return scalar.Value{Kind: scalar.String, Str: string(s)}, err
+	}
+
+	switch tag {
+	case 0xc2, 0xc3:
+		return scalar.Value{Kind: scalar.Bool, Bool: tag == 0xc3}, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		b, err := d.next(1 << (tag - 0xcc))
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		return scalar.Value{Kind: scalar.Uint, Uint: beUint(b)}, nil
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		n := 1 << (tag - 0xd0)
+		b, err := d.next(n)
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		// sign-extend from n bytes
+		shift := 64 - 8*n
+		return scalar.Value{Kind: scalar.Int, Int: int64(beUint(b)<<shift) >> shift}, nil
+	case 0xca:
+		b, err := d.next(4)
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		return scalar.Value{Kind: scalar.Float32, Float: float64(math.Float32frombits(binary.BigEndian.Uint32(b)))}, nil
+	case 0xcb:
+		b, err := d.next(8)
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		return scalar.Value{Kind: scalar.Float64, Float: math.Float64frombits(binary.BigEndian.Uint64(b))}, nil
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.length(1 << (tag - 0xd9))
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		s, err := d.next(n)
+		return scalar.Value{Kind: scalar.String, Str: string(s)}, err
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.length(1 << (tag - 0xc4))
+		if err != nil {
+			return scalar.Value{}, err
+		}
+		s, err := d.next(n)
+		return scalar.Value{Kind: scalar.Bytes, Bytes: s}, err
+	}
+	return scalar.Value{}, fmt.Errorf("unsupported type tag 0x%02x", tag)
+}
+
+func beUint(b []byte) uint64 {
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u
+}
@@ -0,0 +1,67 @@
+package msgpack
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	t.Run("Known Encoding", func(t *testing.T) {
+		data, err := Codec[string, int]{}.EncodeEntry("a", -1)
+		if err != nil {
+			t.Fatalf("EncodeEntry failed: %v", err)
+		}
+		expected := []byte{0x92, 0xa1, 'a', 0xff}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Expected % x, got % x", expected, data)
+		}
+	})
+
+	t.Run("Round Trip Integers", func(t *testing.T) {
+		codec := Codec[int64, uint64]{}
+		for _, k := range []int64{0, 1, -1, -33, 127, 128, -129, math.MinInt32, math.MaxInt64, math.MinInt64} {
+			for _, v := range []uint64{0, 127, 255, 65536, math.MaxUint64} {
+				data, err := codec.EncodeEntry(k, v)
+				if err != nil {
+					t.Fatalf("EncodeEntry(%d, %d) failed: %v", k, v, err)
+				}
+				gotK, gotV, err := codec.DecodeEntry(data)
+				if err != nil || gotK != k || gotV != v {
+					t.Errorf("Round trip of (%d, %d) gave (%d, %d), err=%v", k, v, gotK, gotV, err)
+				}
+			}
+		}
+	})
+
+	t.Run("Round Trip Other Types", func(t *testing.T) {
+		long := string(bytes.Repeat([]byte("x"), 70000))
+		codec := Codec[string, []byte]{}
+		data, err := codec.EncodeEntry(long, []byte{1, 2, 3})
+		if err != nil {
+			t.Fatalf("EncodeEntry failed: %v", err)
+		}
+		k, v, err := codec.DecodeEntry(data)
+		if err != nil || k != long || !bytes.Equal(v, []byte{1, 2, 3}) {
+			t.Errorf("Round trip failed: err=%v", err)
+		}
+
+		floats := Codec[bool, float32]{}
+		data, _ = floats.EncodeEntry(true, 1.5)
+		if b, f, err := floats.DecodeEntry(data); err != nil || !b || f != 1.5 {
+			t.Errorf("Expected (true, 1.5), got (%v, %v), err=%v", b, f, err)
+		}
+	})
+
+	t.Run("Unsupported Types", func(t *testing.T) {
+		if _, err := New[string, struct{}](); err == nil {
+			t.Error("Expected error for struct value type")
+		}
+		if _, _, err := (Codec[int8, int]{}).DecodeEntry([]byte{0x92, 0xcd, 0x01, 0x00, 0x00}); err == nil {
+			t.Error("Expected overflow error decoding 256 into int8")
+		}
+		if _, _, err := (Codec[string, int]{}).DecodeEntry([]byte{0x92, 0xa5, 'a'}); err == nil {
+			t.Error("Expected error for truncated data")
+		}
+	})
+}
@@ -0,0 +1,247 @@
+// Package protobuf provides a shrinkmap.Codec encoding entries in the Protocol
+// Buffers wire format, without depending on the protobuf runtime.
+//
+// An entry is encoded as the message
+//
+//	message Entry {
+//	  KeyType   key   = 1;
+//	  ValueType value = 2;
+//	}
+//
+// where Go scalar types map to protobuf types as follows: bool to bool,
+// signed integers to sint64, unsigned integers to uint64, float32 to float,
+// float64 to double, string to string and []byte to bytes. Message types are
+// supported by supplying marshal functions (typically wrapping proto.Marshal
+// and proto.Unmarshal), in which case the field is an embedded message.
+package protobuf
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/jongyunha/shrinkmap"
+	"github.com/jongyunha/shrinkmap/internal/scalar"
+)
+
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+
+	fieldKey   = 1
+	fieldValue = 2
+)
+
+// Config supplies optional marshal functions for non-scalar keys or values
+type Config[K comparable, V any] struct {
+	MarshalKey   func(K) ([]byte, error)
+	UnmarshalKey func([]byte) (K, error)
+
+	MarshalValue   func(V) ([]byte, error)
+	UnmarshalValue func([]byte) (V, error)
+}
+
+// Codec implements shrinkmap.Codec using the protobuf wire format
+type Codec[K comparable, V any] struct {
+	config    Config[K, V]
+	keyKind   scalar.Kind
+	valueKind scalar.Kind
+}
+
+var _ shrinkmap.Codec[string, int] = (*Codec[string, int])(nil)
+
+// New returns a protobuf codec. Keys and values without marshal functions
+// must be supported scalar types.
+func New[K comparable, V any](config Config[K, V]) (*Codec[K, V], error) {
+	c := &Codec[K, V]{config: config}
+
+	if (config.MarshalKey == nil) != (config.UnmarshalKey == nil) {
+		return nil, fmt.Errorf("protobuf: key marshal and unmarshal functions must be set together")
+	}
+	if (config.MarshalValue == nil) != (config.UnmarshalValue == nil) {
+		return nil, fmt.Errorf("protobuf: value marshal and unmarshal functions must be set together")
+	}
+
+	var err error
+	if config.MarshalKey == nil {
+		if c.keyKind, err = scalar.KindOf[K](); err != nil {
+			return nil, fmt.Errorf("protobuf: key: %w", err)
+		}
+	}
+	if config.MarshalValue == nil {
+		if c.valueKind, err = scalar.KindOf[V](); err != nil {
+			return nil, fmt.Errorf("protobuf: value: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// EncodeEntry encodes the entry as an Entry message
+func (c *Codec[K, V]) EncodeEntry(key K, value V) ([]byte, error) {
+	var buf []byte
+	var err error
+
+	if c.config.MarshalKey != nil {
+		data, err := c.config.MarshalKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("protobuf: key: %w", err)
+		}
+		buf = appendBytesField(buf, fieldKey, data)
+	} else if buf, err = appendScalarField(buf, fieldKey, key); err != nil {
+		return nil, fmt.Errorf("protobuf: key: %w", err)
+	}
+
+	if c.config.MarshalValue != nil {
+		data, err := c.config.MarshalValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("protobuf: value: %w", err)
+		}
+		buf = appendBytesField(buf, fieldValue, data)
+	} else if buf, err = appendScalarField(buf, fieldValue, value); err != nil {
+		return nil, fmt.Errorf("protobuf: value: %w", err)
+	}
+	return buf, nil
+}
+
+// DecodeEntry decodes an Entry message. Unknown fields are skipped and missing
+// fields decode to the zero value.
+func (c *Codec[K, V]) DecodeEntry(data []byte) (K, V, error) {
+	var key K
+	var value V
+
+	for len(data) > 0 {
+		tag, n := binary.Uvarint(data)
+		if n <= 0 {
+			return key, value, fmt.Errorf("protobuf: invalid field tag")
+		}
+		data = data[n:]
+		field, wire := tag>>3, tag&7
+
+		var raw uint64
+		var payload []byte
+		switch wire {
+		case wireVarint:
+			raw, n = binary.Uvarint(data)
+			if n <= 0 {
+				return key, value, fmt.Errorf("protobuf: invalid varint in field %d", field)
+			}
+			data = data[n:]
+		case wireFixed64:
+			if len(data) < 8 {
+				return key, value, fmt.Errorf("protobuf: truncated field %d", field)
+			}
+			raw, data = binary.LittleEndian.Uint64(data), data[8:]
+		case wireFixed32:
+			if len(data) < 4 {
+				return key, value, fmt.Errorf("protobuf: truncated field %d", field)
+			}
+			raw, data = uint64(binary.LittleEndian.Uint32(data)), data[4:]
+		case wireBytes:
+			size, n := binary.Uvarint(data)
+			if n <= 0 || uint64(len(data)-n) < size {
+				return key, value, fmt.Errorf("protobuf: truncated field %d", field)
+			}
+			payload, data = data[n:n+int(size)], data[n+int(size):]
+		default:
+			return key, value, fmt.Errorf("protobuf: unsupported wire type %d", wire)
+		}
+
+		var err error
+		switch field {
+		case fieldKey:
+			if c.config.UnmarshalKey != nil {
+				key, err = c.config.UnmarshalKey(payload)
+			} else {
+				err = assignField(&key, c.keyKind, wire, raw, payload)
+			}
+			if err != nil {
+				return key, value, fmt.Errorf("protobuf: key: %w", err)
+			}
+		case fieldValue:
+			if c.config.UnmarshalValue != nil {
+				value, err = c.config.UnmarshalValue(payload)
+			} else {
+				err = assignField(&value, c.valueKind, wire, raw, payload)
+			}
+			if err != nil {
+				return key, value, fmt.Errorf("protobuf: value: %w", err)
+			}
+		}
+	}
+	return key, value, nil
+}
+
+func appendTag(buf []byte, field int, wire int) []byte {
+	return binary.AppendUvarint(buf, uint64(field<<3|wire))
+}
+
+func appendBytesField(buf []byte, field int, data []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+func appendScalarField(buf []byte, field int, x any) ([]byte, error) {
+	v, err := scalar.Of(x)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v.Kind {
+	case scalar.Bool:
+		var b uint64
+		if v.Bool {
+			b = 1
+		}
+		return binary.AppendUvarint(appendTag(buf, field, wireVarint), b), nil
+	case scalar.Int:
+		zigzag := uint64(v.Int<<1) ^ uint64(v.Int>>63)
+		return binary.AppendUvarint(appendTag(buf, field, wireVarint), zigzag), nil
+	case scalar.Uint:
+		return binary.AppendUvarint(appendTag(buf, field, wireVarint), v.Uint), nil
+	case scalar.Float32:
+		return binary.LittleEndian.AppendUint32(appendTag(buf, field, wireFixed32), math.Float32bits(float32(v.Float))), nil
+	case scalar.Float64:
+		return binary.LittleEndian.AppendUint64(appendTag(buf, field, wireFixed64), math.Float64bits(v.Float)), nil
+	case scalar.String:
+		return appendBytesField(buf, field, []byte(v.Str)), nil
+	default:
+		return appendBytesField(buf, field, v.Bytes), nil
+	}
+}
+
+func assignField[T any](dst *T, kind scalar.Kind, wire, raw uint64, payload []byte) error {
+	expected := map[scalar.Kind]uint64{
+		scalar.Bool:    wireVarint,
+		scalar.Int:     wireVarint,
+		scalar.Uint:    wireVarint,
+		scalar.Float32: wireFixed32,
+		scalar.Float64: wireFixed64,
+		scalar.String:  wireBytes,
+		scalar.Bytes:   wireBytes,
+	}[kind]
+	if wire != expected {
+		return fmt.Errorf("wire type %d does not match %s", wire, kind)
+	}
+
+	var v scalar.Value
+	switch kind {
+	case scalar.Bool:
+		v = scalar.Value{Kind: scalar.Bool, Bool: raw != 0}
+	case scalar.Int:
+		v = scalar.Value{Kind: scalar.Int, Int: int64(raw>>1) ^ -int64(raw&1)}
+	case scalar.Uint:
+		v = scalar.Value{Kind: scalar.Uint, Uint: raw}
+	case scalar.Float32:
+		v = scalar.Value{Kind: scalar.Float32, Float: float64(math.Float32frombits(uint32(raw)))}
+	case scalar.Float64:
+		v = scalar.Value{Kind: scalar.Float64, Float: math.Float64frombits(raw)}
+	case scalar.String:
+		v = scalar.Value{Kind: scalar.String, Str: string(payload)}
+	default:
+		v = scalar.Value{Kind: scalar.Bytes, Bytes: payload}
+	}
+	return scalar.Assign(dst, v)
+}
@@ -0,0 +1,79 @@
+package protobuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestCodec(t *testing.T) {
+	t.Run("Known Encoding", func(t *testing.T) {
+		codec, err := New(Config[string, int]{})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+		data, err := codec.EncodeEntry("a", -2)
+		if err != nil {
+			t.Fatalf("EncodeEntry failed: %v", err)
+		}
+		// key: field 1 length-delimited "a"; value: field 2 sint64 zigzag(-2) = 3
+		expected := []byte{0x0a, 0x01, 'a', 0x10, 0x03}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("Expected % x, got % x", expected, data)
+		}
+	})
+
+	t.Run("Round Trip Scalars", func(t *testing.T) {
+		codec, _ := New(Config[int64, float64]{})
+		for _, k := range []int64{0, -1, math.MaxInt64, math.MinInt64} {
+			data, err := codec.EncodeEntry(k, 2.5)
+			if err != nil {
+				t.Fatalf("EncodeEntry failed: %v", err)
+			}
+			gotK, gotV, err := codec.DecodeEntry(data)
+			if err != nil || gotK != k || gotV != 2.5 {
+				t.Errorf("Round trip of %d gave (%d, %v), err=%v", k, gotK, gotV, err)
+			}
+		}
+	})
+
+	t.Run("Message Values", func(t *testing.T) {
+		type user struct{ Name string }
+		codec, err := New(Config[uint32, user]{
+			MarshalValue: func(u user) ([]byte, error) { return json.Marshal(u) },
+			UnmarshalValue: func(data []byte) (user, error) {
+				var u user
+				err := json.Unmarshal(data, &u)
+				return u, err
+			},
+		})
+		if err != nil {
+			t.Fatalf("New failed: %v", err)
+		}
+
+		data, err := codec.EncodeEntry(7, user{Name: "alice"})
+		if err != nil {
+			t.Fatalf("EncodeEntry failed: %v", err)
+		}
+		k, v, err := codec.DecodeEntry(data)
+		if err != nil || k != 7 || v.Name != "alice" {
+			t.Errorf("Expected (7, alice), got (%d, %+v), err=%v", k, v, err)
+		}
+	})
+
+	t.Run("Unknown Fields Skipped", func(t *testing.T) {
+		codec, _ := New(Config[string, bool]{})
+		data := []byte{0x0a, 0x01, 'k', 0x1a, 0x02, 'x', 'y', 0x10, 0x01}
+		k, v, err := codec.DecodeEntry(data)
+		if err != nil || k != "k" || !v {
+			t.Errorf("Expected (k, true), got (%q, %v), err=%v", k, v, err)
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		if _, err := New(Config[string, struct{}]{}); err == nil {
+			t.Error("Expected error for struct value without marshal functions")
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCodecs(t *testing.T) {
+	codecs := map[string]Codec[string, []int]{
+		"gob":  GobCodec[string, []int]{},
+		"json": JSONCodec[string, []int]{},
+	}
+
+	for name, codec := range codecs {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.EncodeEntry("key", []int{1, 2, 3})
+			if err != nil {
+				t.Fatalf("EncodeEntry failed: %v", err)
+			}
+			k, v, err := codec.DecodeEntry(data)
+			if err != nil {
+				t.Fatalf("DecodeEntry failed: %v", err)
+			}
+			if k != "key" || len(v) != 3 || v[2] != 3 {
+				t.Errorf("Unexpected entry: %q %v", k, v)
+			}
+
+			sm := New[string, []int](DefaultConfig())
+			defer sm.Stop()
+			sm.Set("a", []int{1})
+			sm.Set("b", []int{2, 3})
+
+			var buf bytes.Buffer
+			if err := sm.SaveToCodec(&buf, codec); err != nil {
+				t.Fatalf("SaveToCodec failed: %v", err)
+			}
+			restored := New[string, []int](DefaultConfig())
+			defer restored.Stop()
+			if err := restored.LoadFromCodec(&buf, codec); err != nil {
+				t.Fatalf("LoadFromCodec failed: %v", err)
+			}
+			if v, _ := restored.Get("b"); len(v) != 2 || v[1] != 3 {
+				t.Errorf("Expected b=[2 3], got %v", v)
+			}
+		})
+	}
+}
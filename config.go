@@ -2,6 +2,7 @@ package shrinkmap
 
 import (
 	"fmt"
+	"path"
 	"time"
 )
 
@@ -27,6 +28,237 @@ type Config struct {
 
 	// Extra capacity factor when creating new map (e.g., 1.2 for 20% extra space)
 	CapacityGrowthFactor float64
+
+	// Policy sizing the map rebuilt by a shrink; nil uses GrowthFactorCapacity
+	TargetCapacity TargetCapacityFunc
+
+	// Skip shrinks while the net number of keys added within this window
+	// would outgrow the shrunk map, avoiding shrink-then-regrow cycles
+	// (0 disables). ForceShrink is not affected.
+	GrowthHysteresis time.Duration
+
+	// Call debug.FreeOSMemory after a shrink so freed memory is returned to the OS
+	ReleaseOSMemoryAfterShrink bool
+
+	// Minimum number of deleted entries a shrink must reclaim before memory is released
+	ReleaseOSMemoryThreshold int
+
+	// Name identifying the map in metrics and logs
+	Name string
+
+	// Additional label pairs distinguishing the map's metrics, e.g. {"component": "auth"}
+	Labels map[string]string
+
+	// Thresholds evaluated by Healthy
+	Health HealthConfig
+
+	// Rules notifying operators when errors or panics exceed a threshold
+	Alerts []AlertRule
+
+	// Operations taking at least this long are reported to OnSlowOp (0 disables)
+	SlowOpThreshold time.Duration
+
+	// Called synchronously with the operation name ("set", "get", "delete",
+	// "batch" or "shrink"), its duration and its key (nil for batch and shrink)
+	OnSlowOp func(op string, d time.Duration, key any)
+
+	// Reject inserts of new keys with ErrMemoryPressure while memory pressure is signaled
+	RejectInsertsUnderPressure bool
+
+	// Maximum size of a single value in bytes; larger values fail with ErrValueTooLarge (0 disables)
+	MaxValueBytes int
+
+	// Measures values for MaxValueBytes; nil measures strings and byte slices
+	ValueSizer SizerFunc
+
+	// Called with every key before it is written; an error rejects the write
+	// with a *ValidationError
+	ValidateKey func(key any) error
+
+	// Called with every value before it is written; an error rejects the write
+	// with a *ValidationError
+	ValidateValue func(value any) error
+
+	// Applied to every written value before validation, e.g. to canonicalize or
+	// intern it; must return a value of the map's value type
+	TransformOnSet func(key, value any) any
+
+	// Called with every entry read by Get; entries for which it returns false
+	// are treated as missing
+	ValidateOnGet func(key, value any) bool
+
+	// Delete entries that fail ValidateOnGet when they are read
+	DeleteInvalidOnGet bool
+
+	// How long previous values are retained for GetAt and SnapshotAt (0 disables history)
+	HistoryRetention time.Duration
+
+	// Maximum number of versions retained per key (0 for unlimited)
+	HistoryMaxVersions int
+
+	// Skip per-operation accounting not needed for shrinking. TotalItemsProcessed,
+	// PeakSize and the size hint behind Stats are no longer updated by writes.
+	MinimalAccounting bool
+
+	// Policy removing entries automatically. Eviction, MaxEntries, MaxEntryAge,
+	// AgeRules and SweepBudget are only supported by NewLinked: New records an
+	// error in the metrics and ignores them, and NewInGroup rejects them.
+	Eviction EvictionPolicy
+
+	// Maximum number of entries kept under EvictFIFO; NewLinked only
+	MaxEntries int
+
+	// Maximum age of an entry since insertion under EvictAge; NewLinked only
+	MaxEntryAge time.Duration
+
+	// Maximum ages for string keys under EvictAge, overriding MaxEntryAge. The
+	// first matching rule applies. NewLinked only, and ignored with an error
+	// recorded unless keys are strings.
+	AgeRules []AgeRule
+
+	// Limits on the work of a single expiration sweep under EvictAge, so a
+	// backlog of expired entries is removed over several sweeps; NewLinked only
+	SweepBudget SweepBudget
+
+	// Store a single shared copy of equal values, cutting memory for maps where
+	// many keys hold few distinct values. Ignored unless the value type is comparable.
+	InternValues bool
+
+	// Copy string keys into shared arena blocks on insert and compact them on
+	// shrink, so keys do not retain the buffers they were sliced from.
+	// Ignored unless the key type is a string type.
+	InternKeys bool
+
+	// Merge background shrink checks requested by batches while one is
+	// already pending, instead of starting a goroutine per batch
+	CoalesceShrinks bool
+
+	// Cron-style windows confining shrinks triggered by the deleted ratio or
+	// MaxMapSize, e.g. "* 1-4 * * *" for 01:00 to 04:59 every day. Windows are
+	// separated by ";" and evaluated in local time unless prefixed with
+	// "CRON_TZ=<zone> ". Outside a window a due shrink is postponed to the first
+	// check inside one, so windows should be longer than ShrinkInterval.
+	// ForceShrink ignores the schedule. Empty allows shrinking at any time.
+	ShrinkSchedule string
+
+	// Replaces keys before they are recorded in errors, e.g. to mask or hash
+	// sensitive keys that would otherwise reach logs. nil records keys as is.
+	RedactKey func(key any) any
+
+	// Record errors returned by SetChecked, TrySet, SetWait, ApplyBatch,
+	// ApplyBatchMode, Rename and Publish in Metrics. Every error is counted; at most 10
+	// per second are added to the error history and passed to alert rules.
+	// Writes rejected by Set are always recorded, as Set cannot return them.
+	RecordAPIErrors bool
+
+	// Shrink when the map is allocated for at least this many times its live
+	// entries, regardless of ShrinkRatio (0 disables). Unlike the deleted
+	// ratio, this catches maps that grew large once and were later refilled
+	// with few entries, since the allocation outlives the deletion counters.
+	PeakShrinkFactor float64
+
+	// Receives shrink, operation and error events as they happen (nil disables)
+	MetricsObserver MetricsObserver
+
+	// Store a copy of every written value and hand out copies on reads, so
+	// callers cannot mutate stored pointer, slice or map values through
+	// retained references. Copies are made with CloneValue. Reads cover Get,
+	// TryGet, Snapshot, iterators, Scan, locked cursors, SampleWeighted,
+	// GetAt, SnapshotAt and the change events passed to each watcher and sink.
+	// LinkedShrinkableMap and TieredShrinkableMap only honor CopyOnWrite.
+	CopyOnWrite bool
+	CopyOnRead  bool
+
+	// Deep-copies values for CopyOnWrite and CopyOnRead, see Clone
+	CloneValue CloneFunc
+
+	// Debugging aid for aliasing bugs: hash every value when it is stored and
+	// check the hash when the entry is overwritten, deleted or copied by a
+	// shrink, recording values mutated in place through retained references
+	// as errors wrapping ErrValueMutated. Hashing walks the whole value under
+	// the map lock, so this is too slow for production use.
+	VerifyImmutable bool
+
+	// Number every write with a generation reported by Inspect and change
+	// events, so external caches can tell a replaced entry from one merely
+	// moved by a shrink. Costs a uint64 per entry.
+	TrackGenerations bool
+
+	// Entries of a TieredShrinkableMap not read or written for this long are
+	// moved to its encoded cold tier (0 disables automatic demotion)
+	ColdAfter time.Duration
+
+	// Restart the auto-shrink goroutine after a panic instead of leaving the
+	// map without automatic shrinking. For maps in a Group, the group's checks
+	// of the map resume after the backoff.
+	RestartShrinkLoopOnPanic bool
+
+	// Maximum number of restarts after panics (0 for unlimited)
+	ShrinkLoopMaxRestarts int
+
+	// Delay before the first restart, doubled after each restart up to
+	// ShrinkInterval
+	ShrinkLoopRestartBackoff time.Duration
+
+	// Drives the auto-shrink goroutine instead of a ticker firing every
+	// ShrinkInterval: each receive runs one shrink check. Lets tests step the
+	// loop deterministically or tie checks to events such as GC cycles.
+	// Closing the channel ends the goroutine. Rejected by NewInGroup.
+	ShrinkTrigger <-chan struct{}
+}
+
+// EvictionPolicy selects which entries are removed automatically
+type EvictionPolicy int
+
+const (
+	// EvictNone never evicts entries
+	EvictNone EvictionPolicy = iota
+	// EvictFIFO evicts the oldest inserted entries beyond MaxEntries
+	EvictFIFO
+	// EvictAge evicts entries inserted more than MaxEntryAge ago
+	EvictAge
+)
+
+// AgeRule sets the maximum age of entries whose keys start with Prefix or
+// match Pattern. Exactly one of them must be set.
+type AgeRule struct {
+	// Prefix of the keys the rule applies to, e.g. "session:"
+	Prefix string
+
+	// Glob pattern matched against keys, using the syntax of path.Match
+	Pattern string
+
+	// Maximum age since insertion; 0 means matching entries never expire
+	MaxAge time.Duration
+}
+
+// SweepBudget limits a single expiration sweep. Expired entries left over
+// stay hidden from reads and are removed by later sweeps.
+type SweepBudget struct {
+	// Maximum number of entries removed (0 for unlimited)
+	MaxEntries int
+
+	// Maximum time spent (0 for unlimited)
+	MaxDuration time.Duration
+}
+
+// evictionError reports eviction settings, which only NewLinked supports
+func (c Config) evictionError() error {
+	if c.Eviction == EvictNone && c.MaxEntries == 0 && c.MaxEntryAge == 0 &&
+		len(c.AgeRules) == 0 && c.SweepBudget == (SweepBudget{}) {
+		return nil
+	}
+	return fmt.Errorf("eviction settings are only supported by NewLinked")
+}
+
+// withoutEviction returns c with the eviction settings cleared
+func (c Config) withoutEviction() Config {
+	c.Eviction = EvictNone
+	c.MaxEntries = 0
+	c.MaxEntryAge = 0
+	c.AgeRules = nil
+	c.SweepBudget = SweepBudget{}
+	return c
 }
 
 // DefaultConfig returns the default configuration for ShrinkableMap
@@ -53,6 +285,18 @@ func DefaultConfig() Config {
 
 		// Allocate 20% extra space when shrinking
 		CapacityGrowthFactor: 1.2,
+
+		// Leave returning memory to the OS to the runtime by default
+		ReleaseOSMemoryAfterShrink: false,
+
+		// Only release memory after shrinks reclaiming at least 100k entries
+		ReleaseOSMemoryThreshold: 100_000,
+
+		// Use the default health thresholds
+		Health: DefaultHealthConfig(),
+
+		// Run at most one pending background shrink check at a time
+		CoalesceShrinks: true,
 	}
 }
 
@@ -98,6 +342,220 @@ func (c Config) WithCapacityGrowthFactor(factor float64) Config {
 	return c
 }
 
+// WithReleaseOSMemoryAfterShrink enables releasing memory to the OS after shrinks
+// reclaiming at least threshold entries and returns the modified config
+func (c Config) WithReleaseOSMemoryAfterShrink(enabled bool, threshold int) Config {
+	c.ReleaseOSMemoryAfterShrink = enabled
+	c.ReleaseOSMemoryThreshold = threshold
+	return c
+}
+
+// WithName sets the map name and returns the modified config
+func (c Config) WithName(name string) Config {
+	c.Name = name
+	return c
+}
+
+// WithLabel adds a label pair and returns the modified config.
+// The labels of the original config are not modified.
+func (c Config) WithLabel(key, value string) Config {
+	c.Labels = copyLabels(c.Labels)
+	c.Labels[key] = value
+	return c
+}
+
+// WithHealth sets the health thresholds and returns the modified config
+func (c Config) WithHealth(health HealthConfig) Config {
+	c.Health = health
+	return c
+}
+
+// WithAlert adds an alert rule and returns the modified config.
+// The rules of the original config are not modified.
+func (c Config) WithAlert(rule AlertRule) Config {
+	c.Alerts = append(append([]AlertRule(nil), c.Alerts...), rule)
+	return c
+}
+
+// WithMetricsObserver sets the observer receiving metrics events and returns the modified config
+func (c Config) WithMetricsObserver(observer MetricsObserver) Config {
+	c.MetricsObserver = observer
+	return c
+}
+
+// WithSlowOpThreshold enables slow operation reporting and returns the modified config
+func (c Config) WithSlowOpThreshold(threshold time.Duration, onSlowOp func(op string, d time.Duration, key any)) Config {
+	c.SlowOpThreshold = threshold
+	c.OnSlowOp = onSlowOp
+	return c
+}
+
+// WithRejectInsertsUnderPressure sets memory-pressure degradation and returns the modified config
+func (c Config) WithRejectInsertsUnderPressure(enabled bool) Config {
+	c.RejectInsertsUnderPressure = enabled
+	return c
+}
+
+// WithMaxValueBytes sets the value size limit and sizer and returns the modified config
+func (c Config) WithMaxValueBytes(limit int, sizer SizerFunc) Config {
+	c.MaxValueBytes = limit
+	c.ValueSizer = sizer
+	return c
+}
+
+// WithValidators sets the key and value validation functions and returns the modified config
+func (c Config) WithValidators(validateKey func(key any) error, validateValue func(value any) error) Config {
+	c.ValidateKey = validateKey
+	c.ValidateValue = validateValue
+	return c
+}
+
+// WithTransformOnSet sets the value transform and returns the modified config
+func (c Config) WithTransformOnSet(transform func(key, value any) any) Config {
+	c.TransformOnSet = transform
+	return c
+}
+
+// WithValidateOnGet sets the read validation function and returns the modified config
+func (c Config) WithValidateOnGet(validate func(key, value any) bool, deleteInvalid bool) Config {
+	c.ValidateOnGet = validate
+	c.DeleteInvalidOnGet = deleteInvalid
+	return c
+}
+
+// WithHistory enables retained history and returns the modified config
+func (c Config) WithHistory(retention time.Duration, maxVersions int) Config {
+	c.HistoryRetention = retention
+	c.HistoryMaxVersions = maxVersions
+	return c
+}
+
+// WithMinimalAccounting sets throughput mode and returns the modified config
+func (c Config) WithMinimalAccounting(enabled bool) Config {
+	c.MinimalAccounting = enabled
+	return c
+}
+
+// WithFIFOEviction evicts the oldest inserted entries beyond maxEntries and returns the modified config
+func (c Config) WithFIFOEviction(maxEntries int) Config {
+	c.Eviction = EvictFIFO
+	c.MaxEntries = maxEntries
+	return c
+}
+
+// WithAgeEviction evicts entries older than maxAge and returns the modified config
+func (c Config) WithAgeEviction(maxAge time.Duration) Config {
+	c.Eviction = EvictAge
+	c.MaxEntryAge = maxAge
+	return c
+}
+
+// WithAgeRules sets per-prefix or per-pattern maximum entry ages under age eviction and returns the modified config
+func (c Config) WithAgeRules(rules ...AgeRule) Config {
+	c.AgeRules = rules
+	return c
+}
+
+// WithSweepBudget limits the entries removed and time spent per expiration sweep and returns the modified config
+func (c Config) WithSweepBudget(maxEntries int, maxDuration time.Duration) Config {
+	c.SweepBudget = SweepBudget{MaxEntries: maxEntries, MaxDuration: maxDuration}
+	return c
+}
+
+// WithValueCopies sets defensive copying of values on writes and reads and returns the modified config
+func (c Config) WithValueCopies(onWrite, onRead bool, clone CloneFunc) Config {
+	c.CopyOnWrite = onWrite
+	c.CopyOnRead = onRead
+	c.CloneValue = clone
+	return c
+}
+
+// WithVerifyImmutable sets immutability verification and returns the modified config
+func (c Config) WithVerifyImmutable(enabled bool) Config {
+	c.VerifyImmutable = enabled
+	return c
+}
+
+// WithTrackGenerations sets per-entry generation tracking and returns the modified config
+func (c Config) WithTrackGenerations(enabled bool) Config {
+	c.TrackGenerations = enabled
+	return c
+}
+
+// WithTargetCapacity sets the policy sizing shrunk maps and returns the modified config
+func (c Config) WithTargetCapacity(policy TargetCapacityFunc) Config {
+	c.TargetCapacity = policy
+	return c
+}
+
+// WithGrowthHysteresis sets the window in which regrowth postpones shrinks and returns the modified config
+func (c Config) WithGrowthHysteresis(window time.Duration) Config {
+	c.GrowthHysteresis = window
+	return c
+}
+
+// WithColdAfter sets the idle time after which tiered map entries are demoted and returns the modified config
+func (c Config) WithColdAfter(d time.Duration) Config {
+	c.ColdAfter = d
+	return c
+}
+
+// WithShrinkLoopRestart restarts the auto-shrink goroutine after panics and returns the modified config
+func (c Config) WithShrinkLoopRestart(maxRestarts int, backoff time.Duration) Config {
+	c.RestartShrinkLoopOnPanic = true
+	c.ShrinkLoopMaxRestarts = maxRestarts
+	c.ShrinkLoopRestartBackoff = backoff
+	return c
+}
+
+// WithShrinkTrigger drives the auto-shrink goroutine by trigger instead of a ticker and returns the modified config
+func (c Config) WithShrinkTrigger(trigger <-chan struct{}) Config {
+	c.ShrinkTrigger = trigger
+	return c
+}
+
+// WithInternValues sets value interning and returns the modified config
+func (c Config) WithInternValues(enabled bool) Config {
+	c.InternValues = enabled
+	return c
+}
+
+// WithInternKeys sets string key interning and returns the modified config
+func (c Config) WithInternKeys(enabled bool) Config {
+	c.InternKeys = enabled
+	return c
+}
+
+// WithCoalesceShrinks sets shrink request coalescing and returns the modified config
+func (c Config) WithCoalesceShrinks(enabled bool) Config {
+	c.CoalesceShrinks = enabled
+	return c
+}
+
+// WithShrinkSchedule sets the shrink windows and returns the modified config
+func (c Config) WithShrinkSchedule(schedule string) Config {
+	c.ShrinkSchedule = schedule
+	return c
+}
+
+// WithRedactKey sets the function masking keys in errors and returns the modified config
+func (c Config) WithRedactKey(redact func(key any) any) Config {
+	c.RedactKey = redact
+	return c
+}
+
+// WithRecordAPIErrors sets automatic recording of API errors and returns the modified config
+func (c Config) WithRecordAPIErrors(enabled bool) Config {
+	c.RecordAPIErrors = enabled
+	return c
+}
+
+// WithPeakShrinkFactor sets the peak-to-live shrink trigger and returns the modified config
+func (c Config) WithPeakShrinkFactor(factor float64) Config {
+	c.PeakShrinkFactor = factor
+	return c
+}
+
 // Validate checks if the configuration is valid
 func (c Config) Validate() error {
 	if c.ShrinkInterval <= 0 {
@@ -118,5 +576,89 @@ func (c Config) Validate() error {
 	if c.CapacityGrowthFactor <= 1 {
 		return fmt.Errorf("capacity growth factor must be greater than 1")
 	}
+	if c.ReleaseOSMemoryThreshold < 0 {
+		return fmt.Errorf("release OS memory threshold must be non-negative")
+	}
+	if err := c.Health.Validate(); err != nil {
+		return err
+	}
+	if c.SlowOpThreshold < 0 {
+		return fmt.Errorf("slow operation threshold must be non-negative")
+	}
+	if c.SlowOpThreshold > 0 && c.OnSlowOp == nil {
+		return fmt.Errorf("slow operation callback must be set with a threshold")
+	}
+	if c.MaxValueBytes < 0 {
+		return fmt.Errorf("maximum value size must be non-negative")
+	}
+	if c.HistoryRetention < 0 {
+		return fmt.Errorf("history retention must be non-negative")
+	}
+	if c.HistoryMaxVersions < 0 {
+		return fmt.Errorf("history max versions must be non-negative")
+	}
+	switch c.Eviction {
+	case EvictNone:
+	case EvictFIFO:
+		if c.MaxEntries <= 0 {
+			return fmt.Errorf("FIFO eviction requires positive max entries")
+		}
+	case EvictAge:
+		if c.MaxEntryAge <= 0 {
+			return fmt.Errorf("age eviction requires positive max entry age")
+		}
+	default:
+		return fmt.Errorf("unknown eviction policy %d", c.Eviction)
+	}
+	if c.SweepBudget.MaxEntries < 0 || c.SweepBudget.MaxDuration < 0 {
+		return fmt.Errorf("sweep budget must be non-negative")
+	}
+	if len(c.AgeRules) > 0 && c.Eviction != EvictAge {
+		return fmt.Errorf("age rules require age eviction")
+	}
+	for _, rule := range c.AgeRules {
+		if (rule.Prefix == "") == (rule.Pattern == "") {
+			return fmt.Errorf("age rule needs exactly one of prefix and pattern")
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid age rule pattern %q: %w", rule.Pattern, err)
+		}
+		if rule.MaxAge < 0 {
+			return fmt.Errorf("age rule max age must be non-negative")
+		}
+	}
+	if (c.CopyOnWrite || c.CopyOnRead) && c.CloneValue == nil {
+		return fmt.Errorf("clone function must be set to copy values")
+	}
+	if c.GrowthHysteresis < 0 {
+		return fmt.Errorf("growth hysteresis must be non-negative")
+	}
+	if c.ColdAfter < 0 {
+		return fmt.Errorf("cold after must be non-negative")
+	}
+	if c.ShrinkLoopMaxRestarts < 0 {
+		return fmt.Errorf("shrink loop max restarts must be non-negative")
+	}
+	if c.ShrinkLoopRestartBackoff < 0 {
+		return fmt.Errorf("shrink loop restart backoff must be non-negative")
+	}
+	if c.PeakShrinkFactor != 0 && c.PeakShrinkFactor <= 1 {
+		return fmt.Errorf("peak shrink factor must be greater than 1")
+	}
+	if c.ShrinkSchedule != "" {
+		if _, err := parseShrinkSchedule(c.ShrinkSchedule); err != nil {
+			return err
+		}
+	}
+	for _, rule := range c.Alerts {
+		if err := rule.Validate(); err != nil {
+			return err
+		}
+	}
+	for k := range c.Labels {
+		if k == "" {
+			return fmt.Errorf("label names must not be empty")
+		}
+	}
 	return nil
 }
@@ -0,0 +1,207 @@
+// Package crdt provides conflict-free replicated maps built on ShrinkableMap.
+// Replicas are updated independently and converge once they have merged each
+// other's state, regardless of the order in which merges happen.
+package crdt
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// Register is a last-writer-wins register holding one entry's value and the
+// metadata needed to resolve conflicts
+type Register[V any] struct {
+	Value     V
+	Timestamp int64 // hybrid logical clock in nanoseconds
+	Node      string
+	Deleted   bool
+}
+
+// wins reports whether r takes precedence over other. Higher timestamps win,
+// ties are broken by node id and then in favor of deletion, so every replica
+// picks the same register.
+func (r Register[V]) wins(other Register[V]) bool {
+	if r.Timestamp != other.Timestamp {
+		return r.Timestamp > other.Timestamp
+	}
+	if r.Node != other.Node {
+		return r.Node > other.Node
+	}
+	return r.Deleted && !other.Deleted
+}
+
+// Entry is a key with its register, as exchanged between replicas
+type Entry[K comparable, V any] struct {
+	Key      K
+	Register Register[V]
+}
+
+// LWWConfig defines a last-writer-wins replica
+type LWWConfig struct {
+	// Unique identifier of this replica
+	Node string
+
+	// How long tombstones of deleted keys are kept. Replicas must merge more
+	// often than this, or deleted keys can be resurrected by stale replicas.
+	TombstoneTTL time.Duration
+
+	// Configuration of the underlying map
+	Map shrinkmap.Config
+}
+
+// Validate checks if the configuration is valid
+func (c LWWConfig) Validate() error {
+	if c.Node == "" {
+		return fmt.Errorf("node id must not be empty")
+	}
+	if c.TombstoneTTL <= 0 {
+		return fmt.Errorf("tombstone TTL must be positive")
+	}
+	return c.Map.Validate()
+}
+
+// LWWMap is a replicated map resolving concurrent updates by last-writer-wins.
+// Reads are served by the underlying ShrinkableMap; writes and merges are
+// serialized so every register update is a compare-and-set.
+type LWWMap[K comparable, V any] struct {
+	node string
+	ttl  time.Duration
+	sm   *shrinkmap.ShrinkableMap[K, Register[V]]
+
+	mu        sync.Mutex
+	clock     int64
+	lastPurge time.Time
+	now       func() time.Time
+}
+
+// NewLWW creates an empty replica
+func NewLWW[K comparable, V any](config LWWConfig) (*LWWMap[K, V], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &LWWMap[K, V]{
+		node: config.Node,
+		ttl:  config.TombstoneTTL,
+		sm:   shrinkmap.New[K, Register[V]](config.Map),
+		now:  time.Now,
+	}, nil
+}
+
+// tick advances the hybrid logical clock past both wall time and every
+// timestamp observed so far. Must be called with m.mu held.
+func (m *LWWMap[K, V]) tick() int64 {
+	m.clock = max(m.now().UnixNano(), m.clock+1)
+	return m.clock
+}
+
+// Set stores value for key on this replica
+func (m *LWWMap[K, V]) Set(key K, value V) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.purgeIfDue()
+	return m.sm.SetChecked(key, Register[V]{Value: value, Timestamp: m.tick(), Node: m.node})
+}
+
+// Delete removes key on this replica, leaving a tombstone for TombstoneTTL
+// so the deletion propagates to other replicas
+func (m *LWWMap[K, V]) Delete(key K) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.purgeIfDue()
+	return m.sm.SetChecked(key, Register[V]{Timestamp: m.tick(), Node: m.node, Deleted: true})
+}
+
+// Get returns the current value for key
+func (m *LWWMap[K, V]) Get(key K) (V, bool) {
+	r, exists := m.sm.Get(key)
+	if !exists || r.Deleted {
+		var zero V
+		return zero, false
+	}
+	return r.Value, true
+}
+
+// State returns every register including tombstones, for sending to other replicas
+func (m *LWWMap[K, V]) State() []Entry[K, V] {
+	snapshot := m.sm.Snapshot()
+	entries := make([]Entry[K, V], len(snapshot))
+	for i, kv := range snapshot {
+		entries[i] = Entry[K, V]{Key: kv.Key, Register: kv.Value}
+	}
+	return entries
+}
+
+// Merge applies registers received from another replica, keeping the winning
+// register for every key, and returns how many registers were replaced
+func (m *LWWMap[K, V]) Merge(entries []Entry[K, V]) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.purgeIfDue()
+
+	applied := 0
+	for _, e := range entries {
+		m.clock = max(m.clock, e.Register.Timestamp)
+		if current, exists := m.sm.Get(e.Key); exists && !e.Register.wins(current) {
+			continue
+		}
+		if e.Register.Deleted && m.now().Sub(time.Unix(0, e.Register.Timestamp)) >= m.ttl {
+			// Expired tombstones would be purged right away
+			if m.sm.Delete(e.Key) {
+				applied++
+			}
+			continue
+		}
+		if err := m.sm.SetChecked(e.Key, e.Register); err != nil {
+			return applied, err
+		}
+		applied++
+	}
+	return applied, nil
+}
+
+// MergeFrom merges the state of another replica in the same process
+func (m *LWWMap[K, V]) MergeFrom(other *LWWMap[K, V]) (int, error) {
+	return m.Merge(other.State())
+}
+
+// PurgeTombstones removes tombstones older than TombstoneTTL and returns how
+// many were removed. It also runs automatically during writes.
+func (m *LWWMap[K, V]) PurgeTombstones() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.purge()
+}
+
+// purgeIfDue purges tombstones at most every TombstoneTTL/2. Must be called with m.mu held.
+func (m *LWWMap[K, V]) purgeIfDue() {
+	if m.now().Sub(m.lastPurge) >= m.ttl/2 {
+		m.purge()
+	}
+}
+
+// purge must be called with m.mu held
+func (m *LWWMap[K, V]) purge() int {
+	now := m.now()
+	m.lastPurge = now
+	purged := 0
+	for _, kv := range m.sm.Snapshot() {
+		if kv.Value.Deleted && now.Sub(time.Unix(0, kv.Value.Timestamp)) >= m.ttl {
+			m.sm.Delete(kv.Key)
+			purged++
+		}
+	}
+	return purged
+}
+
+// Map returns the underlying map of registers, e.g. for persistence or metrics
+func (m *LWWMap[K, V]) Map() *shrinkmap.ShrinkableMap[K, Register[V]] {
+	return m.sm
+}
+
+// Stop stops the underlying map
+func (m *LWWMap[K, V]) Stop() {
+	m.sm.Stop()
+}
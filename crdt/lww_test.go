@@ -0,0 +1,104 @@
+package crdt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func newReplica(t *testing.T, node string) *LWWMap[string, int] {
+	m, err := NewLWW[string, int](LWWConfig{
+		Node:         node,
+		TombstoneTTL: time.Minute,
+		Map:          shrinkmap.DefaultConfig(),
+	})
+	if err != nil {
+		t.Fatalf("NewLWW failed: %v", err)
+	}
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func TestLWWMap(t *testing.T) {
+	t.Run("Converges", func(t *testing.T) {
+		a, b := newReplica(t, "a"), newReplica(t, "b")
+
+		a.Set("x", 1)
+		b.Set("y", 2)
+		b.Set("x", 3) // later write wins
+		a.Delete("y")
+
+		a.MergeFrom(b)
+		b.MergeFrom(a)
+
+		for _, key := range []string{"x", "y"} {
+			va, oka := a.Get(key)
+			vb, okb := b.Get(key)
+			if va != vb || oka != okb {
+				t.Errorf("Replicas diverged on %s: a=%d,%v b=%d,%v", key, va, oka, vb, okb)
+			}
+		}
+		if v, _ := a.Get("x"); v != 3 {
+			t.Errorf("Expected last write x=3, got %d", v)
+		}
+		if _, ok := a.Get("y"); ok {
+			t.Error("Expected later delete of y to win")
+		}
+	})
+
+	t.Run("Deterministic Tie Break", func(t *testing.T) {
+		a, b := newReplica(t, "a"), newReplica(t, "b")
+		fixed := time.Unix(1000, 0)
+		a.now = func() time.Time { return fixed }
+		b.now = func() time.Time { return fixed }
+
+		a.Set("x", 1)
+		b.Set("x", 2)
+		a.MergeFrom(b)
+		b.MergeFrom(a)
+
+		if va, _ := a.Get("x"); va != 2 {
+			t.Errorf("Expected higher node id to win the tie, got %d", va)
+		}
+		if vb, _ := b.Get("x"); vb != 2 {
+			t.Errorf("Expected higher node id to win the tie, got %d", vb)
+		}
+	})
+
+	t.Run("Clock Advances Past Merged Writes", func(t *testing.T) {
+		a, b := newReplica(t, "a"), newReplica(t, "b")
+		b.now = func() time.Time { return time.Now().Add(time.Hour) }
+
+		b.Set("x", 1)
+		a.MergeFrom(b)
+		a.Set("x", 2)
+		b.MergeFrom(a)
+
+		if v, _ := b.Get("x"); v != 2 {
+			t.Errorf("Expected write after merge to win despite clock skew, got %d", v)
+		}
+	})
+
+	t.Run("Tombstone Purge", func(t *testing.T) {
+		a := newReplica(t, "a")
+		now := time.Now()
+		a.now = func() time.Time { return now }
+
+		a.Set("x", 1)
+		a.Delete("x")
+		if a.Map().Len() != 1 {
+			t.Fatal("Expected tombstone to be kept")
+		}
+		now = now.Add(2 * time.Minute)
+		if purged := a.PurgeTombstones(); purged != 1 || a.Map().Len() != 0 {
+			t.Errorf("Expected tombstone to be purged, purged=%d len=%d", purged, a.Map().Len())
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		if _, err := NewLWW[string, int](LWWConfig{TombstoneTTL: time.Minute, Map: shrinkmap.DefaultConfig()}); err == nil {
+			t.Error("Expected error for empty node id")
+		}
+	})
+}
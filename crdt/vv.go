@@ -0,0 +1,272 @@
+package crdt
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// Ordering is the causal relation between two version vectors
+type Ordering int
+
+const (
+	// Equal vectors describe the same history
+	Equal Ordering = iota
+	// Before means the vector is an ancestor of the other
+	Before
+	// After means the vector descends from the other
+	After
+	// Concurrent vectors were updated independently and conflict
+	Concurrent
+)
+
+func (o Ordering) String() string {
+	switch o {
+	case Equal:
+		return "equal"
+	case Before:
+		return "before"
+	case After:
+		return "after"
+	case Concurrent:
+		return "concurrent"
+	default:
+		return fmt.Sprintf("Ordering(%d)", int(o))
+	}
+}
+
+// VersionVector counts the updates each node has made to an entry
+type VersionVector map[string]uint64
+
+// Compare returns the causal ordering of v relative to other
+func (v VersionVector) Compare(other VersionVector) Ordering {
+	less, greater := false, false
+	for node, n := range v {
+		if m := other[node]; n > m {
+			greater = true
+		} else if n < m {
+			less = true
+		}
+	}
+	for node, m := range other {
+		if _, ok := v[node]; !ok && m > 0 {
+			less = true
+		}
+	}
+	switch {
+	case less && greater:
+		return Concurrent
+	case less:
+		return Before
+	case greater:
+		return After
+	default:
+		return Equal
+	}
+}
+
+// Merge returns the element-wise maximum of v and other
+func (v VersionVector) Merge(other VersionVector) VersionVector {
+	merged := v.Clone()
+	for node, n := range other {
+		if n > merged[node] {
+			merged[node] = n
+		}
+	}
+	return merged
+}
+
+// Clone returns a copy of v
+func (v VersionVector) Clone() VersionVector {
+	c := make(VersionVector, len(v))
+	for node, n := range v {
+		c[node] = n
+	}
+	return c
+}
+
+// Versioned is an entry value with its causal history
+type Versioned[V any] struct {
+	Value   V
+	Version VersionVector
+	Deleted bool
+}
+
+func (x Versioned[V]) clone() Versioned[V] {
+	x.Version = x.Version.Clone()
+	return x
+}
+
+// VersionedEntry is a key with its versioned value, as exchanged between replicas
+type VersionedEntry[K comparable, V any] struct {
+	Key       K
+	Versioned Versioned[V]
+}
+
+// Resolver decides the outcome of concurrent updates to key. The returned
+// Value and Deleted are kept; its Version is replaced by the merged vector.
+type Resolver[K comparable, V any] func(key K, local, remote Versioned[V]) Versioned[V]
+
+// VVConfig defines a version-vector replica
+type VVConfig[K comparable, V any] struct {
+	// Unique identifier of this replica
+	Node string
+
+	// Called for concurrent updates during Merge. If nil, the local value is
+	// kept and the conflicting keys are reported in MergeResult.
+	Resolve Resolver[K, V]
+
+	// Configuration of the underlying map
+	Map shrinkmap.Config
+}
+
+// Validate checks if the configuration is valid
+func (c VVConfig[K, V]) Validate() error {
+	if c.Node == "" {
+		return fmt.Errorf("node id must not be empty")
+	}
+	return c.Map.Validate()
+}
+
+// MergeResult summarizes a Merge
+type MergeResult[K comparable] struct {
+	// Number of entries replaced by newer remote versions
+	Applied int
+	// Number of concurrent updates passed to the resolver
+	Resolved int
+	// Keys with concurrent updates left unresolved because no resolver is set
+	Conflicts []K
+}
+
+// VVMap is a replicated map tracking causality with per-entry version
+// vectors. Unlike LWWMap it never discards a concurrent update silently:
+// conflicts are handed to the configured resolver or reported to the caller.
+// Tombstones of deleted keys are kept so deletions are ordered correctly
+// against concurrent updates.
+type VVMap[K comparable, V any] struct {
+	node    string
+	resolve Resolver[K, V]
+	sm      *shrinkmap.ShrinkableMap[K, Versioned[V]]
+	mu      sync.Mutex
+}
+
+// NewVV creates an empty replica
+func NewVV[K comparable, V any](config VVConfig[K, V]) (*VVMap[K, V], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &VVMap[K, V]{
+		node:    config.Node,
+		resolve: config.Resolve,
+		sm:      shrinkmap.New[K, Versioned[V]](config.Map),
+	}, nil
+}
+
+// update stores a local write descending from the current version of key
+func (m *VVMap[K, V]) update(key K, value V, deleted bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	current, _ := m.sm.Get(key)
+	version := current.Version.Clone()
+	version[m.node]++
+	return m.sm.SetChecked(key, Versioned[V]{Value: value, Version: version, Deleted: deleted})
+}
+
+// Set stores value for key on this replica
+func (m *VVMap[K, V]) Set(key K, value V) error {
+	return m.update(key, value, false)
+}
+
+// Delete removes key on this replica, leaving a tombstone
+func (m *VVMap[K, V]) Delete(key K) error {
+	var zero V
+	return m.update(key, zero, true)
+}
+
+// Get returns the current value for key
+func (m *VVMap[K, V]) Get(key K) (V, bool) {
+	x, exists := m.sm.Get(key)
+	if !exists || x.Deleted {
+		var zero V
+		return zero, false
+	}
+	return x.Value, true
+}
+
+// GetVersioned returns the value of key with its version vector, including tombstones
+func (m *VVMap[K, V]) GetVersioned(key K) (Versioned[V], bool) {
+	x, exists := m.sm.Get(key)
+	return x.clone(), exists
+}
+
+// State returns every entry including tombstones, for sending to other replicas
+func (m *VVMap[K, V]) State() []VersionedEntry[K, V] {
+	snapshot := m.sm.Snapshot()
+	entries := make([]VersionedEntry[K, V], len(snapshot))
+	for i, kv := range snapshot {
+		entries[i] = VersionedEntry[K, V]{Key: kv.Key, Versioned: kv.Value.clone()}
+	}
+	return entries
+}
+
+// Merge applies entries received from another replica. Entries descending
+// from the local version replace it, older ones are ignored and concurrent
+// ones are resolved.
+func (m *VVMap[K, V]) Merge(entries []VersionedEntry[K, V]) (MergeResult[K], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var result MergeResult[K]
+	for _, e := range entries {
+		remote := e.Versioned.clone()
+		local, exists := m.sm.Get(e.Key)
+		if !exists {
+			if err := m.sm.SetChecked(e.Key, remote); err != nil {
+				return result, err
+			}
+			result.Applied++
+			continue
+		}
+
+		switch remote.Version.Compare(local.Version) {
+		case Equal, Before:
+			continue
+		case After:
+			if err := m.sm.SetChecked(e.Key, remote); err != nil {
+				return result, err
+			}
+			result.Applied++
+		case Concurrent:
+			if m.resolve == nil {
+				result.Conflicts = append(result.Conflicts, e.Key)
+				continue
+			}
+			resolved := m.resolve(e.Key, local.clone(), remote.clone())
+			// The resolution descends from both sides and is a new write here
+			resolved.Version = local.Version.Merge(remote.Version)
+			resolved.Version[m.node]++
+			if err := m.sm.SetChecked(e.Key, resolved); err != nil {
+				return result, err
+			}
+			result.Resolved++
+		}
+	}
+	return result, nil
+}
+
+// MergeFrom merges the state of another replica in the same process
+func (m *VVMap[K, V]) MergeFrom(other *VVMap[K, V]) (MergeResult[K], error) {
+	return m.Merge(other.State())
+}
+
+// Map returns the underlying map of versioned values
+func (m *VVMap[K, V]) Map() *shrinkmap.ShrinkableMap[K, Versioned[V]] {
+	return m.sm
+}
+
+// Stop stops the underlying map
+func (m *VVMap[K, V]) Stop() {
+	m.sm.Stop()
+}
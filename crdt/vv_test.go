@@ -0,0 +1,106 @@
+package crdt
+
+import (
+	"testing"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func newVVReplica(t *testing.T, node string, resolve Resolver[string, int]) *VVMap[string, int] {
+	m, err := NewVV(VVConfig[string, int]{Node: node, Resolve: resolve, Map: shrinkmap.DefaultConfig()})
+	if err != nil {
+		t.Fatalf("NewVV failed: %v", err)
+	}
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func TestVersionVector(t *testing.T) {
+	cases := []struct {
+		a, b     VersionVector
+		expected Ordering
+	}{
+		{VersionVector{"a": 1}, VersionVector{"a": 1}, Equal},
+		{VersionVector{"a": 1}, VersionVector{"a": 2}, Before},
+		{VersionVector{"a": 2, "b": 1}, VersionVector{"a": 2}, After},
+		{VersionVector{"a": 1}, VersionVector{"b": 1}, Concurrent},
+		{VersionVector{}, VersionVector{"a": 0}, Equal},
+	}
+	for _, c := range cases {
+		if got := c.a.Compare(c.b); got != c.expected {
+			t.Errorf("%v.Compare(%v) = %s, expected %s", c.a, c.b, got, c.expected)
+		}
+	}
+}
+
+func TestVVMap(t *testing.T) {
+	t.Run("Causal Update Replaces", func(t *testing.T) {
+		a, b := newVVReplica(t, "a", nil), newVVReplica(t, "b", nil)
+
+		a.Set("x", 1)
+		b.MergeFrom(a)
+		b.Set("x", 2)
+		result, _ := a.MergeFrom(b)
+
+		if v, _ := a.Get("x"); v != 2 || result.Applied != 1 || len(result.Conflicts) != 0 {
+			t.Errorf("Expected descendant update to apply, got x=%d result=%+v", v, result)
+		}
+
+		// Merging stale state back is a no-op
+		a.Set("x", 3)
+		result, _ = a.MergeFrom(b)
+		if v, _ := a.Get("x"); v != 3 || result.Applied != 0 {
+			t.Errorf("Expected older version to be ignored, got x=%d", v)
+		}
+	})
+
+	t.Run("Concurrent Updates Reported", func(t *testing.T) {
+		a, b := newVVReplica(t, "a", nil), newVVReplica(t, "b", nil)
+
+		a.Set("x", 1)
+		b.Set("x", 2)
+		result, _ := a.MergeFrom(b)
+
+		if len(result.Conflicts) != 1 || result.Conflicts[0] != "x" {
+			t.Errorf("Expected conflict on x, got %+v", result)
+		}
+		if v, _ := a.Get("x"); v != 1 {
+			t.Errorf("Expected local value to be kept, got %d", v)
+		}
+	})
+
+	t.Run("Concurrent Updates Resolved", func(t *testing.T) {
+		sum := func(key string, local, remote Versioned[int]) Versioned[int] {
+			return Versioned[int]{Value: local.Value + remote.Value}
+		}
+		a, b := newVVReplica(t, "a", sum), newVVReplica(t, "b", sum)
+
+		a.Set("x", 1)
+		b.Set("x", 2)
+		result, _ := a.MergeFrom(b)
+		if result.Resolved != 1 {
+			t.Fatalf("Expected one resolved conflict, got %+v", result)
+		}
+		b.MergeFrom(a)
+
+		va, _ := a.Get("x")
+		vb, _ := b.Get("x")
+		if va != 3 || vb != 3 {
+			t.Errorf("Expected replicas to converge on resolved value 3, got a=%d b=%d", va, vb)
+		}
+	})
+
+	t.Run("Delete Concurrent With Update", func(t *testing.T) {
+		a, b := newVVReplica(t, "a", nil), newVVReplica(t, "b", nil)
+
+		a.Set("x", 1)
+		b.MergeFrom(a)
+		a.Delete("x")
+		b.Set("x", 2)
+
+		result, _ := a.MergeFrom(b)
+		if len(result.Conflicts) != 1 {
+			t.Errorf("Expected delete and update to conflict, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package shrinkmap
+
+// Cursor iterates like database/sql.Rows: Next advances to the next entry and
+// Key, Value and Entry return the current entry without moving, so they may
+// be called any number of times. Iterator advances on Get instead; both
+// iterate over a snapshot taken when they were created.
+type Cursor[K comparable, V any] struct {
+	it      *Iterator[K, V]
+	current KeyValue[K, V]
+	valid   bool
+}
+
+// NewCursor creates a cursor over a snapshot of the map. Its snapshot buffer
+// is pooled; call Release when done.
+func (sm *ShrinkableMap[K, V]) NewCursor() *Cursor[K, V] {
+	return sm.NewIterator().Cursor()
+}
+
+// Cursor returns a cursor over the remaining entries of the iterator, which
+// may be derived with combinators such as Filter. The cursor consumes the
+// iterator, so the iterator must not be used directly afterwards.
+func (it *Iterator[K, V]) Cursor() *Cursor[K, V] {
+	return &Cursor[K, V]{it: it}
+}
+
+// Next advances to the next entry and reports whether there is one.
+// It must be called before the first entry is read.
+func (c *Cursor[K, V]) Next() bool {
+	c.valid = c.it.Next()
+	if c.valid {
+		c.current.Key, c.current.Value = c.it.Get()
+	} else {
+		c.current = KeyValue[K, V]{}
+	}
+	return c.valid
+}
+
+// Key returns the key of the current entry, or the zero key if Next has not
+// been called or returned false
+func (c *Cursor[K, V]) Key() K {
+	return c.current.Key
+}
+
+// Value returns the value of the current entry, or the zero value if Next has
+// not been called or returned false
+func (c *Cursor[K, V]) Value() V {
+	return c.current.Value
+}
+
+// Entry returns the current entry and whether there is one
+func (c *Cursor[K, V]) Entry() (KeyValue[K, V], bool) {
+	return c.current, c.valid
+}
+
+// Reset restarts the iteration from the beginning of the same snapshot.
+// Next must be called again before reading an entry.
+func (c *Cursor[K, V]) Reset() {
+	c.it.Reset()
+	c.current = KeyValue[K, V]{}
+	c.valid = false
+}
+
+// Release returns the snapshot buffer of a cursor created by NewCursor to the
+// map's pool, like Iterator.Release. The cursor is exhausted afterwards.
+func (c *Cursor[K, V]) Release() {
+	c.it.Release()
+	c.current = KeyValue[K, V]{}
+	c.valid = false
+}
@@ -0,0 +1,67 @@
+package shrinkmap
+
+import "testing"
+
+func TestCursor(t *testing.T) {
+	sm := New[int, int](DefaultConfig())
+	defer sm.Stop()
+	for i := 0; i < 10; i++ {
+		sm.Set(i, i*10)
+	}
+
+	t.Run("Accessors Are Idempotent", func(t *testing.T) {
+		c := sm.NewCursor()
+		defer c.Release()
+
+		if _, ok := c.Entry(); ok {
+			t.Error("Expected no current entry before Next")
+		}
+		count := 0
+		for c.Next() {
+			// Reading twice must not skip entries
+			if c.Key() != c.Key() || c.Value() != c.Key()*10 || c.Value() != c.Value() {
+				t.Errorf("Inconsistent entry %d=%d", c.Key(), c.Value())
+			}
+			count++
+		}
+		if count != 10 {
+			t.Errorf("Expected 10 entries, got %d", count)
+		}
+		if kv, ok := c.Entry(); ok || kv.Key != 0 {
+			t.Errorf("Expected no current entry after the end, got %v", kv)
+		}
+	})
+
+	t.Run("Reset", func(t *testing.T) {
+		c := sm.NewCursor()
+		defer c.Release()
+		for c.Next() {
+		}
+
+		c.Reset()
+		if _, ok := c.Entry(); ok {
+			t.Error("Expected no current entry after Reset")
+		}
+		count := 0
+		for c.Next() {
+			count++
+		}
+		if count != 10 {
+			t.Errorf("Expected 10 entries after Reset, got %d", count)
+		}
+	})
+
+	t.Run("Derived Iterator", func(t *testing.T) {
+		it := sm.NewIterator()
+		defer it.Release()
+
+		c := it.Filter(func(k, _ int) bool { return k%2 == 0 }).Cursor()
+		sum := 0
+		for c.Next() {
+			sum += c.Key()
+		}
+		if sum != 0+2+4+6+8 {
+			t.Errorf("Expected sum of even keys 20, got %d", sum)
+		}
+	})
+}
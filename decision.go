@@ -0,0 +1,161 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DecisionReason explains the outcome of a shrink attempt
+type DecisionReason int
+
+const (
+	// DecisionNone means no shrink has been attempted yet
+	DecisionNone DecisionReason = iota
+	// DecisionShrunk means the map was shrunk
+	DecisionShrunk
+	// DecisionEmpty means the map held no items worth compacting
+	DecisionEmpty
+	// DecisionRatioBelowThreshold means the deleted ratio had not reached Config.ShrinkRatio
+	DecisionRatioBelowThreshold
+	// DecisionIntervalNotElapsed means Config.MinShrinkInterval had not passed since the last shrink
+	DecisionIntervalNotElapsed
+	// DecisionOutsideWindow means a due shrink was postponed to the next Config.ShrinkSchedule window
+	DecisionOutsideWindow
+	// DecisionAlreadyShrinking means another shrink was in progress
+	DecisionAlreadyShrinking
+	// DecisionBudgetExceeded means a ForceShrinkWithOptions budget ran out or would have
+	DecisionBudgetExceeded
+	// DecisionSuperseded means the data was replaced during an incremental shrink
+	DecisionSuperseded
+	// DecisionGrowing means the map was growing fast enough within
+	// Config.GrowthHysteresis to outgrow the shrunk map
+	DecisionGrowing
+)
+
+// String returns a human readable name for the reason
+func (r DecisionReason) String() string {
+	switch r {
+	case DecisionNone:
+		return "none"
+	case DecisionShrunk:
+		return "shrunk"
+	case DecisionEmpty:
+		return "empty map"
+	case DecisionRatioBelowThreshold:
+		return "ratio below threshold"
+	case DecisionIntervalNotElapsed:
+		return "min interval not elapsed"
+	case DecisionOutsideWindow:
+		return "outside shrink window"
+	case DecisionAlreadyShrinking:
+		return "already shrinking"
+	case DecisionBudgetExceeded:
+		return "budget exceeded"
+	case DecisionSuperseded:
+		return "superseded"
+	case DecisionGrowing:
+		return "growing"
+	default:
+		return fmt.Sprintf("DecisionReason(%d)", int(r))
+	}
+}
+
+// Decision records why the last TryShrink or ForceShrink did or did not shrink
+// the map, together with the inputs it was based on
+type Decision struct {
+	Reason            DecisionReason
+	Forced            bool // the attempt came from ForceShrink and ignored ratio and interval
+	Time              time.Time
+	ItemCount         int64
+	DeletedCount      int64
+	DeletedRatio      float64
+	ShrinkRatio       float64
+	SinceLastShrink   time.Duration
+	MinShrinkInterval time.Duration
+	PeakLen           int64 // entries the map is allocated for, as in Stats
+	Oversized         bool  // PeakLen reached Config.PeakShrinkFactor times the live entries
+	Growth            int64 // net keys added within Config.GrowthHysteresis
+}
+
+// ShrinkDecision reports the outcome of the most recent shrink attempt,
+// including attempts made by the auto-shrink goroutine. Reason is DecisionNone
+// if no attempt has been made.
+func (sm *ShrinkableMap[K, V]) ShrinkDecision() Decision {
+	sm.decision.mu.Lock()
+	defer sm.decision.mu.Unlock()
+	if sm.decision.decided {
+		return sm.decision.d
+	}
+	return Decision{ShrinkRatio: sm.config.ShrinkRatio, MinShrinkInterval: sm.config.MinShrinkInterval}
+}
+
+// evaluateShrink captures the shrink inputs and, unless forced, the reason a
+// shrink should not run. Reason is DecisionShrunk when the conditions are met.
+func (sm *ShrinkableMap[K, V]) evaluateShrink(forced bool) Decision {
+	now := time.Now()
+	d := Decision{
+		Reason:            DecisionShrunk,
+		Forced:            forced,
+		Time:              now,
+		ItemCount:         sm.itemCount.Load(),
+		DeletedCount:      sm.deletedCount.Load(),
+		ShrinkRatio:       sm.config.ShrinkRatio,
+		SinceLastShrink:   now.Sub(sm.lastShrinkTime.Load().(time.Time)),
+		MinShrinkInterval: sm.config.MinShrinkInterval,
+	}
+	if d.ItemCount == 0 {
+		if !forced {
+			d.Reason = DecisionEmpty
+		}
+		return d
+	}
+	d.DeletedRatio = float64(d.DeletedCount) / float64(d.ItemCount)
+	d.PeakLen = sm.sizeHint.Load()
+	d.Oversized = sm.oversized(d.PeakLen, d.ItemCount-d.DeletedCount)
+	d.Growth = sm.growth.growth(now)
+
+	switch {
+	case forced:
+	case d.DeletedRatio < d.ShrinkRatio && !d.Oversized:
+		d.Reason = DecisionRatioBelowThreshold
+	case d.SinceLastShrink < d.MinShrinkInterval:
+		d.Reason = DecisionIntervalNotElapsed
+	case !sm.schedule.allows(now):
+		d.Reason = DecisionOutsideWindow
+	case sm.regrowing(d.ItemCount-d.DeletedCount, d.Growth):
+		d.Reason = DecisionGrowing
+	}
+	return d
+}
+
+// oversized reports whether a map allocated for peak entries holds few
+// enough live entries to trigger a shrink under Config.PeakShrinkFactor. This
+// catches maps that grew large once and have since been emptied, even when
+// the deletions were not counted since the last shrink, e.g. after a Publish.
+func (sm *ShrinkableMap[K, V]) oversized(peak, live int64) bool {
+	factor := sm.config.PeakShrinkFactor
+	if factor <= 0 || float64(peak) < factor*float64(max(live, 1)) {
+		return false
+	}
+	// Only worth it if a shrink would actually allocate fewer buckets
+	return estimateBuckets(int64(sm.shrinkTargetSize(live))) < estimateBuckets(peak)
+}
+
+// decisionSlot holds the outcome of the last shrink attempt. Every Delete
+// makes an attempt, so the decision is updated in place instead of being
+// published as a newly allocated value.
+type decisionSlot struct {
+	mu      sync.Mutex
+	d       Decision
+	decided bool
+}
+
+// recordDecision publishes the outcome of a shrink attempt
+func (sm *ShrinkableMap[K, V]) recordDecision(d Decision, reason DecisionReason) {
+	d.Reason = reason
+	sm.decision.mu.Lock()
+	sm.decision.d = d
+	sm.decision.decided = true
+	sm.decision.mu.Unlock()
+}
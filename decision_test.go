@@ -0,0 +1,163 @@
+package shrinkmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShrinkDecision(t *testing.T) {
+	newMap := func(interval time.Duration) *ShrinkableMap[int, int] {
+		config := DefaultConfig().WithAutoShrinkEnabled(false).WithShrinkRatio(0.5).WithMinShrinkInterval(interval)
+		return New[int, int](config)
+	}
+
+	t.Run("No Attempt", func(t *testing.T) {
+		sm := newMap(0)
+		defer sm.Stop()
+
+		if d := sm.ShrinkDecision(); d.Reason != DecisionNone || d.ShrinkRatio != 0.5 {
+			t.Errorf("Unexpected decision: %+v", d)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		sm := newMap(0)
+		defer sm.Stop()
+
+		sm.TryShrink()
+		if d := sm.ShrinkDecision(); d.Reason != DecisionEmpty {
+			t.Errorf("Expected %v, got %v", DecisionEmpty, d.Reason)
+		}
+	})
+
+	t.Run("Ratio Below Threshold", func(t *testing.T) {
+		sm := newMap(0)
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i)
+		}
+		sm.Delete(0)
+
+		if sm.TryShrink() {
+			t.Fatal("Expected no shrink")
+		}
+		d := sm.ShrinkDecision()
+		if d.Reason != DecisionRatioBelowThreshold || d.DeletedRatio != 0.1 || d.ItemCount != 10 {
+			t.Errorf("Unexpected decision: %+v", d)
+		}
+	})
+
+	t.Run("Interval Not Elapsed", func(t *testing.T) {
+		sm := newMap(time.Hour)
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 8; i++ {
+			sm.Delete(i)
+		}
+
+		sm.TryShrink()
+		d := sm.ShrinkDecision()
+		if d.Reason != DecisionIntervalNotElapsed || d.SinceLastShrink >= time.Hour {
+			t.Errorf("Unexpected decision: %+v", d)
+		}
+	})
+
+	t.Run("Shrunk", func(t *testing.T) {
+		sm := newMap(0)
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 8; i++ {
+			sm.Delete(i)
+		}
+
+		if !sm.TryShrink() {
+			t.Fatal("Expected shrink")
+		}
+		if d := sm.ShrinkDecision(); d.Reason != DecisionShrunk || d.Forced {
+			t.Errorf("Unexpected decision: %+v", d)
+		}
+
+		sm.ForceShrink()
+		if d := sm.ShrinkDecision(); d.Reason != DecisionShrunk || !d.Forced {
+			t.Errorf("Unexpected forced decision: %+v", d)
+		}
+	})
+
+	t.Run("Already Shrinking", func(t *testing.T) {
+		sm := newMap(0)
+		defer sm.Stop()
+		sm.Set(1, 1)
+		sm.shrinking.Store(true)
+		defer sm.shrinking.Store(false)
+
+		if sm.ForceShrink() {
+			t.Fatal("Expected no shrink")
+		}
+		if d := sm.ShrinkDecision(); d.Reason != DecisionAlreadyShrinking {
+			t.Errorf("Expected %v, got %v", DecisionAlreadyShrinking, d.Reason)
+		}
+	})
+}
+
+func TestPeakShrinkFactor(t *testing.T) {
+	config := DefaultConfig().
+		WithAutoShrinkEnabled(false).
+		WithShrinkRatio(0.95).
+		WithMinShrinkInterval(time.Nanosecond)
+	fill := func(sm *ShrinkableMap[int, int]) {
+		for i := 0; i < 10000; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 9000; i++ {
+			sm.Delete(i)
+		}
+	}
+
+	t.Run("Triggers Below Ratio", func(t *testing.T) {
+		sm := New[int, int](config.WithPeakShrinkFactor(4))
+		defer sm.Stop()
+		fill(sm)
+
+		if !sm.TryShrink() {
+			t.Fatalf("Expected an oversized map to shrink, got %v", sm.ShrinkDecision().Reason)
+		}
+		if d := sm.ShrinkDecision(); !d.Oversized || d.PeakLen != 10000 {
+			t.Errorf("Expected an oversized decision at peak 10000, got %+v", d)
+		}
+		// Freshly shrunk, the map is no longer oversized
+		if sm.TryShrink() {
+			t.Error("Expected no further shrink")
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		fill(sm)
+
+		if sm.TryShrink() {
+			t.Error("Expected no shrink below the ratio without PeakShrinkFactor")
+		}
+	})
+
+	t.Run("Not Worth Shrinking", func(t *testing.T) {
+		// The initial capacity keeps the allocation, so a shrink would not help
+		sm := New[int, int](config.WithPeakShrinkFactor(4).WithInitialCapacity(20000))
+		defer sm.Stop()
+		fill(sm)
+
+		if sm.TryShrink() {
+			t.Error("Expected no shrink when the target allocation is not smaller")
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := DefaultConfig().WithPeakShrinkFactor(0.5).Validate(); err == nil {
+			t.Error("Expected error for a factor below 1")
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package shrinkmap
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// DigestOptions controls the shape of a Digest. Replicas can only compare
+// digests built with the same options.
+type DigestOptions[K comparable, V any] struct {
+	// Encodes entries for hashing; keys are bucketed by the encoding of the key
+	// with a zero value. Defaults to GobCodec.
+	Codec Codec[K, V]
+
+	// Number of levels below the root (default 3)
+	Depth int
+
+	// Number of children per node (default 16)
+	Fanout int
+}
+
+func (o DigestOptions[K, V]) withDefaults() (DigestOptions[K, V], error) {
+	if o.Codec == nil {
+		o.Codec = GobCodec[K, V]{}
+	}
+	if o.Depth == 0 {
+		o.Depth = 3
+	}
+	if o.Fanout == 0 {
+		o.Fanout = 16
+	}
+	if o.Depth < 1 || o.Fanout < 2 {
+		return o, fmt.Errorf("digest depth must be positive and fanout at least 2")
+	}
+	leaves := 1
+	for i := 0; i < o.Depth; i++ {
+		leaves *= o.Fanout
+		if leaves > 1<<24 {
+			return o, fmt.Errorf("digest with depth %d and fanout %d has too many buckets", o.Depth, o.Fanout)
+		}
+	}
+	return o, nil
+}
+
+// Digest is a Merkle tree over the entries of a map. The keys are spread
+// over leaf buckets by hash range; every leaf hashes the entries in its
+// range and every inner node hashes its children. Two replicas can compare
+// digests top-down and exchange only the buckets that differ.
+type Digest struct {
+	Fanout int
+	// Levels[0] holds the root hash; Levels[len(Levels)-1] holds the leaf hashes
+	Levels [][]uint64
+}
+
+// Root returns the hash of the whole map
+func (d *Digest) Root() uint64 {
+	return d.Levels[0][0]
+}
+
+// Diff returns the indexes of the leaf buckets that differ between d and
+// other, descending only into subtrees whose hashes differ
+func (d *Digest) Diff(other *Digest) ([]int, error) {
+	if d.Fanout != other.Fanout || len(d.Levels) != len(other.Levels) {
+		return nil, fmt.Errorf("digests have different shapes")
+	}
+	nodes := []int{0}
+	for level := range d.Levels {
+		var differing []int
+		for _, n := range nodes {
+			if d.Levels[level][n] != other.Levels[level][n] {
+				differing = append(differing, n)
+			}
+		}
+		if level == len(d.Levels)-1 {
+			return differing, nil
+		}
+		nodes = nodes[:0:0]
+		for _, n := range differing {
+			for c := 0; c < d.Fanout; c++ {
+				nodes = append(nodes, n*d.Fanout+c)
+			}
+		}
+	}
+	return nil, nil
+}
+
+// Digest builds a Merkle digest of a snapshot of the map
+func (sm *ShrinkableMap[K, V]) Digest(opts DigestOptions[K, V]) (*Digest, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	leaves := make([]uint64, pow(opts.Fanout, opts.Depth))
+	for _, kv := range sm.Snapshot() {
+		leaf, err := digestBucket(opts, kv.Key, len(leaves))
+		if err != nil {
+			return nil, err
+		}
+		data, err := opts.Codec.EncodeEntry(kv.Key, kv.Value)
+		if err != nil {
+			return nil, err
+		}
+		// Entry hashes are mixed and summed so bucket hashes are independent of iteration order
+		leaves[leaf] += mix64(hash64(data))
+	}
+
+	levels := [][]uint64{leaves}
+	for len(levels[0]) > 1 {
+		children := levels[0]
+		parents := make([]uint64, len(children)/opts.Fanout)
+		buf := make([]byte, 8*opts.Fanout)
+		for i := range parents {
+			for c := 0; c < opts.Fanout; c++ {
+				binary.BigEndian.PutUint64(buf[8*c:], children[i*opts.Fanout+c])
+			}
+			parents[i] = hash64(buf)
+		}
+		levels = append([][]uint64{parents}, levels...)
+	}
+	return &Digest{Fanout: opts.Fanout, Levels: levels}, nil
+}
+
+// DigestBucket returns the entries that fall into the given leaf bucket of a
+// digest built with the same options
+func (sm *ShrinkableMap[K, V]) DigestBucket(opts DigestOptions[K, V], leaf int) ([]KeyValue[K, V], error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+	leaves := pow(opts.Fanout, opts.Depth)
+	if leaf < 0 || leaf >= leaves {
+		return nil, fmt.Errorf("digest bucket %d out of range [0, %d)", leaf, leaves)
+	}
+
+	var result []KeyValue[K, V]
+	for _, kv := range sm.Snapshot() {
+		bucket, err := digestBucket(opts, kv.Key, leaves)
+		if err != nil {
+			return nil, err
+		}
+		if bucket == leaf {
+			result = append(result, kv)
+		}
+	}
+	return result, nil
+}
+
+// digestBucket maps the hash of the encoded key onto one of the leaf ranges
+func digestBucket[K comparable, V any](opts DigestOptions[K, V], key K, leaves int) (int, error) {
+	var zero V
+	data, err := opts.Codec.EncodeEntry(key, zero)
+	if err != nil {
+		return 0, err
+	}
+	return int((hash64(data) >> 32) * uint64(leaves) >> 32), nil
+}
+
+func hash64(data []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	return h.Sum64()
+}
+
+// mix64 is the splitmix64 finalizer
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func pow(base, exp int) int {
+	result := 1
+	for i := 0; i < exp; i++ {
+		result *= base
+	}
+	return result
+}
@@ -0,0 +1,75 @@
+package shrinkmap
+
+import "testing"
+
+func TestDigest(t *testing.T) {
+	opts := DigestOptions[string, int]{Codec: JSONCodec[string, int]{}, Depth: 2, Fanout: 4}
+
+	newReplica := func() *ShrinkableMap[string, int] {
+		sm := New[string, int](DefaultConfig())
+		for i := 0; i < 100; i++ {
+			sm.Set(string(rune('a'+i%26))+string(rune('a'+i/26)), i)
+		}
+		return sm
+	}
+
+	t.Run("Equal Replicas", func(t *testing.T) {
+		a, b := newReplica(), newReplica()
+		defer a.Stop()
+		defer b.Stop()
+
+		da, err := a.Digest(opts)
+		if err != nil {
+			t.Fatalf("Digest failed: %v", err)
+		}
+		db, _ := b.Digest(opts)
+		if da.Root() != db.Root() {
+			t.Error("Expected equal roots for equal replicas")
+		}
+		if diff, _ := da.Diff(db); len(diff) != 0 {
+			t.Errorf("Expected no differing buckets, got %v", diff)
+		}
+		if len(da.Levels) != 3 || len(da.Levels[2]) != 16 {
+			t.Errorf("Unexpected digest shape: %d levels", len(da.Levels))
+		}
+	})
+
+	t.Run("Repair Differing Bucket", func(t *testing.T) {
+		a, b := newReplica(), newReplica()
+		defer a.Stop()
+		defer b.Stop()
+		b.Set("aa", -1)
+
+		da, _ := a.Digest(opts)
+		db, _ := b.Digest(opts)
+		diff, err := da.Diff(db)
+		if err != nil {
+			t.Fatalf("Diff failed: %v", err)
+		}
+		if len(diff) != 1 {
+			t.Fatalf("Expected exactly one differing bucket, got %v", diff)
+		}
+
+		entries, err := a.DigestBucket(opts, diff[0])
+		if err != nil {
+			t.Fatalf("DigestBucket failed: %v", err)
+		}
+		for _, kv := range entries {
+			b.Set(kv.Key, kv.Value)
+		}
+		db, _ = b.Digest(opts)
+		if da.Root() != db.Root() {
+			t.Error("Expected replicas to converge after repairing the bucket")
+		}
+	})
+
+	t.Run("Shape Mismatch", func(t *testing.T) {
+		a := newReplica()
+		defer a.Stop()
+		da, _ := a.Digest(opts)
+		other, _ := a.Digest(DigestOptions[string, int]{Codec: JSONCodec[string, int]{}})
+		if _, err := da.Diff(other); err == nil {
+			t.Error("Expected error comparing digests of different shapes")
+		}
+	})
+}
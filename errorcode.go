@@ -0,0 +1,109 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies recorded errors so benign rejections can be told
+// apart from real failures
+type ErrorCode int
+
+const (
+	// ErrCodeOther covers errors matching none of the other codes
+	ErrCodeOther ErrorCode = iota
+	ErrCodeStopped
+	ErrCodeWouldBlock
+	ErrCodeKeyNotFound
+	ErrCodeKeyExists
+	ErrCodeValueTooLarge
+	ErrCodeValidation
+	ErrCodeMemoryPressure
+	ErrCodeConditionFailed
+	ErrCodeCorruptSnapshot
+	ErrCodeSinkBufferFull
+	ErrCodeWatchChannelFull
+	ErrCodeShrinkBudgetExceeded
+	ErrCodeCanceled
+	// ErrCodePanic counts panics recovered by the map, such as in the shrink goroutine
+	ErrCodePanic
+	ErrCodeValueMutated
+	ErrCodeSinkPanic
+)
+
+// errorCodes maps sentinel errors to their codes, checked in order
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrMapStopped, ErrCodeStopped},
+	{ErrWouldBlock, ErrCodeWouldBlock},
+	{ErrKeyNotFound, ErrCodeKeyNotFound},
+	{ErrKeyExists, ErrCodeKeyExists},
+	{ErrValueTooLarge, ErrCodeValueTooLarge},
+	{ErrValidation, ErrCodeValidation},
+	{ErrMemoryPressure, ErrCodeMemoryPressure},
+	{ErrConditionFailed, ErrCodeConditionFailed},
+	{ErrCorruptSnapshot, ErrCodeCorruptSnapshot},
+	{ErrSinkBufferFull, ErrCodeSinkBufferFull},
+	{ErrWatchChannelFull, ErrCodeWatchChannelFull},
+	{ErrShrinkBudgetExceeded, ErrCodeShrinkBudgetExceeded},
+	{ErrValueMutated, ErrCodeValueMutated},
+	{ErrSinkPanicked, ErrCodeSinkPanic},
+	{context.Canceled, ErrCodeCanceled},
+	{context.DeadlineExceeded, ErrCodeCanceled},
+}
+
+// String returns a stable name for the code, suitable as a metric label
+func (c ErrorCode) String() string {
+	switch c {
+	case ErrCodeOther:
+		return "other"
+	case ErrCodeStopped:
+		return "stopped"
+	case ErrCodeWouldBlock:
+		return "would_block"
+	case ErrCodeKeyNotFound:
+		return "key_not_found"
+	case ErrCodeKeyExists:
+		return "key_exists"
+	case ErrCodeValueTooLarge:
+		return "value_too_large"
+	case ErrCodeValidation:
+		return "validation"
+	case ErrCodeMemoryPressure:
+		return "memory_pressure"
+	case ErrCodeConditionFailed:
+		return "condition_failed"
+	case ErrCodeCorruptSnapshot:
+		return "corrupt_snapshot"
+	case ErrCodeSinkBufferFull:
+		return "sink_buffer_full"
+	case ErrCodeWatchChannelFull:
+		return "watch_channel_full"
+	case ErrCodeShrinkBudgetExceeded:
+		return "shrink_budget_exceeded"
+	case ErrCodeCanceled:
+		return "canceled"
+	case ErrCodePanic:
+		return "panic"
+	case ErrCodeValueMutated:
+		return "value_mutated"
+	case ErrCodeSinkPanic:
+		return "sink_panic"
+	default:
+		return fmt.Sprintf("ErrorCode(%d)", int(c))
+	}
+}
+
+// ErrorCodeOf returns the code of the first sentinel error err matches with
+// errors.Is, or ErrCodeOther
+func ErrorCodeOf(err error) ErrorCode {
+	for _, e := range errorCodes {
+		if errors.Is(err, e.err) {
+			return e.code
+		}
+	}
+	return ErrCodeOther
+}
@@ -0,0 +1,54 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorCodes(t *testing.T) {
+	t.Run("Classification", func(t *testing.T) {
+		tests := []struct {
+			err  error
+			code ErrorCode
+		}{
+			{ErrMapStopped, ErrCodeStopped},
+			{&KeyError{Op: "set", Key: "k", Err: ErrMemoryPressure}, ErrCodeMemoryPressure},
+			{&ValidationError{Field: "key", Err: errors.New("bad")}, ErrCodeValidation},
+			{&BatchError{Err: ErrConditionFailed}, ErrCodeConditionFailed},
+			{corrupt(nil, "bad checksum"), ErrCodeCorruptSnapshot},
+			{fmt.Errorf("load: %w", context.DeadlineExceeded), ErrCodeCanceled},
+			{errors.New("boom"), ErrCodeOther},
+		}
+		for _, tt := range tests {
+			if got := ErrorCodeOf(tt.err); got != tt.code {
+				t.Errorf("%v: expected %v, got %v", tt.err, tt.code, got)
+			}
+		}
+	})
+
+	t.Run("Counted In Metrics", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		sm.metrics.RecordError(ErrSinkBufferFull, "")
+		sm.metrics.RecordError(ErrSinkBufferFull, "")
+		sm.metrics.RecordError(errors.New("boom"), "")
+		sm.metrics.RecordPanic("shrink failed", "")
+
+		metrics := sm.GetMetrics()
+		if metrics.ErrorCount(ErrCodeSinkBufferFull) != 2 || metrics.ErrorCount(ErrCodeOther) != 1 || metrics.ErrorCount(ErrCodePanic) != 1 {
+			t.Errorf("Unexpected counts: %v", metrics.ErrorsByCode())
+		}
+		if len(metrics.ErrorsByCode()) != 3 {
+			t.Errorf("Expected 3 codes, got %v", metrics.ErrorsByCode())
+		}
+
+		sm.metrics.Reset()
+		metrics = sm.GetMetrics()
+		if metrics.ErrorCount(ErrCodeSinkBufferFull) != 0 || len(metrics.ErrorsByCode()) != 0 {
+			t.Errorf("Expected counts cleared by Reset, got %v", metrics.ErrorsByCode())
+		}
+	})
+}
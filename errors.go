@@ -0,0 +1,90 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrMapStopped is returned by SetWait if the map is stopped while it waits
+var ErrMapStopped = errors.New("shrinkmap: map is stopped")
+
+// ErrWouldBlock is returned by the Try* operations when the map lock is held
+var ErrWouldBlock = errors.New("shrinkmap: operation would block")
+
+// ErrKeyNotFound is returned when an operation requires a key that is not present
+var ErrKeyNotFound = errors.New("shrinkmap: key not found")
+
+// ErrKeyExists is returned when an operation would overwrite an existing key
+var ErrKeyExists = errors.New("shrinkmap: key already exists")
+
+// KeyError records the operation and key that caused an error
+type KeyError struct {
+	Op  string
+	Key interface{}
+	Err error
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("%s %v: %v", e.Op, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error, such as ErrKeyNotFound
+func (e *KeyError) Unwrap() error {
+	return e.Err
+}
+
+// ErrValueTooLarge is returned when a value exceeds Config.MaxValueBytes
+var ErrValueTooLarge = errors.New("shrinkmap: value too large")
+
+// errorKey returns key as it should be recorded in errors, applying Config.RedactKey
+func (sm *ShrinkableMap[K, V]) errorKey(key K) interface{} {
+	if sm.config.RedactKey != nil {
+		return sm.config.RedactKey(key)
+	}
+	return key
+}
+
+// apiErrorsPerSecond limits the API errors added to the error history with
+// Config.RecordAPIErrors, so a burst of rejections cannot flood it
+const apiErrorsPerSecond = 10
+
+// apiError records err in the metrics if Config.RecordAPIErrors is set and returns it
+func (sm *ShrinkableMap[K, V]) apiError(err error) error {
+	if err != nil && sm.config.RecordAPIErrors {
+		sm.recordAPIError(err)
+	}
+	return err
+}
+
+// recordAPIError records err in the metrics, adding at most
+// apiErrorsPerSecond errors per second to the error history
+func (sm *ShrinkableMap[K, V]) recordAPIError(err error) {
+	if sm.apiErrorLimit.allow(time.Now()) {
+		sm.metrics.RecordError(err, "")
+	} else {
+		sm.metrics.countError(err)
+	}
+}
+
+// apiErrorLimiter allows apiErrorsPerSecond errors into the history per second
+type apiErrorLimiter struct {
+	mu    sync.Mutex
+	start time.Time
+	count int
+}
+
+func (l *apiErrorLimiter) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.start) >= time.Second {
+		l.start = now
+		l.count = 0
+	}
+	if l.count >= apiErrorsPerSecond {
+		return false
+	}
+	l.count++
+	return true
+}
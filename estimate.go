@@ -0,0 +1,52 @@
+package shrinkmap
+
+import (
+	"time"
+	"unsafe"
+)
+
+// ShrinkEstimate projects the effect and cost of shrinking the map now
+type ShrinkEstimate struct {
+	// Entries that would be copied into the new map
+	ItemsToCopy int64
+
+	// Estimated entry capacity before and after the shrink
+	CurrentCapacity int64
+	NewCapacity     int64
+
+	// Estimated bytes allocated for the new map while the old one is still
+	// live, and bytes freed once the old map is collected. Only the inline
+	// key and value sizes are counted, not memory they point to.
+	TransientBytes int64
+	ReclaimedBytes int64
+
+	// Copy time extrapolated from the last shrink; zero if the map has not
+	// been shrunk before
+	Duration time.Duration
+}
+
+// EstimateShrink reports what ForceShrink would do without doing it, based on
+// the current state and the last shrink's copy rate. The figures are estimates:
+// the runtime does not expose the size of a map's backing storage.
+func (sm *ShrinkableMap[K, V]) EstimateShrink() ShrinkEstimate {
+	items := sm.Len()
+	current := estimateBuckets(sm.sizeHint.Load())
+	next := estimateBuckets(max(int64(sm.shrinkTargetSize(items)), items))
+
+	return ShrinkEstimate{
+		ItemsToCopy:     items,
+		CurrentCapacity: current * mapMaxLoad,
+		NewCapacity:     next * mapMaxLoad,
+		TransientBytes:  next * groupBytes[K, V](),
+		ReclaimedBytes:  max(current-next, 0) * groupBytes[K, V](),
+		Duration:        sm.estimateCopyTime(items),
+	}
+}
+
+// groupBytes approximates the size of one slot group: a control word plus
+// mapGroupSlots inline key/value pairs
+func groupBytes[K comparable, V any]() int64 {
+	var k K
+	var v V
+	return 8 + mapGroupSlots*int64(unsafe.Sizeof(k)+unsafe.Sizeof(v))
+}
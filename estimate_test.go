@@ -0,0 +1,57 @@
+package shrinkmap
+
+import "testing"
+
+func TestEstimateShrink(t *testing.T) {
+	t.Run("Projects Reclaimed Capacity", func(t *testing.T) {
+		sm := New[int, int64](DefaultConfig().WithAutoShrinkEnabled(false).WithInitialCapacity(10))
+		defer sm.Stop()
+		for i := 0; i < 10000; i++ {
+			sm.Set(i, int64(i))
+		}
+		for i := 0; i < 9000; i++ {
+			sm.Delete(i)
+		}
+
+		est := sm.EstimateShrink()
+		if est.ItemsToCopy != 1000 {
+			t.Errorf("Expected 1000 items to copy, got %d", est.ItemsToCopy)
+		}
+		if est.NewCapacity >= est.CurrentCapacity || est.NewCapacity < 1000 {
+			t.Errorf("Unexpected capacities: %+v", est)
+		}
+		if est.ReclaimedBytes <= 0 || est.TransientBytes <= 0 {
+			t.Errorf("Expected positive byte estimates: %+v", est)
+		}
+		if est.Duration != 0 {
+			t.Errorf("Expected no duration without shrink history, got %v", est.Duration)
+		}
+
+		// The estimate must match what a shrink actually allocates
+		sm.ForceShrink()
+		if stats := sm.Stats(); stats.EstimatedCapacity != est.NewCapacity {
+			t.Errorf("Expected capacity %d after shrink, got %d", est.NewCapacity, stats.EstimatedCapacity)
+		}
+	})
+
+	t.Run("Duration From History", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+		for i := 0; i < 1000; i++ {
+			sm.Set(i, i)
+		}
+		sm.ForceShrink()
+
+		metrics := sm.GetMetrics()
+		if metrics.LastShrinkItems() != 1000 {
+			t.Fatalf("Expected 1000 items copied, got %d", metrics.LastShrinkItems())
+		}
+		for i := 1000; i < 3000; i++ {
+			sm.Set(i, i)
+		}
+		est := sm.EstimateShrink()
+		if expected := metrics.LastShrinkDuration() * 3; est.Duration < expected-3 || est.Duration > expected+3 {
+			t.Errorf("Expected duration about %v, got %v", expected, est.Duration)
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package shrinkmap
+
+import "fmt"
+
+// EvictionReason tells why entries were evicted from a LinkedShrinkableMap
+type EvictionReason int
+
+const (
+	// EvictedExpired means the entries were older than their maximum age under EvictAge
+	EvictedExpired EvictionReason = iota
+	// EvictedOverCapacity means a write exceeded MaxEntries under EvictFIFO
+	EvictedOverCapacity
+	// EvictedTrimmed means the entries were removed by TrimOldest or TrimToSize
+	EvictedTrimmed
+)
+
+// String returns a human readable name for the reason
+func (r EvictionReason) String() string {
+	switch r {
+	case EvictedExpired:
+		return "expired"
+	case EvictedOverCapacity:
+		return "over capacity"
+	case EvictedTrimmed:
+		return "trimmed"
+	default:
+		return fmt.Sprintf("EvictionReason(%d)", int(r))
+	}
+}
+
+// OnEvictBatch sets fn to be called with the entries removed by each
+// eviction sweep, oldest first, so cleanup of many entries expiring or
+// evicted at once can be batched as well. It replaces any previous callback;
+// nil removes it. fn is called with the map locked and must not use the map;
+// slow cleanup should be handed off to another goroutine.
+func (lm *LinkedShrinkableMap[K, V]) OnEvictBatch(fn func(entries []KeyValue[K, V], reason EvictionReason)) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	lm.onEvict = fn
+}
@@ -0,0 +1,148 @@
+package shrinkmap_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+func Example() {
+	sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+	defer sm.Stop()
+
+	sm.Set("a", 1)
+	sm.Set("b", 2)
+	sm.Delete("a")
+
+	value, ok := sm.Get("b")
+	fmt.Println(value, ok, sm.Len())
+	// Output: 2 true 1
+}
+
+func ExampleConfig() {
+	config := shrinkmap.DefaultConfig().
+		WithShrinkInterval(time.Minute).
+		WithShrinkRatio(0.5).
+		WithInitialCapacity(1024)
+	fmt.Println(config.Validate())
+	fmt.Println(config.WithShrinkRatio(2).Validate() != nil)
+	// Output:
+	// <nil>
+	// true
+}
+
+func ExampleShrinkableMap_NewSortedIterator() {
+	sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+	defer sm.Stop()
+	for i, key := range []string{"c", "a", "d", "b"} {
+		sm.Set(key, i)
+	}
+
+	it := sm.NewSortedIterator(func(a, b string) bool { return a < b })
+	defer it.Release()
+	for it.Next() {
+		key, value := it.Get()
+		fmt.Println(key, value)
+	}
+	// Output:
+	// a 1
+	// b 3
+	// c 0
+	// d 2
+}
+
+func ExampleIterator_Filter() {
+	sm := shrinkmap.New[int, int](shrinkmap.DefaultConfig())
+	defer sm.Stop()
+	for i := 0; i < 10; i++ {
+		sm.Set(i, i*i)
+	}
+
+	it := sm.NewIterator()
+	defer it.Release()
+	odd := it.Filter(func(key, _ int) bool { return key%2 == 1 }).Collect()
+	fmt.Println(len(odd))
+	// Output: 5
+}
+
+func ExampleShrinkableMap_ApplyBatch() {
+	sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+	defer sm.Stop()
+	sm.Set("stale", 0)
+
+	err := sm.ApplyBatch(shrinkmap.BatchOperations[string, int]{
+		Operations: []shrinkmap.BatchOperation[string, int]{
+			{Type: shrinkmap.BatchSet, Key: "a", Value: 1},
+			{Type: shrinkmap.BatchSet, Key: "b", Value: 2},
+			{Type: shrinkmap.BatchDelete, Key: "stale"},
+		},
+	})
+	fmt.Println(err, sm.Len(), sm.Contains("stale"))
+	// Output: <nil> 2 false
+}
+
+func ExampleShrinkableMap_ApplyBatchMode() {
+	sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+	defer sm.Stop()
+
+	absent := func(_ int, exists bool) bool { return !exists }
+	batch := shrinkmap.BatchOperations[string, int]{
+		Operations: []shrinkmap.BatchOperation[string, int]{
+			{Type: shrinkmap.BatchSet, Key: "a", Value: 1, Condition: absent},
+			{Type: shrinkmap.BatchSet, Key: "a", Value: 2, Condition: absent},
+		},
+	}
+
+	// Atomic batches apply nothing if any operation fails
+	_, err := sm.ApplyBatchMode(batch, shrinkmap.BatchAtomic)
+	fmt.Println(errors.Is(err, shrinkmap.ErrConditionFailed), sm.Len())
+
+	// Best-effort batches apply what they can and report the rest
+	result, err := sm.ApplyBatchMode(batch, shrinkmap.BatchBestEffort)
+	value, _ := sm.Get("a")
+	fmt.Println(err, result.Applied, result.Failed[0].Index, value)
+	// Output:
+	// true 0
+	// <nil> 1 1 1
+}
+
+func ExampleLinkedShrinkableMap_fifoEviction() {
+	lm := shrinkmap.NewLinked[string, int](shrinkmap.DefaultConfig().WithFIFOEviction(2))
+	defer lm.Stop()
+
+	lm.Set("a", 1)
+	lm.Set("b", 2)
+	lm.Set("c", 3)
+
+	lm.Range(func(key string, value int) bool {
+		fmt.Println(key, value)
+		return true
+	})
+	metrics := lm.GetMetrics()
+	fmt.Println("evicted:", metrics.Evictions())
+	// Output:
+	// b 2
+	// c 3
+	// evicted: 1
+}
+
+func ExampleLinkedShrinkableMap_ageEviction() {
+	lm := shrinkmap.NewLinked[string, int](shrinkmap.DefaultConfig().WithAgeEviction(10 * time.Millisecond))
+	defer lm.Stop()
+
+	lm.Set("session", 1)
+	fmt.Println(lm.Contains("session"))
+
+	time.Sleep(20 * time.Millisecond)
+	// Expired entries are hidden from reads until they are evicted, either by
+	// the background sweep or by an explicit EvictExpired
+	fmt.Println(lm.Contains("session"))
+	lm.EvictExpired()
+	fmt.Println(lm.Len())
+	// Output:
+	// true
+	// false
+	// 0
+}
@@ -46,27 +46,16 @@ func gradingSystemExample() {
 	fmt.Println("\nFinal Grade Report:")
 	fmt.Println("==================")
 
-	var totalGrade float64
-	var highestGrade float64
-	var highestStudent string
-	count := 0
-
 	for iter.Next() {
 		student, grade := iter.Get()
 		fmt.Printf("%s: %.1f\n", student, grade)
-
-		totalGrade += grade
-		count++
-
-		if grade > highestGrade {
-			highestGrade = grade
-			highestStudent = student
-		}
 	}
+	iter.Release()
 
-	if count > 0 {
+	if average, ok := shrinkmap.Average(sm); ok {
+		highest, _ := shrinkmap.Max(sm)
 		fmt.Println("\nClass Statistics:")
-		fmt.Printf("Average Grade: %.1f\n", totalGrade/float64(count))
-		fmt.Printf("Highest Grade: %.1f (Student: %s)\n", highestGrade, highestStudent)
+		fmt.Printf("Average Grade: %.1f\n", average)
+		fmt.Printf("Highest Grade: %.1f (Student: %s)\n", highest.Value, highest.Key)
 	}
 }
@@ -0,0 +1,57 @@
+package shrinkmap
+
+// EntryInfo describes a stored entry without its value
+type EntryInfo struct {
+	// Sequence number assigned when the entry was last written under
+	// Config.TrackGenerations, unique within the map and increasing with
+	// every write. It survives shrinks, so an unchanged generation means the
+	// entry was not replaced even if the map was rebuilt. 0 if generations
+	// are not tracked.
+	Generation uint64
+}
+
+// Inspect returns information about the entry for key, or false if the key
+// is not present
+func (sm *ShrinkableMap[K, V]) Inspect(key K) (EntryInfo, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	if _, exists := sm.data[key]; !exists {
+		return EntryInfo{}, false
+	}
+	return EntryInfo{Generation: sm.generations[key]}, true
+}
+
+// nextGenerationLocked assigns a new generation to key under
+// Config.TrackGenerations. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) nextGenerationLocked(key K) {
+	if sm.generations != nil {
+		sm.generation++
+		sm.generations[key] = sm.generation
+	}
+}
+
+// renumberLocked assigns new generations to every entry of data, which
+// replaces the map's contents. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) renumberLocked(data map[K]V) {
+	if sm.generations == nil {
+		return
+	}
+	sm.generations = make(map[K]uint64, len(data))
+	for k := range data {
+		sm.nextGenerationLocked(k)
+	}
+}
+
+// compactGenerationsLocked reallocates the generation table for the entries
+// of a shrunk map, keeping their generations. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) compactGenerationsLocked(data map[K]V) {
+	if sm.generations == nil {
+		return
+	}
+	generations := make(map[K]uint64, len(data))
+	for k := range data {
+		generations[k] = sm.generations[k]
+	}
+	sm.generations = generations
+}
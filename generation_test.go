@@ -0,0 +1,77 @@
+package shrinkmap
+
+import (
+	"testing"
+)
+
+func TestGenerations(t *testing.T) {
+	t.Run("Survive Shrink", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithAutoShrinkEnabled(false).WithTrackGenerations(true))
+		defer sm.Stop()
+
+		for _, k := range []string{"a", "b", "c"} {
+			sm.Set(k, 1)
+		}
+		before, _ := sm.Inspect("a")
+		sm.Delete("b")
+		sm.ForceShrink()
+		after, ok := sm.Inspect("a")
+		if !ok || after.Generation == 0 || after.Generation != before.Generation {
+			t.Errorf("Expected generation %d to survive the shrink, got %d", before.Generation, after.Generation)
+		}
+
+		sm.Set("a", 2)
+		replaced, _ := sm.Inspect("a")
+		if replaced.Generation <= before.Generation {
+			t.Errorf("Expected a new generation after the write, got %d", replaced.Generation)
+		}
+		c, _ := sm.Inspect("c")
+		if replaced.Generation <= c.Generation {
+			t.Errorf("Expected generations to increase across keys, got %d and %d", c.Generation, replaced.Generation)
+		}
+		if _, ok := sm.Inspect("b"); ok {
+			t.Error("Expected deleted key to be missing")
+		}
+	})
+
+	t.Run("Change Events", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithTrackGenerations(true))
+		defer sm.Stop()
+
+		ch := make(chan ChangeEvent[string, int], 10)
+		cancel := sm.Watch(ch, nil)
+		defer cancel()
+
+		sm.Set("a", 1)
+		info, _ := sm.Inspect("a")
+		sm.Delete("a")
+
+		set, del := <-ch, <-ch
+		if set.Generation != info.Generation || del.Generation != info.Generation {
+			t.Errorf("Expected generation %d in both events, got %d and %d", info.Generation, set.Generation, del.Generation)
+		}
+	})
+
+	t.Run("Replaced Contents", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithTrackGenerations(true))
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		before, _ := sm.Inspect("a")
+		sm.replaceData(map[string]int{"a": 1}, 1)
+		after, _ := sm.Inspect("a")
+		if after.Generation <= before.Generation {
+			t.Errorf("Expected replaced contents to get new generations, got %d after %d", after.Generation, before.Generation)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		if info, ok := sm.Inspect("a"); !ok || info.Generation != 0 {
+			t.Errorf("Expected present entry without generation, got %+v, %v", info, ok)
+		}
+	})
+}
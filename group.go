@@ -0,0 +1,309 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrGroupStopped is returned when a map is added to a stopped Group
+var ErrGroupStopped = errors.New("shrinkmap: group is stopped")
+
+// groupMember is implemented by every *ShrinkableMap regardless of its key
+// and value types
+type groupMember interface {
+	Stop()
+	Len() int64
+	GetMetrics() Metrics
+	isStopped() bool
+	shrinkInterval() time.Duration
+	scheduledShrink() (panicked bool)
+	restartDelay(restarts int) (time.Duration, bool)
+}
+
+type groupEntry struct {
+	member   groupMember
+	next     time.Time
+	restarts int  // restarts after panics
+	ended    bool // checks ended by a panic
+}
+
+// Group owns a set of named maps and their lifecycle.
+// Instead of one goroutine per map, a single scheduler goroutine runs the
+// periodic shrink checks of all maps in the group, and one Stop call tears
+// down every map.
+type Group struct {
+	mu      sync.Mutex
+	entries map[string]*groupEntry
+	stopped bool
+
+	wake   chan struct{}
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// GroupMetrics aggregates the metrics of all maps in a group
+type GroupMetrics struct {
+	Maps         int
+	TotalItems   int64
+	TotalShrinks int64
+	TotalErrors  int64
+	TotalPanics  int64
+}
+
+// NewGroup creates an empty group and starts its scheduler
+func NewGroup() *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+	g := &Group{
+		entries: make(map[string]*groupEntry),
+		wake:    make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go g.run(ctx)
+	return g
+}
+
+// NewInGroup creates a map owned by g under the given name.
+// The map's periodic shrink checks run on the group's scheduler, and it is
+// stopped by g.Stop or g.Remove. Status reports the checks as ShrinkLoop, and
+// Config.RestartShrinkLoopOnPanic resumes them after a panic. Names must be
+// unique within the group and become the map's Config.Name unless one is
+// already set. Eviction settings and Config.ShrinkTrigger are rejected: only
+// NewLinked supports the former, and the group's scheduler replaces the latter.
+func NewInGroup[K comparable, V any](g *Group, name string, config Config) (*ShrinkableMap[K, V], error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return nil, ErrGroupStopped
+	}
+	if _, exists := g.entries[name]; exists {
+		return nil, fmt.Errorf("map %q already exists in group", name)
+	}
+	if err := config.evictionError(); err != nil {
+		return nil, err
+	}
+	if config.ShrinkTrigger != nil {
+		return nil, fmt.Errorf("shrink triggers are not supported by grouped maps")
+	}
+
+	if config.Name == "" {
+		config.Name = name
+	}
+	sm := newMap[K, V](config)
+	if config.AutoShrinkEnabled {
+		sm.shrinkStatus.start()
+		// The checks end with the map
+		context.AfterFunc(sm.ctx, func() { sm.shrinkStatus.running.Store(false) })
+	}
+	g.entries[name] = &groupEntry{member: sm, next: time.Now().Add(sm.shrinkInterval())}
+	g.notify()
+	return sm, nil
+}
+
+// Names returns the names of the maps in the group in sorted order
+func (g *Group) Names() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	names := make([]string, 0, len(g.entries))
+	for name := range g.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Len returns the number of maps in the group
+func (g *Group) Len() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.entries)
+}
+
+// Remove stops the named map and removes it from the group.
+// Returns false if the group has no map with that name.
+func (g *Group) Remove(name string) bool {
+	g.mu.Lock()
+	entry, exists := g.entries[name]
+	delete(g.entries, name)
+	g.mu.Unlock()
+
+	if exists {
+		entry.member.Stop()
+	}
+	return exists
+}
+
+// Metrics returns the metrics of all maps in the group combined
+func (g *Group) Metrics() GroupMetrics {
+	g.mu.Lock()
+	members := make([]groupMember, 0, len(g.entries))
+	for _, entry := range g.entries {
+		members = append(members, entry.member)
+	}
+	g.mu.Unlock()
+
+	result := GroupMetrics{Maps: len(members)}
+	for _, m := range members {
+		metrics := m.GetMetrics()
+		result.TotalItems += m.Len()
+		result.TotalShrinks += metrics.TotalShrinks()
+		result.TotalErrors += metrics.TotalErrors()
+		result.TotalPanics += metrics.TotalPanics()
+	}
+	return result
+}
+
+// Stop stops the scheduler and every map in the group.
+// Maps can no longer be added afterwards.
+func (g *Group) Stop() {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+	g.stopped = true
+	entries := g.entries
+	g.entries = make(map[string]*groupEntry)
+	g.mu.Unlock()
+
+	g.cancel()
+	<-g.done
+	for _, entry := range entries {
+		entry.member.Stop()
+	}
+}
+
+// notify wakes the scheduler to recompute its next deadline
+func (g *Group) notify() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run shrinks each map once its shrink interval has elapsed
+func (g *Group) run(ctx context.Context) {
+	defer close(g.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		due, next := g.collectDue(time.Now())
+		for _, entry := range due {
+			if entry.member.scheduledShrink() {
+				g.restart(entry)
+			}
+		}
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if next.IsZero() {
+			timer.Reset(time.Hour)
+		} else {
+			timer.Reset(time.Until(next))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-g.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// restart schedules the next check of a member whose check panicked after
+// the restart backoff, or ends its checks if it may not be restarted
+func (g *Group) restart(entry *groupEntry) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delay, ok := entry.member.restartDelay(entry.restarts)
+	if !ok {
+		entry.ended = true
+		return
+	}
+	entry.restarts++
+	entry.next = time.Now().Add(delay)
+}
+
+// collectDue returns the entries whose shrink check is due and the earliest
+// upcoming deadline. Stopped members are dropped from the group.
+func (g *Group) collectDue(now time.Time) ([]*groupEntry, time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var due []*groupEntry
+	var next time.Time
+	for name, entry := range g.entries {
+		if entry.member.isStopped() {
+			delete(g.entries, name)
+			continue
+		}
+		interval := entry.member.shrinkInterval()
+		if interval <= 0 || entry.ended {
+			continue
+		}
+		if !now.Before(entry.next) {
+			due = append(due, entry)
+			entry.next = now.Add(interval)
+		}
+		if next.IsZero() || entry.next.Before(next) {
+			next = entry.next
+		}
+	}
+	return due, next
+}
+
+func (sm *ShrinkableMap[K, V]) isStopped() bool {
+	return sm.stopped.Load()
+}
+
+// shrinkInterval returns how often the map is checked, or 0 if auto shrinking is disabled
+func (sm *ShrinkableMap[K, V]) shrinkInterval() time.Duration {
+	if !sm.config.AutoShrinkEnabled {
+		return 0
+	}
+	return sm.config.ShrinkInterval
+}
+
+// scheduledShrink runs a periodic shrink check, recording panics in the metrics
+func (sm *ShrinkableMap[K, V]) scheduledShrink() (panicked bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			sm.metrics.RecordPanic(r, string(debug.Stack()))
+			panicked = true
+		}
+	}()
+	sm.shrinkTick()
+	return false
+}
+
+// restartDelay returns how long the periodic shrink checks pause after a
+// panic that followed the given number of restarts, with the backoff of
+// shrinkLoop, or false if Config.RestartShrinkLoopOnPanic does not allow
+// another restart
+func (sm *ShrinkableMap[K, V]) restartDelay(restarts int) (time.Duration, bool) {
+	if !sm.config.RestartShrinkLoopOnPanic ||
+		(sm.config.ShrinkLoopMaxRestarts > 0 && restarts >= sm.config.ShrinkLoopMaxRestarts) {
+		sm.shrinkStatus.exit(true)
+		return 0, false
+	}
+	sm.shrinkStatus.restart()
+	delay := sm.config.ShrinkLoopRestartBackoff
+	for i := 0; i < restarts; i++ {
+		delay = min(delay*2, sm.config.ShrinkInterval)
+	}
+	return delay, true
+}
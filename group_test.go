@@ -0,0 +1,181 @@
+package shrinkmap
+
+import (
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	t.Run("Create And Stop", func(t *testing.T) {
+		g := NewGroup()
+		users, err := NewInGroup[string, int](g, "users", DefaultConfig())
+		if err != nil {
+			t.Fatalf("NewInGroup failed: %v", err)
+		}
+		sessions, err := NewInGroup[int, string](g, "sessions", DefaultConfig())
+		if err != nil {
+			t.Fatalf("NewInGroup failed: %v", err)
+		}
+		users.Set("a", 1)
+		sessions.Set(1, "x")
+
+		if _, err := NewInGroup[string, int](g, "users", DefaultConfig()); err == nil {
+			t.Error("Expected error for duplicate name")
+		}
+		if users.Name() != "users" {
+			t.Errorf("Expected group name to become the map name, got %q", users.Name())
+		}
+		if names := g.Names(); len(names) != 2 || names[0] != "sessions" || names[1] != "users" {
+			t.Errorf("Unexpected names: %v", names)
+		}
+		if m := g.Metrics(); m.Maps != 2 || m.TotalItems != 2 {
+			t.Errorf("Unexpected group metrics: %+v", m)
+		}
+
+		g.Stop()
+		if !users.Status().Stopped || !sessions.Status().Stopped {
+			t.Error("Expected all maps to be stopped with the group")
+		}
+		if _, err := NewInGroup[string, int](g, "late", DefaultConfig()); !errors.Is(err, ErrGroupStopped) {
+			t.Errorf("Expected ErrGroupStopped, got %v", err)
+		}
+		g.Stop()
+	})
+
+	t.Run("Shared Scheduler", func(t *testing.T) {
+		before := runtime.NumGoroutine()
+		g := NewGroup()
+		defer g.Stop()
+
+		config := DefaultConfig().
+			WithShrinkInterval(5 * time.Millisecond).
+			WithMinShrinkInterval(time.Millisecond)
+		maps := make([]*ShrinkableMap[int, int], 10)
+		for i := range maps {
+			sm, err := NewInGroup[int, int](g, string(rune('a'+i)), config)
+			if err != nil {
+				t.Fatalf("NewInGroup failed: %v", err)
+			}
+			maps[i] = sm
+		}
+		if extra := runtime.NumGoroutine() - before; extra > 1 {
+			t.Errorf("Expected a single scheduler goroutine, got %d extra", extra)
+		}
+
+		sm, _ := NewInGroup[int, int](g, "shrinking", config)
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		// Delete directly so the periodic check, not the delete path, shrinks the map
+		sm.mu.Lock()
+		for i := 0; i < 50; i++ {
+			sm.deleteLocked(i)
+		}
+		sm.mu.Unlock()
+
+		deadline := time.Now().Add(time.Second)
+		for {
+			metrics := sm.GetMetrics()
+			if metrics.TotalShrinks() > 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("Expected the group scheduler to shrink the map")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		g := NewGroup()
+		defer g.Stop()
+
+		sm, _ := NewInGroup[string, int](g, "a", DefaultConfig())
+		if !g.Remove("a") || g.Remove("a") {
+			t.Error("Expected Remove to succeed exactly once")
+		}
+		if !sm.Status().Stopped {
+			t.Error("Expected removed map to be stopped")
+		}
+		if g.Len() != 0 {
+			t.Errorf("Expected empty group, got %d maps", g.Len())
+		}
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		g := NewGroup()
+		defer g.Stop()
+
+		sm, _ := NewInGroup[int, int](g, "a", DefaultConfig().
+			WithShrinkInterval(5*time.Millisecond).WithMinShrinkInterval(0))
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.LastActivity.IsZero() })
+		if status := sm.Status(); !status.ShrinkLoop.Enabled || !status.ShrinkLoop.Running {
+			t.Errorf("Expected the group's checks to be reported as running, got %+v", status.ShrinkLoop)
+		}
+
+		g.Remove("a")
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+	})
+
+	t.Run("Restart After Panic", func(t *testing.T) {
+		g := NewGroup()
+		defer g.Stop()
+
+		var calls atomic.Int64
+		sm, _ := NewInGroup[int, int](g, "a", DefaultConfig().
+			WithShrinkInterval(5*time.Millisecond).WithMinShrinkInterval(0).
+			WithShrinkLoopRestart(0, time.Millisecond).
+			WithTargetCapacity(func(live, peak int64, config Config) int {
+				if calls.Add(1) == 1 {
+					panic("boom")
+				}
+				return GrowthFactorCapacity(live, peak, config)
+			}))
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.LastActivity.IsZero() })
+		if status := sm.Status(); !status.ShrinkLoop.Running || status.ShrinkLoop.Restarts != 1 {
+			t.Errorf("Expected the checks to be restarted once and running, got %+v", status.ShrinkLoop)
+		}
+	})
+
+	t.Run("No Restart After Panic", func(t *testing.T) {
+		g := NewGroup()
+		defer g.Stop()
+
+		var calls atomic.Int64
+		sm, _ := NewInGroup[int, int](g, "a", DefaultConfig().
+			WithShrinkInterval(5*time.Millisecond).WithMinShrinkInterval(0).
+			WithTargetCapacity(func(live, peak int64, config Config) int {
+				calls.Add(1)
+				panic("boom")
+			}))
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+		time.Sleep(20 * time.Millisecond)
+		if status := sm.Status(); !status.ShrinkLoop.Panicked || calls.Load() != 1 {
+			t.Errorf("Expected the checks to end after one panic, got %+v after %d calls", status.ShrinkLoop, calls.Load())
+		}
+	})
+
+	t.Run("Shrink Trigger Rejected", func(t *testing.T) {
+		g := NewGroup()
+		defer g.Stop()
+
+		if _, err := NewInGroup[int, int](g, "a", DefaultConfig().WithShrinkTrigger(make(chan struct{}))); err == nil {
+			t.Error("Expected NewInGroup to reject a shrink trigger")
+		}
+	})
+}
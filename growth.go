@@ -0,0 +1,73 @@
+package shrinkmap
+
+import (
+	"sync"
+	"time"
+)
+
+// growthTracker estimates the net number of keys added (inserts minus
+// deletes) over the last Config.GrowthHysteresis. Counts are kept for the
+// current and the previous window; the previous window is weighted by the
+// part of it still inside the sliding window.
+type growthTracker struct {
+	mu     sync.Mutex
+	window time.Duration
+	start  time.Time
+	cur    int64
+	prev   int64
+}
+
+func newGrowthTracker(window time.Duration) *growthTracker {
+	if window <= 0 {
+		return nil
+	}
+	return &growthTracker{window: window, start: time.Now()}
+}
+
+// record adds delta keys at now. Callers check for a nil tracker first, so
+// the clock is not read on the write path when hysteresis is disabled.
+func (g *growthTracker) record(now time.Time, delta int64) {
+	g.mu.Lock()
+	g.advance(now)
+	g.cur += delta
+	g.mu.Unlock()
+}
+
+// reset forgets all recorded growth, starting a new window at now
+func (g *growthTracker) reset(now time.Time) {
+	g.mu.Lock()
+	g.prev, g.cur = 0, 0
+	g.start = now
+	g.mu.Unlock()
+}
+
+// growth returns the estimated net growth over the window ending at now
+func (g *growthTracker) growth(now time.Time) int64 {
+	if g == nil {
+		return 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.advance(now)
+	remaining := 1 - float64(now.Sub(g.start))/float64(g.window)
+	return g.cur + int64(float64(g.prev)*remaining)
+}
+
+// advance moves the windows forward to now. Must be called with g.mu held.
+func (g *growthTracker) advance(now time.Time) {
+	elapsed := now.Sub(g.start)
+	switch {
+	case elapsed >= 2*g.window:
+		g.prev, g.cur = 0, 0
+		g.start = now
+	case elapsed >= g.window:
+		g.prev, g.cur = g.cur, 0
+		g.start = g.start.Add(g.window)
+	}
+}
+
+// regrowing reports whether live entries growing by growth would outgrow the
+// map a shrink allocates, so shrinking now would soon be undone
+func (sm *ShrinkableMap[K, V]) regrowing(live, growth int64) bool {
+	return growth > 0 && live+growth > int64(sm.shrinkTargetSize(live))
+}
@@ -0,0 +1,82 @@
+package shrinkmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGrowthHysteresis(t *testing.T) {
+	t.Run("Tracker", func(t *testing.T) {
+		g := newGrowthTracker(time.Minute)
+		start := g.start
+		g.record(start, 10)
+		g.record(start.Add(30*time.Second), -4)
+		if got := g.growth(start.Add(30 * time.Second)); got != 6 {
+			t.Errorf("Expected growth 6 within the window, got %d", got)
+		}
+		// Half of the previous window is still inside the sliding window
+		if got := g.growth(start.Add(90 * time.Second)); got != 3 {
+			t.Errorf("Expected growth 3 half a window later, got %d", got)
+		}
+		if got := g.growth(start.Add(5 * time.Minute)); got != 0 {
+			t.Errorf("Expected no growth after two windows, got %d", got)
+		}
+		if newGrowthTracker(0) != nil {
+			t.Error("Expected no tracker without a window")
+		}
+	})
+
+	t.Run("Skips Shrink While Regrowing", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().
+			WithAutoShrinkEnabled(false).
+			WithMinShrinkInterval(time.Nanosecond).
+			WithGrowthHysteresis(50 * time.Millisecond))
+		defer sm.Stop()
+
+		for i := 0; i < 1000; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 900; i++ {
+			sm.Delete(i)
+		}
+		for i := 0; i < 200; i++ {
+			sm.Set(i, i)
+		}
+
+		if sm.TryShrink() {
+			t.Fatal("Expected the shrink to be skipped while the map grows")
+		}
+		d := sm.ShrinkDecision()
+		if d.Reason != DecisionGrowing || d.Growth != 300 {
+			t.Errorf("Expected DecisionGrowing with growth 300, got %v with %d", d.Reason, d.Growth)
+		}
+		metrics := sm.GetMetrics()
+		if metrics.ShrinksSkippedForGrowth() != 1 {
+			t.Errorf("Expected 1 skipped shrink, got %d", metrics.ShrinksSkippedForGrowth())
+		}
+
+		time.Sleep(120 * time.Millisecond)
+		if !sm.TryShrink() {
+			t.Errorf("Expected the shrink once growth settled, got %v", sm.ShrinkDecision().Reason)
+		}
+	})
+
+	t.Run("Force Shrink Ignores Growth", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithAutoShrinkEnabled(false).WithGrowthHysteresis(time.Hour))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		sm.Delete(0)
+		if !sm.ForceShrink() {
+			t.Error("Expected ForceShrink to ignore growth")
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := DefaultConfig().WithGrowthHysteresis(-time.Second).Validate(); err == nil {
+			t.Error("Expected error for negative hysteresis")
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"time"
+)
+
+// HealthConfig defines the thresholds evaluated by Healthy.
+// A zero value disables the corresponding rule.
+type HealthConfig struct {
+	// Unhealthy if a shrink panic occurred within this window
+	PanicWindow time.Duration
+
+	// Unhealthy if at least ErrorThreshold errors were recorded within ErrorWindow
+	ErrorWindow    time.Duration
+	ErrorThreshold int
+
+	// Unhealthy if Len reaches this fraction of MaxMapSize (0.0 to 1.0)
+	MaxSizeRatio float64
+}
+
+// DefaultHealthConfig returns the default health thresholds
+func DefaultHealthConfig() HealthConfig {
+	return HealthConfig{
+		// Report shrink panics for 10 minutes
+		PanicWindow: 10 * time.Minute,
+
+		// Tolerate fewer than 5 errors in 5 minutes
+		ErrorWindow:    5 * time.Minute,
+		ErrorThreshold: 5,
+
+		// Warn before the map reaches its maximum size
+		MaxSizeRatio: 0.9,
+	}
+}
+
+// Validate checks if the health configuration is valid
+func (c HealthConfig) Validate() error {
+	if c.PanicWindow < 0 || c.ErrorWindow < 0 {
+		return fmt.Errorf("health windows must be non-negative")
+	}
+	if c.ErrorThreshold < 0 {
+		return fmt.Errorf("health error threshold must be non-negative")
+	}
+	if c.MaxSizeRatio < 0 || c.MaxSizeRatio > 1 {
+		return fmt.Errorf("health max size ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// HealthCheck is the outcome of a single health rule
+type HealthCheck struct {
+	Name    string
+	Healthy bool
+	Message string
+}
+
+// HealthReport lists the outcome of every evaluated health rule
+type HealthReport struct {
+	CheckedAt time.Time
+	Checks    []HealthCheck
+}
+
+// Healthy evaluates the rules in Config.Health against the map's metrics and
+// reports whether all of them pass. A stopped map is always unhealthy.
+func (sm *ShrinkableMap[K, V]) Healthy() (bool, HealthReport) {
+	config := sm.config.Health
+	now := time.Now()
+	metrics := sm.GetMetrics()
+	report := HealthReport{CheckedAt: now}
+	healthy := true
+
+	add := func(name string, ok bool, format string, args ...interface{}) {
+		report.Checks = append(report.Checks, HealthCheck{
+			Name:    name,
+			Healthy: ok,
+			Message: fmt.Sprintf(format, args...),
+		})
+		healthy = healthy && ok
+	}
+
+	if sm.stopped.Load() {
+		add("running", false, "map is stopped")
+	} else {
+		add("running", true, "map is running")
+	}
+
+	if config.PanicWindow > 0 {
+		last := metrics.LastPanicTime()
+		if !last.IsZero() && now.Sub(last) < config.PanicWindow {
+			add("shrink_panics", false, "shrink panic at %s", last.Format(time.RFC3339))
+		} else {
+			add("shrink_panics", true, "no shrink panic in the last %s", config.PanicWindow)
+		}
+	}
+
+	if config.ErrorWindow > 0 && config.ErrorThreshold > 0 {
+		// Errors are counted up to the threshold
+		recent := sm.metrics.errorsSince(now.Add(-config.ErrorWindow))
+		if recent < config.ErrorThreshold {
+			add("errors", true, "%d errors in the last %s (threshold %d)",
+				recent, config.ErrorWindow, config.ErrorThreshold)
+		} else {
+			add("errors", false, "at least %d errors in the last %s (threshold %d)",
+				recent, config.ErrorWindow, config.ErrorThreshold)
+		}
+	}
+
+	if config.MaxSizeRatio > 0 && sm.config.MaxMapSize > 0 {
+		ratio := float64(sm.Len()) / float64(sm.config.MaxMapSize)
+		add("size", ratio < config.MaxSizeRatio,
+			"%.1f%% of maximum size %d (threshold %.1f%%)", ratio*100, sm.config.MaxMapSize, config.MaxSizeRatio*100)
+	}
+
+	return healthy, report
+}
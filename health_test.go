@@ -0,0 +1,105 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHealthy(t *testing.T) {
+	checkFailed := func(report HealthReport, name string) bool {
+		for _, c := range report.Checks {
+			if c.Name == name {
+				return !c.Healthy
+			}
+		}
+		return false
+	}
+
+	t.Run("Healthy Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		healthy, report := sm.Healthy()
+		if !healthy {
+			t.Errorf("Expected healthy map, got %+v", report.Checks)
+		}
+		if len(report.Checks) != 4 {
+			t.Errorf("Expected 4 checks, got %d", len(report.Checks))
+		}
+	})
+
+	t.Run("Recent Panic", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.metrics.RecordPanic("boom", "")
+
+		if healthy, report := sm.Healthy(); healthy || !checkFailed(report, "shrink_panics") {
+			t.Errorf("Expected shrink_panics to fail, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Error Threshold", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 5; i++ {
+			sm.metrics.RecordError(errors.New("failure"), "")
+		}
+
+		if healthy, report := sm.Healthy(); healthy || !checkFailed(report, "errors") {
+			t.Errorf("Expected errors check to fail, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Error Threshold After Reset", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 5; i++ {
+			sm.metrics.RecordError(errors.New("failure"), "")
+		}
+		sm.metrics.Reset()
+
+		if healthy, report := sm.Healthy(); !healthy {
+			t.Errorf("Expected errors cleared by Reset to be forgotten, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Error Threshold Above History", func(t *testing.T) {
+		health := DefaultHealthConfig()
+		health.ErrorThreshold = 20
+		sm := New[string, int](DefaultConfig().WithHealth(health))
+		defer sm.Stop()
+		for i := 0; i < 19; i++ {
+			sm.metrics.RecordError(errors.New("failure"), "")
+		}
+		if healthy, report := sm.Healthy(); !healthy {
+			t.Errorf("Expected healthy map below the threshold, got %+v", report.Checks)
+		}
+
+		sm.metrics.RecordPanic("boom", "")
+		if _, report := sm.Healthy(); !checkFailed(report, "errors") {
+			t.Errorf("Expected errors check to fail, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Size Ratio", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithMaxMapSize(10).WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+		for i := 0; i < 9; i++ {
+			sm.Set(i, i)
+		}
+
+		if healthy, report := sm.Healthy(); healthy || !checkFailed(report, "size") {
+			t.Errorf("Expected size check to fail, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Stopped Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithHealth(HealthConfig{}))
+		sm.Stop()
+
+		healthy, report := sm.Healthy()
+		if healthy || !checkFailed(report, "running") || len(report.Checks) != 1 {
+			t.Errorf("Expected only the running check to fail, got %+v", report.Checks)
+		}
+	})
+}
@@ -0,0 +1,162 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// ErrHistoryUnavailable is returned by GetAt and SnapshotAt when history is
+// disabled or the requested time is no longer retained
+var ErrHistoryUnavailable = errors.New("shrinkmap: history unavailable for requested time")
+
+// version is one value of a key, valid from timestamp until the next version
+type version[V any] struct {
+	timestamp time.Time
+	value     V
+	deleted   bool
+}
+
+type keyHistory[V any] struct {
+	versions []version[V]
+	// Versions before this time were dropped by HistoryMaxVersions
+	truncatedBefore time.Time
+}
+
+// history retains recent versions of every key. Must be used with sm.mu held.
+type history[K comparable, V any] struct {
+	retention   time.Duration
+	maxVersions int
+	keys        map[K]*keyHistory[V]
+	lastSweep   time.Time
+	now         func() time.Time
+}
+
+func newHistory[K comparable, V any](config Config) *history[K, V] {
+	if config.HistoryRetention <= 0 {
+		return nil
+	}
+	return &history[K, V]{
+		retention:   config.HistoryRetention,
+		maxVersions: config.HistoryMaxVersions,
+		keys:        make(map[K]*keyHistory[V]),
+		now:         time.Now,
+	}
+}
+
+// horizon returns the oldest time that can still be queried
+func (h *history[K, V]) horizon(now time.Time) time.Time {
+	return now.Add(-h.retention)
+}
+
+func (h *history[K, V]) record(typ ChangeType, key K, value V) {
+	now := h.now()
+	kh := h.keys[key]
+	if kh == nil {
+		if typ == ChangeDelete {
+			return
+		}
+		kh = &keyHistory[V]{}
+		h.keys[key] = kh
+	}
+	kh.versions = append(kh.versions, version[V]{timestamp: now, value: value, deleted: typ == ChangeDelete})
+	if h.maxVersions > 0 && len(kh.versions) > h.maxVersions {
+		drop := len(kh.versions) - h.maxVersions
+		kh.truncatedBefore = kh.versions[drop].timestamp
+		kh.versions = append(kh.versions[:0], kh.versions[drop:]...)
+	}
+	h.prune(key, kh, h.horizon(now))
+
+	if h.lastSweep.IsZero() {
+		h.lastSweep = now
+	} else if now.Sub(h.lastSweep) >= h.retention {
+		h.sweep(now)
+	}
+}
+
+// prune drops versions superseded before the horizon and forgets keys whose
+// last version is a deletion before the horizon
+func (h *history[K, V]) prune(key K, kh *keyHistory[V], horizon time.Time) {
+	drop := 0
+	for drop+1 < len(kh.versions) && !kh.versions[drop+1].timestamp.After(horizon) {
+		drop++
+	}
+	if drop > 0 {
+		kh.versions = append(kh.versions[:0], kh.versions[drop:]...)
+	}
+	if len(kh.versions) == 1 && kh.versions[0].deleted && !kh.versions[0].timestamp.After(horizon) {
+		delete(h.keys, key)
+	}
+}
+
+// sweep prunes every key, so history of keys that are no longer written does not grow unbounded
+func (h *history[K, V]) sweep(now time.Time) {
+	horizon := h.horizon(now)
+	for key, kh := range h.keys {
+		h.prune(key, kh, horizon)
+	}
+	h.lastSweep = now
+}
+
+// at returns the value of kh at time t
+func (kh *keyHistory[V]) at(t time.Time) (V, bool, error) {
+	var zero V
+	if t.Before(kh.truncatedBefore) {
+		return zero, false, ErrHistoryUnavailable
+	}
+	i := sort.Search(len(kh.versions), func(i int) bool {
+		return kh.versions[i].timestamp.After(t)
+	})
+	if i == 0 || kh.versions[i-1].deleted {
+		return zero, false, nil
+	}
+	return kh.versions[i-1].value, true, nil
+}
+
+// GetAt returns the value key had at time t, within the retention configured
+// by Config.HistoryRetention. Returns ErrHistoryUnavailable if history is
+// disabled or t is older than the retained history of the key.
+func (sm *ShrinkableMap[K, V]) GetAt(key K, t time.Time) (V, bool, error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var zero V
+	h := sm.history
+	if h == nil || t.Before(h.horizon(h.now())) {
+		return zero, false, ErrHistoryUnavailable
+	}
+	kh := h.keys[key]
+	if kh == nil {
+		return zero, false, nil
+	}
+	value, exists, err := kh.at(t)
+	if exists {
+		value = sm.copyOnRead(value)
+	}
+	return value, exists, err
+}
+
+// SnapshotAt returns the contents of the map at time t, within the retention
+// configured by Config.HistoryRetention. Returns ErrHistoryUnavailable if
+// history is disabled or t is older than the retained history of any key.
+func (sm *ShrinkableMap[K, V]) SnapshotAt(t time.Time) ([]KeyValue[K, V], error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	h := sm.history
+	if h == nil || t.Before(h.horizon(h.now())) {
+		return nil, ErrHistoryUnavailable
+	}
+	var result []KeyValue[K, V]
+	for key, kh := range h.keys {
+		value, exists, err := kh.at(t)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result = append(result, KeyValue[K, V]{Key: key, Value: value})
+		}
+	}
+	sm.copyEntriesOnRead(result)
+	return result, nil
+}
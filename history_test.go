@@ -0,0 +1,117 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHistory(t *testing.T) {
+	newHistoryMap := func(retention time.Duration, maxVersions int) (*ShrinkableMap[string, int], *time.Time) {
+		sm := New[string, int](DefaultConfig().WithHistory(retention, maxVersions))
+		now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		sm.history.now = func() time.Time { return now }
+		return sm, &now
+	}
+
+	t.Run("Get At", func(t *testing.T) {
+		sm, now := newHistoryMap(time.Minute, 0)
+		defer sm.Stop()
+		start := *now
+
+		sm.Set("a", 1)
+		*now = now.Add(10 * time.Second)
+		sm.Set("a", 2)
+		*now = now.Add(10 * time.Second)
+		sm.Delete("a")
+
+		cases := []struct {
+			at     time.Time
+			value  int
+			exists bool
+		}{
+			{start.Add(-time.Second), 0, false},
+			{start, 1, true},
+			{start.Add(5 * time.Second), 1, true},
+			{start.Add(10 * time.Second), 2, true},
+			{start.Add(20 * time.Second), 0, false},
+		}
+		for _, c := range cases {
+			value, exists, err := sm.GetAt("a", c.at)
+			if err != nil || value != c.value || exists != c.exists {
+				t.Errorf("GetAt(%s) = %d, %v, %v; want %d, %v", c.at.Sub(start), value, exists, err, c.value, c.exists)
+			}
+		}
+	})
+
+	t.Run("Snapshot At", func(t *testing.T) {
+		sm, now := newHistoryMap(time.Minute, 0)
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		sm.Set("b", 1)
+		before := *now
+		*now = now.Add(time.Second)
+		sm.Set("a", 2)
+		sm.Delete("b")
+
+		snapshot, err := sm.SnapshotAt(before)
+		if err != nil {
+			t.Fatalf("SnapshotAt failed: %v", err)
+		}
+		if len(snapshot) != 2 {
+			t.Errorf("Expected 2 entries at the earlier time, got %v", snapshot)
+		}
+		snapshot, _ = sm.SnapshotAt(*now)
+		if len(snapshot) != 1 || snapshot[0].Value != 2 {
+			t.Errorf("Expected only a=2 now, got %v", snapshot)
+		}
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		sm, now := newHistoryMap(time.Minute, 0)
+		defer sm.Stop()
+		start := *now
+
+		sm.Set("a", 1)
+		sm.Set("gone", 1)
+		sm.Delete("gone")
+		*now = now.Add(2 * time.Minute)
+		sm.Set("a", 2)
+
+		if _, _, err := sm.GetAt("a", start); !errors.Is(err, ErrHistoryUnavailable) {
+			t.Errorf("Expected ErrHistoryUnavailable beyond retention, got %v", err)
+		}
+		if v, _, _ := sm.GetAt("a", now.Add(-30*time.Second)); v != 1 {
+			t.Errorf("Expected value before the latest write to be retained, got %d", v)
+		}
+		if _, exists := sm.history.keys["gone"]; exists {
+			t.Error("Expected history of deleted key to be swept after retention")
+		}
+	})
+
+	t.Run("Max Versions", func(t *testing.T) {
+		sm, now := newHistoryMap(time.Hour, 2)
+		defer sm.Stop()
+		start := *now
+
+		for i := 1; i <= 3; i++ {
+			sm.Set("a", i)
+			*now = now.Add(time.Second)
+		}
+		if _, _, err := sm.GetAt("a", start); !errors.Is(err, ErrHistoryUnavailable) {
+			t.Errorf("Expected ErrHistoryUnavailable for dropped version, got %v", err)
+		}
+		if v, _, err := sm.GetAt("a", start.Add(time.Second)); err != nil || v != 2 {
+			t.Errorf("Expected retained version 2, got %d, %v", v, err)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		if _, _, err := sm.GetAt("a", time.Now()); !errors.Is(err, ErrHistoryUnavailable) {
+			t.Errorf("Expected ErrHistoryUnavailable without history, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,187 @@
+package shrinkmap
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// ErrValueMutated is wrapped in the *KeyError recorded under
+// Config.VerifyImmutable when a stored value changed in place
+var ErrValueMutated = errors.New("shrinkmap: stored value mutated in place")
+
+// hashWriteLocked records the hash of a value being stored under
+// Config.VerifyImmutable. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) hashWriteLocked(key K, value V) {
+	if sm.hashes != nil {
+		sm.hashes[key] = hashValue(value)
+	}
+}
+
+// verifyLocked compares value with the hash recorded when it was stored and
+// records a mutation if they differ. The hash is updated, so a mutation is
+// reported once. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) verifyLocked(key K, value V) bool {
+	if sm.hashes == nil {
+		return true
+	}
+	want, ok := sm.hashes[key]
+	if !ok {
+		return true
+	}
+	if got := hashValue(value); got != want {
+		sm.hashes[key] = got
+		sm.metrics.RecordError(&KeyError{Op: "verify", Key: sm.errorKey(key), Err: ErrValueMutated}, "")
+		return false
+	}
+	return true
+}
+
+// rehashLocked rebuilds the recorded hashes for data, verifying entries that
+// were already stored. The hash table is reallocated, so it shrinks along
+// with the map. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) rehashLocked(data map[K]V, verify bool) {
+	if sm.hashes == nil {
+		return
+	}
+	if verify {
+		for k, v := range data {
+			sm.verifyLocked(k, v)
+		}
+	}
+	hashes := make(map[K]uint64, len(data))
+	for k, v := range data {
+		if h, ok := sm.hashes[k]; ok && verify {
+			hashes[k] = h
+		} else {
+			hashes[k] = hashValue(v)
+		}
+	}
+	sm.hashes = hashes
+}
+
+// VerifyValues checks every entry against the hash recorded when it was
+// stored under Config.VerifyImmutable and returns the keys of values that
+// were mutated in place. Each mutation is also recorded as an error wrapping
+// ErrValueMutated. Returns nil if VerifyImmutable is not set.
+func (sm *ShrinkableMap[K, V]) VerifyValues() []K {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	var mutated []K
+	for k, v := range sm.data {
+		if !sm.verifyLocked(k, v) {
+			mutated = append(mutated, k)
+		}
+	}
+	return mutated
+}
+
+// hashValue hashes the contents of v, following pointers, slices and maps,
+// so changes made through references into the value change the hash. Map
+// entries are combined independently of iteration order. Channels and
+// functions are hashed by identity.
+func hashValue(v any) uint64 {
+	h := valueHasher{Hash64: fnv.New64a(), seen: make(map[uintptr]bool)}
+	h.value(reflect.ValueOf(v))
+	return h.Sum64()
+}
+
+type valueHasher struct {
+	hash.Hash64
+	seen map[uintptr]bool // pointers being followed, guarding against cycles
+	buf  [8]byte
+}
+
+func (h *valueHasher) uint(n uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], n)
+	h.Write(h.buf[:])
+}
+
+func (h *valueHasher) value(v reflect.Value) {
+	h.uint(uint64(v.Kind()))
+	switch v.Kind() {
+	case reflect.Invalid:
+	case reflect.Bool:
+		if v.Bool() {
+			h.uint(1)
+		} else {
+			h.uint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.uint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.uint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.uint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		h.uint(math.Float64bits(real(c)))
+		h.uint(math.Float64bits(imag(c)))
+	case reflect.String:
+		h.uint(uint64(v.Len()))
+		h.Write([]byte(v.String()))
+	case reflect.Pointer:
+		if v.IsNil() {
+			h.uint(0)
+			return
+		}
+		// Only pointers on the current path are tracked, so shared pointers
+		// hash the same wherever they are reached from
+		p := v.Pointer()
+		if h.seen[p] {
+			h.uint(1)
+			return
+		}
+		h.seen[p] = true
+		h.value(v.Elem())
+		delete(h.seen, p)
+	case reflect.Interface:
+		if v.IsNil() {
+			h.uint(0)
+			return
+		}
+		h.Write([]byte(v.Elem().Type().String()))
+		h.value(v.Elem())
+	case reflect.Slice:
+		if v.IsNil() {
+			h.uint(0)
+			return
+		}
+		fallthrough
+	case reflect.Array:
+		h.uint(uint64(v.Len()))
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			h.Write(v.Bytes())
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			h.value(v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			h.value(v.Field(i))
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			h.uint(0)
+			return
+		}
+		h.uint(uint64(v.Len()))
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := valueHasher{Hash64: fnv.New64a(), seen: h.seen}
+			entry.value(iter.Key())
+			entry.value(iter.Value())
+			sum += entry.Sum64()
+		}
+		h.uint(sum)
+	default:
+		// Channels, functions and unsafe pointers are compared by identity
+		h.uint(uint64(v.Pointer()))
+	}
+}
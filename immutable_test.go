@@ -0,0 +1,126 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyImmutable(t *testing.T) {
+	type profile struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]int
+	}
+	newProfile := func() *profile {
+		return &profile{Name: "a", Tags: []string{"x"}, Attrs: map[string]int{"age": 1}}
+	}
+	mutations := func(sm *ShrinkableMap[string, *profile]) int64 {
+		metrics := sm.GetMetrics()
+		return metrics.ErrorCount(ErrCodeValueMutated)
+	}
+
+	t.Run("Detects Mutation On Delete", func(t *testing.T) {
+		sm := New[string, *profile](DefaultConfig().WithAutoShrinkEnabled(false).WithVerifyImmutable(true))
+		defer sm.Stop()
+
+		p := newProfile()
+		sm.Set("a", p)
+		sm.Set("b", newProfile())
+		p.Tags[0] = "y"
+
+		sm.Delete("b")
+		if n := mutations(sm); n != 0 {
+			t.Errorf("Expected no mutation for an untouched value, got %d", n)
+		}
+		sm.Delete("a")
+		if n := mutations(sm); n != 1 {
+			t.Fatalf("Expected 1 mutation, got %d", n)
+		}
+		metrics := sm.GetMetrics()
+		var keyErr *KeyError
+		if err := metrics.LastError().Error.(error); !errors.Is(err, ErrValueMutated) || !errors.As(err, &keyErr) || keyErr.Key != "a" {
+			t.Errorf("Expected KeyError for key a wrapping ErrValueMutated, got %v", err)
+		}
+	})
+
+	t.Run("Detects Mutation On Overwrite And Shrink", func(t *testing.T) {
+		sm := New[string, *profile](DefaultConfig().WithAutoShrinkEnabled(false).WithVerifyImmutable(true))
+		defer sm.Stop()
+
+		p, q := newProfile(), newProfile()
+		sm.Set("a", p)
+		sm.Set("b", q)
+		p.Attrs["age"] = 2
+		sm.Set("a", newProfile())
+		if n := mutations(sm); n != 1 {
+			t.Errorf("Expected mutation reported on overwrite, got %d", n)
+		}
+
+		q.Name = "changed"
+		sm.ForceShrink()
+		if n := mutations(sm); n != 2 {
+			t.Errorf("Expected mutation reported on shrink, got %d", n)
+		}
+		// Reported once, not again on delete
+		sm.Delete("b")
+		if n := mutations(sm); n != 2 {
+			t.Errorf("Expected the mutation to be reported once, got %d", n)
+		}
+	})
+
+	t.Run("VerifyValues", func(t *testing.T) {
+		sm := New[string, *profile](DefaultConfig().WithVerifyImmutable(true))
+		defer sm.Stop()
+
+		p := newProfile()
+		sm.Set("a", p)
+		sm.Set("b", newProfile())
+		if mutated := sm.VerifyValues(); len(mutated) != 0 {
+			t.Errorf("Expected no mutations, got %v", mutated)
+		}
+		p.Tags = append(p.Tags, "z")
+		if mutated := sm.VerifyValues(); len(mutated) != 1 || mutated[0] != "a" {
+			t.Errorf("Expected key a to be reported, got %v", mutated)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		sm := New[string, *profile](DefaultConfig())
+		defer sm.Stop()
+
+		p := newProfile()
+		sm.Set("a", p)
+		p.Name = "changed"
+		sm.Delete("a")
+		if mutated := sm.VerifyValues(); mutated != nil || mutations(sm) != 0 {
+			t.Error("Expected no verification without VerifyImmutable")
+		}
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		type node struct {
+			Next *node
+			N    int
+		}
+		cycle := &node{N: 1}
+		cycle.Next = cycle
+		if hashValue(cycle) != hashValue(cycle) {
+			t.Error("Expected cyclic values to hash deterministically")
+		}
+
+		m := map[string][]int{"a": {1}, "b": {2}, "c": {3}}
+		h := hashValue(m)
+		for i := 0; i < 10; i++ {
+			if hashValue(m) != h {
+				t.Fatal("Expected map hashes to ignore iteration order")
+			}
+		}
+		m["a"][0] = 5
+		if hashValue(m) == h {
+			t.Error("Expected a nested change to change the hash")
+		}
+		if hashValue([]byte("ab")) == hashValue([]byte("ba")) || hashValue(1) == hashValue(uint(1)) {
+			t.Error("Expected distinct hashes for distinct values")
+		}
+	})
+}
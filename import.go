@@ -0,0 +1,134 @@
+package shrinkmap
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+const (
+	// defaultImportChunkSize is the number of entries applied per lock acquisition
+	defaultImportChunkSize = 1000
+
+	// defaultImportMaxLineSize bounds a single JSON Lines record
+	defaultImportMaxLineSize = 1 << 20
+)
+
+// ImportOptions controls Import
+type ImportOptions struct {
+	// Number of entries applied per lock acquisition (default 1000)
+	ChunkSize int
+
+	// Maximum length of a single line in bytes (default 1 MiB)
+	MaxLineSize int
+
+	// Skip lines that fail to decode instead of aborting the import
+	SkipInvalid bool
+
+	// Called after every applied chunk with the running totals
+	Progress func(ImportReport)
+}
+
+// ImportReport summarizes an import
+type ImportReport struct {
+	Lines    int // non-empty lines read
+	Imported int // entries stored in the map
+	Skipped  int // lines skipped because they failed to decode
+	Chunks   int // chunks applied
+}
+
+// Import streams JSON Lines from r into the map, one entry per line decoded
+// with codec (typically JSONCodec). Blank lines are ignored. Entries are applied
+// in chunks of opts.ChunkSize, so readers are not blocked for the whole import
+// and the map is shrunk between chunks once it reaches MaxMapSize.
+// Entries from chunks applied before an error remain in the map.
+func (sm *ShrinkableMap[K, V]) Import(r io.Reader, codec Codec[K, V], opts ImportOptions) (ImportReport, error) {
+	var report ImportReport
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = defaultImportChunkSize
+	}
+	if opts.MaxLineSize <= 0 {
+		opts.MaxLineSize = defaultImportMaxLineSize
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, min(opts.MaxLineSize, 64*1024)), opts.MaxLineSize)
+
+	chunk := make([]KeyValue[K, V], 0, opts.ChunkSize)
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		if err := sm.importChunk(chunk); err != nil {
+			return err
+		}
+		report.Imported += len(chunk)
+		report.Chunks++
+		chunk = chunk[:0]
+		if opts.Progress != nil {
+			opts.Progress(report)
+		}
+		return nil
+	}
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		report.Lines++
+
+		key, value, err := codec.DecodeEntry(line)
+		if err != nil {
+			if opts.SkipInvalid {
+				report.Skipped++
+				continue
+			}
+			return report, fmt.Errorf("import line %d: %w", lineNo, err)
+		}
+		chunk = append(chunk, KeyValue[K, V]{Key: key, Value: value})
+		if len(chunk) == opts.ChunkSize {
+			if err := flush(); err != nil {
+				return report, err
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return report, fmt.Errorf("import line %d: %w", lineNo+1, err)
+	}
+	return report, flush()
+}
+
+// importChunk stores the entries under a single lock acquisition
+func (sm *ShrinkableMap[K, V]) importChunk(chunk []KeyValue[K, V]) error {
+	for i, kv := range chunk {
+		value, err := sm.prepareWrite("import", kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+		chunk[i].Value = value
+	}
+
+	needsShrink := false
+	sm.mu.Lock()
+	for _, kv := range chunk {
+		if err := sm.checkInsertLocked("import", kv.Key); err != nil {
+			sm.mu.Unlock()
+			return err
+		}
+	}
+	for _, kv := range chunk {
+		if sm.setLocked(kv.Key, kv.Value) {
+			needsShrink = true
+		}
+	}
+	sm.mu.Unlock()
+
+	if needsShrink {
+		sm.TryShrink()
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package shrinkmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	t.Run("JSON Lines", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		input := `{"key":"a","value":1}
+
+{"key":"b","value":2}
+{"key":"c","value":3}
+`
+		var progress []ImportReport
+		report, err := sm.Import(strings.NewReader(input), JSONCodec[string, int]{}, ImportOptions{
+			ChunkSize: 2,
+			Progress:  func(r ImportReport) { progress = append(progress, r) },
+		})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if report.Lines != 3 || report.Imported != 3 || report.Chunks != 2 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+		if len(progress) != 2 || progress[0].Imported != 2 {
+			t.Errorf("Expected progress after each chunk, got %+v", progress)
+		}
+		if v, _ := sm.Get("c"); v != 3 || sm.Len() != 3 {
+			t.Errorf("Expected 3 imported entries, got len=%d c=%d", sm.Len(), v)
+		}
+	})
+
+	t.Run("Invalid Line", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		input := "{\"key\":\"a\",\"value\":1}\nnot json\n{\"key\":\"b\",\"value\":2}\n"
+		report, err := sm.Import(strings.NewReader(input), JSONCodec[string, int]{}, ImportOptions{})
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("Expected error for line 2, got %v", err)
+		}
+		if report.Imported != 0 {
+			t.Errorf("Expected nothing imported from the unfinished chunk, got %d", report.Imported)
+		}
+
+		report, err = sm.Import(strings.NewReader(input), JSONCodec[string, int]{}, ImportOptions{SkipInvalid: true})
+		if err != nil {
+			t.Fatalf("Import failed: %v", err)
+		}
+		if report.Imported != 2 || report.Skipped != 1 {
+			t.Errorf("Unexpected report: %+v", report)
+		}
+	})
+
+	t.Run("Line Too Long", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig())
+		defer sm.Stop()
+
+		input := `{"key":"a","value":"` + strings.Repeat("x", 100) + `"}`
+		if _, err := sm.Import(strings.NewReader(input), JSONCodec[string, string]{}, ImportOptions{MaxLineSize: 32}); err == nil {
+			t.Error("Expected error for oversized line")
+		}
+	})
+}
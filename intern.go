@@ -0,0 +1,99 @@
+package shrinkmap
+
+import "reflect"
+
+// interner keeps one canonical copy of every distinct value stored in the
+// map with a count of the entries referencing it. All methods must be called
+// with the map lock held; a nil interner leaves values untouched.
+type interner[V any] struct {
+	values map[any]*internedValue[V]
+
+	// Values of interface type are only interned if their dynamic type is comparable
+	dynamic bool
+}
+
+type internedValue[V any] struct {
+	value V
+	refs  int64
+}
+
+// newInterner returns nil unless Config.InternValues is set and V can be compared
+func newInterner[V any](config Config) *interner[V] {
+	t := reflect.TypeOf((*V)(nil)).Elem()
+	if !config.InternValues || !t.Comparable() {
+		return nil
+	}
+	return &interner[V]{
+		values:  make(map[any]*internedValue[V]),
+		dynamic: t.Kind() == reflect.Interface,
+	}
+}
+
+// key returns the lookup key for value, or false if it cannot be interned
+func (in *interner[V]) key(value V) (any, bool) {
+	k := any(value)
+	if in.dynamic && k != nil && !reflect.TypeOf(k).Comparable() {
+		return nil, false
+	}
+	return k, true
+}
+
+// acquire returns the canonical copy of value and takes a reference to it
+func (in *interner[V]) acquire(value V) V {
+	if in == nil {
+		return value
+	}
+	k, ok := in.key(value)
+	if !ok {
+		return value
+	}
+	if e, exists := in.values[k]; exists {
+		e.refs++
+		return e.value
+	}
+	in.values[k] = &internedValue[V]{value: value, refs: 1}
+	return value
+}
+
+// release drops a reference taken by acquire
+func (in *interner[V]) release(value V) {
+	if in == nil {
+		return
+	}
+	k, ok := in.key(value)
+	if !ok {
+		return
+	}
+	if e, exists := in.values[k]; exists {
+		if e.refs--; e.refs <= 0 {
+			delete(in.values, k)
+		}
+	}
+}
+
+// clear drops all references
+func (in *interner[V]) clear() {
+	if in != nil {
+		in.values = make(map[any]*internedValue[V])
+	}
+}
+
+// len returns the number of distinct interned values
+func (in *interner[V]) len() int {
+	if in == nil {
+		return 0
+	}
+	return len(in.values)
+}
+
+// internAllLocked replaces the interned values with those of data, which is
+// updated in place to hold the canonical copies. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) internAllLocked(data map[K]V) {
+	if sm.interner == nil {
+		return
+	}
+	sm.interner.clear()
+	for k, v := range data {
+		data[k] = sm.interner.acquire(v)
+	}
+}
@@ -0,0 +1,82 @@
+package shrinkmap
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestInternValues(t *testing.T) {
+	t.Run("Shares Equal Values", func(t *testing.T) {
+		sm := New[int, string](DefaultConfig().WithInternValues(true))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			// Build each value separately so it has its own backing array
+			sm.Set(i, strings.Repeat("x", 10+i%2))
+		}
+		if n := sm.Stats().InternedValues; n != 2 {
+			t.Errorf("Expected 2 distinct values, got %d", n)
+		}
+
+		a, _ := sm.Get(0)
+		b, _ := sm.Get(2)
+		if unsafe.StringData(a) != unsafe.StringData(b) {
+			t.Error("Expected equal values to share storage")
+		}
+	})
+
+	t.Run("Reference Counting", func(t *testing.T) {
+		sm := New[int, string](DefaultConfig().WithInternValues(true))
+		defer sm.Stop()
+
+		sm.Set(1, "a")
+		sm.Set(2, "a")
+		sm.Set(3, "b")
+		sm.Set(3, "c") // replacing releases b
+		sm.Delete(1)
+		if n := sm.Stats().InternedValues; n != 2 {
+			t.Errorf("Expected values a and c, got %d", n)
+		}
+		sm.ApplyBatch(BatchOperations[int, string]{Operations: []BatchOperation[int, string]{
+			{Type: BatchDelete, Key: 2},
+			{Type: BatchDelete, Key: 3},
+		}})
+		if n := sm.Stats().InternedValues; n != 0 {
+			t.Errorf("Expected no interned values, got %d", n)
+		}
+	})
+
+	t.Run("Replace Data", func(t *testing.T) {
+		sm := New[int, string](DefaultConfig().WithInternValues(true))
+		defer sm.Stop()
+		staging := New[int, string](DefaultConfig().WithInternValues(true))
+		defer staging.Stop()
+
+		sm.Set(1, "old")
+		staging.Set(1, "new")
+		staging.Set(2, "new")
+		if err := sm.Publish(staging); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		if sm.Stats().InternedValues != 1 || staging.Stats().InternedValues != 0 {
+			t.Errorf("Expected interned values to follow the data, got %d and %d",
+				sm.Stats().InternedValues, staging.Stats().InternedValues)
+		}
+	})
+
+	t.Run("Non-Comparable Values", func(t *testing.T) {
+		sm := New[int, []int](DefaultConfig().WithInternValues(true))
+		defer sm.Stop()
+		sm.Set(1, []int{1})
+
+		im := New[int, any](DefaultConfig().WithInternValues(true))
+		defer im.Stop()
+		im.Set(1, []int{1})
+		im.Set(2, "a")
+		im.Set(3, "a")
+		if n := im.Stats().InternedValues; n != 1 {
+			t.Errorf("Expected only comparable dynamic values to be interned, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+// Package scalar converts between generic type parameters and the small set of
+// scalar kinds understood by the bundled binary codecs.
+package scalar
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Kind classifies a scalar value
+type Kind int
+
+const (
+	Invalid Kind = iota
+	Bool
+	Int
+	Uint
+	Float32
+	Float64
+	String
+	Bytes
+)
+
+// Value is a decoded or to-be-encoded scalar
+type Value struct {
+	Kind  Kind
+	Bool  bool
+	Int   int64
+	Uint  uint64
+	Float float64
+	Str   string
+	Bytes []byte
+}
+
+// Of converts v into a scalar Value. Named types whose underlying type is a
+// supported scalar are accepted as well.
+func Of(v any) (Value, error) {
+	switch x := v.(type) {
+	case bool:
+		return Value{Kind: Bool, Bool: x}, nil
+	case int:
+		return Value{Kind: Int, Int: int64(x)}, nil
+	case int8:
+		return Value{Kind: Int, Int: int64(x)}, nil
+	case int16:
+		return Value{Kind: Int, Int: int64(x)}, nil
+	case int32:
+		return Value{Kind: Int, Int: int64(x)}, nil
+	case int64:
+		return Value{Kind: Int, Int: x}, nil
+	case uint:
+		return Value{Kind: Uint, Uint: uint64(x)}, nil
+	case uint8:
+		return Value{Kind: Uint, Uint: uint64(x)}, nil
+	case uint16:
+		return Value{Kind: Uint, Uint: uint64(x)}, nil
+	case uint32:
+		return Value{Kind: Uint, Uint: uint64(x)}, nil
+	case uint64:
+		return Value{Kind: Uint, Uint: x}, nil
+	case uintptr:
+		return Value{Kind: Uint, Uint: uint64(x)}, nil
+	case float32:
+		return Value{Kind: Float32, Float: float64(x)}, nil
+	case float64:
+		return Value{Kind: Float64, Float: x}, nil
+	case string:
+		return Value{Kind: String, Str: x}, nil
+	case []byte:
+		return Value{Kind: Bytes, Bytes: x}, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		return Value{Kind: Bool, Bool: rv.Bool()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Value{Kind: Int, Int: rv.Int()}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return Value{Kind: Uint, Uint: rv.Uint()}, nil
+	case reflect.Float32:
+		return Value{Kind: Float32, Float: rv.Float()}, nil
+	case reflect.Float64:
+		return Value{Kind: Float64, Float: rv.Float()}, nil
+	case reflect.String:
+		return Value{Kind: String, Str: rv.String()}, nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return Value{Kind: Bytes, Bytes: rv.Bytes()}, nil
+		}
+	}
+	return Value{}, fmt.Errorf("unsupported type %T", v)
+}
+
+// KindOf reports the scalar kind used to encode values of type T
+func KindOf[T any]() (Kind, error) {
+	var zero T
+	v, err := Of(zero)
+	return v.Kind, err
+}
+
+// Assign stores v into *dst, converting between numeric kinds when the value fits
+func Assign[T any](dst *T, v Value) error {
+	rv := reflect.ValueOf(dst).Elem()
+	switch rv.Kind() {
+	case reflect.Bool:
+		if v.Kind != Bool {
+			return mismatch(rv, v)
+		}
+		rv.SetBool(v.Bool)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var i int64
+		switch v.Kind {
+		case Int:
+			i = v.Int
+		case Uint:
+			if v.Uint > math.MaxInt64 {
+				return overflow(rv, v)
+			}
+			i = int64(v.Uint)
+		default:
+			return mismatch(rv, v)
+		}
+		if rv.OverflowInt(i) {
+			return overflow(rv, v)
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var u uint64
+		switch v.Kind {
+		case Uint:
+			u = v.Uint
+		case Int:
+			if v.Int < 0 {
+				return overflow(rv, v)
+			}
+			u = uint64(v.Int)
+		default:
+			return mismatch(rv, v)
+		}
+		if rv.OverflowUint(u) {
+			return overflow(rv, v)
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		switch v.Kind {
+		case Float32, Float64:
+			rv.SetFloat(v.Float)
+		case Int:
+			rv.SetFloat(float64(v.Int))
+		case Uint:
+			rv.SetFloat(float64(v.Uint))
+		default:
+			return mismatch(rv, v)
+		}
+	case reflect.String:
+		switch v.Kind {
+		case String:
+			rv.SetString(v.Str)
+		case Bytes:
+			rv.SetString(string(v.Bytes))
+		default:
+			return mismatch(rv, v)
+		}
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", rv.Type())
+		}
+		switch v.Kind {
+		case Bytes:
+			rv.SetBytes(append([]byte(nil), v.Bytes...))
+		case String:
+			rv.SetBytes([]byte(v.Str))
+		default:
+			return mismatch(rv, v)
+		}
+	default:
+		return fmt.Errorf("unsupported type %s", rv.Type())
+	}
+	return nil
+}
+
+func mismatch(rv reflect.Value, v Value) error {
+	return fmt.Errorf("cannot decode %s into %s", v.Kind, rv.Type())
+}
+
+func overflow(rv reflect.Value, v Value) error {
+	return fmt.Errorf("value %s overflows %s", v, rv.Type())
+}
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case Bool:
+		return "bool"
+	case Int:
+		return "int"
+	case Uint:
+		return "uint"
+	case Float32:
+		return "float32"
+	case Float64:
+		return "float64"
+	case String:
+		return "string"
+	case Bytes:
+		return "bytes"
+	default:
+		return "invalid"
+	}
+}
+
+// String formats the value for error messages
+func (v Value) String() string {
+	switch v.Kind {
+	case Bool:
+		return fmt.Sprint(v.Bool)
+	case Int:
+		return fmt.Sprint(v.Int)
+	case Uint:
+		return fmt.Sprint(v.Uint)
+	case Float32, Float64:
+		return fmt.Sprint(v.Float)
+	case String:
+		return fmt.Sprintf("%q", v.Str)
+	case Bytes:
+		return fmt.Sprintf("%d bytes", len(v.Bytes))
+	default:
+		return "invalid"
+	}
+}
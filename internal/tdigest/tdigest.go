@@ -0,0 +1,121 @@
+// Package tdigest implements the merging t-digest of Dunning and Ertl, a
+// compact sketch estimating quantiles of a stream of values with high
+// accuracy near the tails.
+package tdigest
+
+import (
+	"math"
+	"sort"
+)
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// Digest accumulates values. The zero value is not usable; use New.
+type Digest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min, max    float64
+}
+
+// New returns an empty digest. Higher compression keeps more centroids and
+// gives more accurate quantiles; 100 is a common choice.
+func New(compression float64) *Digest {
+	return &Digest{
+		compression: compression,
+		buffer:      make([]centroid, 0, int(compression)*5),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+// Add adds a value to the digest
+func (d *Digest) Add(x float64) {
+	if math.IsNaN(x) {
+		return
+	}
+	d.buffer = append(d.buffer, centroid{mean: x, weight: 1})
+	d.count++
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+	if len(d.buffer) == cap(d.buffer) {
+		d.flush()
+	}
+}
+
+// Count returns the number of values added
+func (d *Digest) Count() float64 {
+	return d.count
+}
+
+// flush merges buffered values into the centroids
+func (d *Digest) flush() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := append(d.buffer, d.centroids...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	current := all[0]
+	seen := 0.0 // weight before current
+	kLeft := d.scale(0)
+	for _, c := range all[1:] {
+		q := (seen + current.weight + c.weight) / d.count
+		if d.scale(q)-kLeft <= 1 {
+			current.mean += (c.mean - current.mean) * c.weight / (current.weight + c.weight)
+			current.weight += c.weight
+			continue
+		}
+		seen += current.weight
+		kLeft = d.scale(seen / d.count)
+		merged = append(merged, current)
+		current = c
+	}
+	d.centroids = append(merged, current)
+	d.buffer = d.buffer[:0]
+}
+
+// scale is the k1 scale function mapping quantiles to centroid indices,
+// which keeps centroids small near the tails
+func (d *Digest) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*math.Min(math.Max(q, 0), 1)-1)
+}
+
+// Quantile returns the estimated value at quantile q in [0, 1], or NaN if the
+// digest is empty
+func (d *Digest) Quantile(q float64) float64 {
+	d.flush()
+	c := d.centroids
+	switch {
+	case len(c) == 0:
+		return math.NaN()
+	case q <= 0:
+		return d.min
+	case q >= 1:
+		return d.max
+	case len(c) == 1:
+		return c[0].mean
+	}
+
+	// Each centroid is centered on its cumulative weight; interpolate between
+	// neighboring centers, and between the extremes and the outer centers
+	index := q * d.count
+	if first := c[0].weight / 2; index < first {
+		return d.min + (c[0].mean-d.min)*index/first
+	}
+	cumulative := c[0].weight / 2
+	for i := 0; i < len(c)-1; i++ {
+		dw := (c[i].weight + c[i+1].weight) / 2
+		if index < cumulative+dw {
+			return c[i].mean + (c[i+1].mean-c[i].mean)*(index-cumulative)/dw
+		}
+		cumulative += dw
+	}
+	last := c[len(c)-1]
+	return last.mean + (d.max-last.mean)*math.Min((index-cumulative)/(last.weight/2), 1)
+}
@@ -1,27 +1,180 @@
 package shrinkmap
 
+import "sort"
+
 // Iterator provides a safe way to iterate over map entries
 type Iterator[K comparable, V any] struct {
 	sm       *ShrinkableMap[K, V]
 	snapshot []KeyValue[K, V]
 	index    int
+	buf      *[]KeyValue[K, V] // pooled snapshot buffer, nil once released
+
+	// pull produces the entries of iterators derived with combinators such as
+	// Filter; it is nil for iterators over a snapshot
+	pull    func() (KeyValue[K, V], bool)
+	reset   func()
+	pending KeyValue[K, V]
+	hasNext bool
 }
 
-// NewIterator creates a new iterator for the map
+// NewIterator creates a new iterator for the map. The snapshot buffer is
+// pooled; call Release when done to let later iterators reuse it.
 func (sm *ShrinkableMap[K, V]) NewIterator() *Iterator[K, V] {
+	buf := sm.snapshotBuffer()
 	return &Iterator[K, V]{
 		sm:       sm,
-		snapshot: sm.Snapshot(),
+		snapshot: *buf,
 		index:    0,
+		buf:      buf,
+	}
+}
+
+// Release returns the snapshot buffer of an iterator created by NewIterator
+// or NewSortedIterator to the map's pool. The iterator and any iterators
+// derived from it are exhausted afterwards. Release is a no-op on derived
+// iterators; release their source instead.
+func (it *Iterator[K, V]) Release() {
+	if it.buf != nil {
+		it.sm.releaseBuffer(it.buf)
+		it.buf = nil
+		it.snapshot = nil
+		it.index = 0
 	}
 }
 
 func (it *Iterator[K, V]) Next() bool {
-	return it.index < len(it.snapshot)
+	if it.pull == nil {
+		return it.index < len(it.snapshot)
+	}
+	if !it.hasNext {
+		it.pending, it.hasNext = it.pull()
+	}
+	return it.hasNext
 }
 
 func (it *Iterator[K, V]) Get() (K, V) {
-	item := it.snapshot[it.index]
-	it.index++
-	return item.Key, item.Value
+	if it.pull == nil {
+		item := it.snapshot[it.index]
+		it.index++
+		return item.Key, item.Value
+	}
+	if !it.Next() {
+		panic("shrinkmap: Get called on exhausted iterator")
+	}
+	it.hasNext = false
+	return it.pending.Key, it.pending.Value
+}
+
+// Reset restarts the iteration from the beginning of the same snapshot.
+// Resetting a derived iterator also resets the iterators it was derived from.
+func (it *Iterator[K, V]) Reset() {
+	if it.pull == nil {
+		it.index = 0
+		return
+	}
+	it.hasNext = false
+	it.pending = KeyValue[K, V]{}
+	it.reset()
+}
+
+// derive returns an iterator producing the entries returned by pull.
+// Derived iterators consume their source lazily, one entry at a time.
+// reset restores the state of pull and its source.
+func derive[K comparable, V any](pull func() (KeyValue[K, V], bool), reset func()) *Iterator[K, V] {
+	return &Iterator[K, V]{pull: pull, reset: reset}
+}
+
+// Filter returns an iterator over the remaining entries for which pred returns true
+func (it *Iterator[K, V]) Filter(pred func(K, V) bool) *Iterator[K, V] {
+	return derive(func() (KeyValue[K, V], bool) {
+		for it.Next() {
+			k, v := it.Get()
+			if pred(k, v) {
+				return KeyValue[K, V]{Key: k, Value: v}, true
+			}
+		}
+		return KeyValue[K, V]{}, false
+	}, it.Reset)
+}
+
+// Take returns an iterator over at most the next n entries
+func (it *Iterator[K, V]) Take(n int) *Iterator[K, V] {
+	taken := 0
+	return derive(func() (KeyValue[K, V], bool) {
+		if taken >= n || !it.Next() {
+			return KeyValue[K, V]{}, false
+		}
+		taken++
+		k, v := it.Get()
+		return KeyValue[K, V]{Key: k, Value: v}, true
+	}, func() {
+		taken = 0
+		it.Reset()
+	})
+}
+
+// Skip returns an iterator over the remaining entries after the next n
+func (it *Iterator[K, V]) Skip(n int) *Iterator[K, V] {
+	skipped := 0
+	return derive(func() (KeyValue[K, V], bool) {
+		for ; skipped < n && it.Next(); skipped++ {
+			it.Get()
+		}
+		if !it.Next() {
+			return KeyValue[K, V]{}, false
+		}
+		k, v := it.Get()
+		return KeyValue[K, V]{Key: k, Value: v}, true
+	}, func() {
+		skipped = 0
+		it.Reset()
+	})
+}
+
+// MapTo returns an iterator over the remaining entries of it with each value
+// replaced by fn(key, value)
+func MapTo[K comparable, V, U any](it *Iterator[K, V], fn func(K, V) U) *Iterator[K, U] {
+	return derive(func() (KeyValue[K, U], bool) {
+		if !it.Next() {
+			return KeyValue[K, U]{}, false
+		}
+		k, v := it.Get()
+		return KeyValue[K, U]{Key: k, Value: fn(k, v)}, true
+	}, it.Reset)
+}
+
+// Collect consumes the iterator and returns the remaining entries
+func (it *Iterator[K, V]) Collect() []KeyValue[K, V] {
+	var result []KeyValue[K, V]
+	for it.Next() {
+		k, v := it.Get()
+		result = append(result, KeyValue[K, V]{Key: k, Value: v})
+	}
+	return result
+}
+
+// SortedIterator iterates over a snapshot ordered by key and supports Seek
+type SortedIterator[K comparable, V any] struct {
+	*Iterator[K, V]
+	less func(a, b K) bool
+}
+
+// NewSortedIterator creates an iterator over a snapshot of the map ordered by less
+func (sm *ShrinkableMap[K, V]) NewSortedIterator(less func(a, b K) bool) *SortedIterator[K, V] {
+	it := sm.NewIterator()
+	sort.Slice(it.snapshot, func(i, j int) bool {
+		return less(it.snapshot[i].Key, it.snapshot[j].Key)
+	})
+	return &SortedIterator[K, V]{Iterator: it, less: less}
+}
+
+// Seek advances the iterator to the first remaining entry whose key is not
+// less than key and reports whether such an entry exists. It never moves
+// backwards; use Reset first to seek to an earlier key.
+func (it *SortedIterator[K, V]) Seek(key K) bool {
+	rest := it.snapshot[it.index:]
+	it.index += sort.Search(len(rest), func(i int) bool {
+		return !it.less(rest[i].Key, key)
+	})
+	return it.Next()
 }
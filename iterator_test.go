@@ -1,6 +1,7 @@
 package shrinkmap
 
 import (
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -168,15 +169,17 @@ func TestIterator(t *testing.T) {
 			sm.Set(i, i)
 		}
 
-		var wg sync.WaitGroup
+		var wg, created sync.WaitGroup
 		numGoroutines := 10
 
 		for i := 0; i < numGoroutines; i++ {
 			wg.Add(1)
+			created.Add(1)
 			go func(routineID int) {
 				defer wg.Done()
 
 				iter := sm.NewIterator()
+				created.Done()
 				localSum := 0
 				for iter.Next() {
 					_, v := iter.Get()
@@ -191,7 +194,9 @@ func TestIterator(t *testing.T) {
 			}(i)
 		}
 
+		// Writes start once every iterator holds its snapshot
 		go func() {
+			created.Wait()
 			for i := 0; i < 100; i++ {
 				sm.Set(1000+i, i)
 				time.Sleep(time.Millisecond)
@@ -231,3 +236,132 @@ func TestIterator(t *testing.T) {
 		}
 	})
 }
+
+func TestIteratorCombinators(t *testing.T) {
+	newMap := func() *ShrinkableMap[int, int] {
+		sm := New[int, int](DefaultConfig())
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i*10)
+		}
+		return sm
+	}
+
+	t.Run("Filter Take", func(t *testing.T) {
+		sm := newMap()
+		defer sm.Stop()
+
+		result := sm.NewIterator().Filter(func(k, v int) bool { return k%2 == 0 }).Take(3).Collect()
+		if len(result) != 3 {
+			t.Fatalf("Expected 3 entries, got %d", len(result))
+		}
+		for _, kv := range result {
+			if kv.Key%2 != 0 || kv.Value != kv.Key*10 {
+				t.Errorf("Unexpected entry %v", kv)
+			}
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		sm := newMap()
+		defer sm.Stop()
+
+		if result := sm.NewIterator().Skip(7).Collect(); len(result) != 3 {
+			t.Errorf("Expected 3 entries after skipping 7, got %d", len(result))
+		}
+		if result := sm.NewIterator().Skip(20).Collect(); len(result) != 0 {
+			t.Errorf("Expected no entries after skipping past the end, got %d", len(result))
+		}
+	})
+
+	t.Run("Map To", func(t *testing.T) {
+		sm := newMap()
+		defer sm.Stop()
+
+		it := MapTo(sm.NewIterator(), func(k, v int) string { return fmt.Sprint(v) })
+		count := 0
+		for it.Next() {
+			k, v := it.Get()
+			if v != fmt.Sprint(k*10) {
+				t.Errorf("Unexpected mapped value %q for key %d", v, k)
+			}
+			count++
+		}
+		if count != 10 {
+			t.Errorf("Expected 10 entries, got %d", count)
+		}
+	})
+
+	t.Run("Lazy Evaluation", func(t *testing.T) {
+		sm := newMap()
+		defer sm.Stop()
+
+		calls := 0
+		it := sm.NewIterator().Filter(func(k, v int) bool {
+			calls++
+			return true
+		}).Take(2)
+		it.Collect()
+		if calls != 2 {
+			t.Errorf("Expected the predicate to run only for taken entries, got %d calls", calls)
+		}
+	})
+}
+
+func TestIteratorResetAndSeek(t *testing.T) {
+	sm := New[int, int](DefaultConfig())
+	defer sm.Stop()
+	for i := 0; i < 10; i++ {
+		sm.Set(i, i)
+	}
+
+	t.Run("Reset", func(t *testing.T) {
+		it := sm.NewIterator()
+		first := it.Collect()
+		sm.Set(100, 100)
+		defer sm.Delete(100)
+
+		it.Reset()
+		if second := it.Collect(); len(second) != len(first) {
+			t.Errorf("Expected reset to replay the same snapshot, got %d and %d entries", len(first), len(second))
+		}
+	})
+
+	t.Run("Reset Derived", func(t *testing.T) {
+		it := sm.NewIterator().Filter(func(k, v int) bool { return k%2 == 0 }).Take(2)
+		if n := len(it.Collect()); n != 2 {
+			t.Fatalf("Expected 2 entries, got %d", n)
+		}
+		it.Reset()
+		if n := len(it.Collect()); n != 2 {
+			t.Errorf("Expected 2 entries after reset, got %d", n)
+		}
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		it := sm.NewSortedIterator(func(a, b int) bool { return a < b })
+		if !it.Seek(5) {
+			t.Fatal("Expected entry at key 5")
+		}
+		if k, _ := it.Get(); k != 5 {
+			t.Errorf("Expected key 5, got %d", k)
+		}
+		if !it.Seek(3) {
+			t.Fatal("Expected remaining entries")
+		}
+		if k, _ := it.Get(); k != 6 {
+			t.Errorf("Seek must not move backwards, got key %d", k)
+		}
+		if it.Seek(42) {
+			t.Error("Expected no entry past the last key")
+		}
+
+		it.Reset()
+		it.Seek(0)
+		keys := it.Collect()
+		for i, kv := range keys {
+			if kv.Key != i {
+				t.Fatalf("Expected sorted keys, got %v", keys)
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package shrinkmap
+
+import (
+	"reflect"
+	"strings"
+	"unsafe"
+)
+
+const (
+	// keyArenaBlockSize is the size of the blocks string keys are copied into
+	keyArenaBlockSize = 64 << 10
+
+	// keyArenaMaxKey is the largest key stored in a block; longer keys get
+	// their own allocation so they do not waste the rest of a block
+	keyArenaMaxKey = keyArenaBlockSize / 16
+)
+
+// keyArena copies string keys into large shared blocks, so inserted keys
+// neither retain the buffers they were sliced from nor fragment the heap
+// with many small allocations. Blocks are released when a shrink copies the
+// live keys into a fresh arena. Must be used with the map lock held; a nil
+// arena leaves keys untouched.
+type keyArena[K comparable] struct {
+	block []byte
+	size  int64 // bytes allocated for blocks and large keys
+}
+
+// newKeyArena returns nil unless Config.InternKeys is set and K is a string type
+func newKeyArena[K comparable](config Config) *keyArena[K] {
+	if !config.InternKeys || reflect.TypeOf((*K)(nil)).Elem().Kind() != reflect.String {
+		return nil
+	}
+	return &keyArena[K]{}
+}
+
+// intern returns a copy of key backed by the arena
+func (a *keyArena[K]) intern(key K) K {
+	if a == nil {
+		return key
+	}
+	// K has an underlying string type, so it shares the string layout
+	s := *(*string)(unsafe.Pointer(&key))
+	if len(s) == 0 {
+		return key
+	}
+
+	var copied string
+	if len(s) > keyArenaMaxKey {
+		copied = strings.Clone(s)
+		a.size += int64(len(s))
+	} else {
+		if len(s) > cap(a.block)-len(a.block) {
+			a.block = make([]byte, 0, keyArenaBlockSize)
+			a.size += keyArenaBlockSize
+		}
+		start := len(a.block)
+		a.block = append(a.block, s...)
+		copied = unsafe.String(&a.block[start], len(s))
+	}
+	return *(*K)(unsafe.Pointer(&copied))
+}
+
+// bytes returns the number of bytes allocated by the arena
+func (a *keyArena[K]) bytes() int64 {
+	if a == nil {
+		return 0
+	}
+	return a.size
+}
+
+// renew returns an empty arena of the same kind, used to compact keys on shrink
+func (a *keyArena[K]) renew() *keyArena[K] {
+	if a == nil {
+		return nil
+	}
+	return &keyArena[K]{}
+}
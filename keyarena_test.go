@@ -0,0 +1,73 @@
+package shrinkmap
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestInternKeys(t *testing.T) {
+	t.Run("Keys Do Not Retain Source Buffers", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithInternKeys(true))
+		defer sm.Stop()
+
+		buf := strings.Repeat("k", 1<<20)
+		sm.Set(buf[:3], 1)
+
+		for _, kv := range sm.Snapshot() {
+			if unsafe.StringData(kv.Key) == unsafe.StringData(buf) {
+				t.Error("Expected key to be copied out of the source buffer")
+			}
+		}
+		if v, ok := sm.Get("kkk"); !ok || v != 1 {
+			t.Errorf("Expected kkk=1, got %d, %v", v, ok)
+		}
+		if sm.Stats().KeyArenaBytes != keyArenaBlockSize {
+			t.Errorf("Expected one arena block, got %d bytes", sm.Stats().KeyArenaBytes)
+		}
+	})
+
+	t.Run("Shrink Compacts Keys", func(t *testing.T) {
+		config := DefaultConfig().WithInternKeys(true)
+		config.AutoShrinkEnabled = false
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		key := strings.Repeat("x", 1000)
+		for i := 0; i < 1000; i++ {
+			sm.Set(key+string(rune('a'+i%26))+strings.Repeat("y", i/26), i)
+		}
+		before := sm.Stats().KeyArenaBytes
+		for i := 0; i < 990; i++ {
+			sm.Delete(key + string(rune('a'+i%26)) + strings.Repeat("y", i/26))
+		}
+		sm.ForceShrink()
+
+		after := sm.Stats().KeyArenaBytes
+		if after >= before || sm.Len() != 10 {
+			t.Errorf("Expected shrink to compact keys, before=%d after=%d len=%d", before, after, sm.Len())
+		}
+		for _, kv := range sm.Snapshot() {
+			if v, ok := sm.Get(kv.Key); !ok || v != kv.Value {
+				t.Errorf("Lookup failed after compaction for value %d", kv.Value)
+			}
+		}
+	})
+
+	t.Run("Named And Non-String Keys", func(t *testing.T) {
+		type id string
+		sm := New[id, int](DefaultConfig().WithInternKeys(true))
+		defer sm.Stop()
+		sm.Set("a", 1)
+		if v, _ := sm.Get("a"); v != 1 {
+			t.Errorf("Expected a=1, got %d", v)
+		}
+
+		im := New[int, int](DefaultConfig().WithInternKeys(true))
+		defer im.Stop()
+		im.Set(1, 1)
+		if im.Stats().KeyArenaBytes != 0 {
+			t.Error("Expected non-string keys to be left alone")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package shrinkmap
+
+import "sync"
+
+// keyLockTable hands out one mutex per locked key. Entries are reference
+// counted and removed when the last holder or waiter releases them, so the
+// table only grows with the number of keys locked concurrently.
+type keyLockTable[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyLock
+}
+
+type keyLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// LockKey acquires an exclusive lock on key and returns the function releasing
+// it. The lock is held independently of the map lock, so callers can perform
+// long external work tied to the key, e.g. file I/O, without blocking other
+// keys or map operations. It is advisory: only other LockKey callers are
+// excluded, while Set, Get and Delete proceed as usual. The returned function
+// may be called more than once; only the first call releases the lock.
+func (sm *ShrinkableMap[K, V]) LockKey(key K) func() {
+	t := &sm.keyLocks
+	t.mu.Lock()
+	if t.locks == nil {
+		t.locks = make(map[K]*keyLock)
+	}
+	l, exists := t.locks[key]
+	if !exists {
+		l = &keyLock{}
+		t.locks[key] = l
+	}
+	l.refs++
+	t.mu.Unlock()
+
+	l.mu.Lock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Unlock()
+			t.mu.Lock()
+			if l.refs--; l.refs == 0 {
+				delete(t.locks, key)
+			}
+			t.mu.Unlock()
+		})
+	}
+}
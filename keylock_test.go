@@ -0,0 +1,67 @@
+package shrinkmap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLockKey(t *testing.T) {
+	t.Run("Excludes Same Key", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		counter := 0
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := sm.LockKey("a")
+				defer unlock()
+				v := counter
+				time.Sleep(time.Microsecond)
+				counter = v + 1
+			}()
+		}
+		wg.Wait()
+
+		if counter != 50 {
+			t.Errorf("Expected 50 serialized increments, got %d", counter)
+		}
+		if len(sm.keyLocks.locks) != 0 {
+			t.Errorf("Expected lock table to be empty, got %d entries", len(sm.keyLocks.locks))
+		}
+	})
+
+	t.Run("Independent Keys And Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		unlock := sm.LockKey("a")
+		defer unlock()
+
+		done := make(chan struct{})
+		go func() {
+			sm.LockKey("b")()
+			sm.Set("a", 1)
+			sm.Get("a")
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Other keys and map operations must not be blocked")
+		}
+	})
+
+	t.Run("Unlock Is Idempotent", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		unlock := sm.LockKey("a")
+		unlock()
+		unlock()
+		sm.LockKey("a")()
+	})
+}
@@ -0,0 +1,565 @@
+package shrinkmap
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// linkedEntry is a node of the insertion-order list. The value is never
+// modified once the node is stored, so readers need no list lock; updates
+// replace the node in place in the list.
+type linkedEntry[K comparable, V any] struct {
+	key        K
+	value      V
+	inserted   time.Time
+	maxAge     time.Duration // under EvictAge; 0 if the entry never expires
+	prev, next *linkedEntry[K, V]
+}
+
+// LinkedShrinkableMap is a ShrinkableMap that remembers insertion order.
+// Entries are kept in an intrusive doubly linked list, so iteration follows
+// insertion order and the oldest and newest entries are found in O(1).
+// Updating an existing key keeps its position and insertion time. Shrinking,
+// metrics and value hooks behave as for ShrinkableMap; Config.DeleteInvalidOnGet
+// is ignored and retained history is not supported.
+//
+// Config.Eviction selects automatic eviction: EvictFIFO removes the oldest
+// entries whenever a write exceeds MaxEntries, EvictAge removes entries older
+// than MaxEntryAge on writes and hides them from reads until then. Under
+// EvictAge a background goroutine also runs EvictExpired every shortest
+// maximum age, so idle maps release expired entries too.
+//
+// With Config.AgeRules, string keys matching a rule get the rule's maximum
+// age instead. Entries then no longer expire in insertion order: writes only
+// evict expired entries at the oldest end, and EvictExpired checks them all.
+// Rules require string keys; for other key types NewLinked records an error
+// and ignores them.
+type LinkedShrinkableMap[K comparable, V any] struct {
+	mu       sync.RWMutex // guards the list; taken before the map lock
+	sm       *ShrinkableMap[K, *linkedEntry[K, V]]
+	head     *linkedEntry[K, V] // oldest
+	tail     *linkedEntry[K, V] // newest
+	config   Config
+	ageRules []AgeRule // Config.AgeRules if keys are strings
+	onEvict  func([]KeyValue[K, V], EvictionReason)
+	sweepAt  *linkedEntry[K, V] // where a full expiration sweep cut short resumes
+	now      func() time.Time
+
+	cancel      context.CancelFunc
+	done        chan struct{}
+	sweepStatus loopState // expiration goroutine, reported by Status
+}
+
+// NewLinked creates a new insertion-ordered map with the given configuration
+func NewLinked[K comparable, V any](config Config) *LinkedShrinkableMap[K, V] {
+	ctx, cancel := context.WithCancel(context.Background())
+	lm := &LinkedShrinkableMap[K, V]{
+		sm:     New[K, *linkedEntry[K, V]](linkedConfig[K, V](config)),
+		config: config,
+		now:    time.Now,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if len(config.AgeRules) > 0 {
+		if err := requireStringKeys[K](); err != nil {
+			lm.sm.metrics.RecordError(fmt.Errorf("age rules ignored: %w", err), "")
+		} else {
+			lm.ageRules = config.AgeRules
+		}
+	}
+	if interval := lm.sweepInterval(); interval > 0 {
+		lm.sweepStatus.start()
+		go lm.sweepLoop(ctx, interval)
+	} else {
+		close(lm.done)
+	}
+	return lm
+}
+
+// sweepInterval returns the shortest positive maximum age under EvictAge,
+// or 0 if no entry can expire
+func (lm *LinkedShrinkableMap[K, V]) sweepInterval() time.Duration {
+	if lm.config.Eviction != EvictAge {
+		return 0
+	}
+	interval := lm.config.MaxEntryAge
+	for _, rule := range lm.ageRules {
+		if rule.MaxAge > 0 && (interval <= 0 || rule.MaxAge < interval) {
+			interval = rule.MaxAge
+		}
+	}
+	return max(interval, 0)
+}
+
+// sweepLoop runs EvictExpired every interval until ctx is canceled
+func (lm *LinkedShrinkableMap[K, V]) sweepLoop(ctx context.Context, interval time.Duration) {
+	defer close(lm.done)
+	defer lm.sweepStatus.exit(false)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			lm.sweepStatus.tick()
+			if lm.EvictExpired() > 0 {
+				lm.sweepStatus.active()
+			}
+		}
+	}
+}
+
+// linkedConfig adapts the value hooks of config to list nodes
+func linkedConfig[K comparable, V any](config Config) Config {
+	// Eviction is done by the list, not the underlying map
+	return entryConfig(config.withoutEviction(),
+		func(e *linkedEntry[K, V]) V { return e.value },
+		func(e *linkedEntry[K, V], v V) { e.value = v })
+}
+
+// entryConfig adapts the value hooks of config to a map storing entries of
+// type E that wrap values of type V. Entries must not be visible to readers
+// before they are stored, so TransformOnSet can update them in place with
+// setValue. Entry maps do not support DeleteInvalidOnGet, CopyOnRead or history.
+func entryConfig[E, V any](config Config, value func(E) V, setValue func(E, V)) Config {
+	get := func(v any) V { return value(v.(E)) }
+
+	if validate := config.ValidateValue; validate != nil {
+		config.ValidateValue = func(v any) error { return validate(get(v)) }
+	}
+	if transform := config.TransformOnSet; transform != nil {
+		config.TransformOnSet = func(key, v any) any {
+			e := v.(E)
+			result := transform(key, value(e))
+			if transformed, ok := result.(V); ok {
+				setValue(e, transformed)
+				return e
+			}
+			return result // rejected by the map as a type mismatch
+		}
+	}
+	if clone := config.CloneValue; clone != nil {
+		// Only written entries are copied, and those are not yet shared
+		config.CloneValue = func(v any) any {
+			e := v.(E)
+			if cloned, ok := clone(value(e)).(V); ok {
+				setValue(e, cloned)
+			}
+			return e
+		}
+	}
+	if config.MaxValueBytes > 0 {
+		sizer := config.ValueSizer
+		if sizer == nil {
+			sizer = defaultSizer
+		}
+		config.ValueSizer = func(v any) int { return sizer(get(v)) }
+	}
+	if validate := config.ValidateOnGet; validate != nil {
+		config.ValidateOnGet = func(key, v any) bool { return validate(key, get(v)) }
+	}
+	config.DeleteInvalidOnGet = false
+	config.CopyOnRead = false
+	config.HistoryRetention = 0
+	return config
+}
+
+// Set stores the pair. A new key is appended as the newest entry; an
+// existing key keeps its position. A key whose entry has expired but not yet
+// been evicted is stored as a new entry.
+func (lm *LinkedShrinkableMap[K, V]) Set(key K, value V) error {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	now := lm.now()
+	lm.evictExpiredLocked(now)
+
+	e := &linkedEntry[K, V]{key: key, value: value, inserted: now, maxAge: lm.maxAge(key)}
+	old, exists := lm.lookupLocked(key)
+	expired := exists && lm.expired(old, now)
+	if exists && !expired {
+		e.inserted = old.inserted
+	}
+	if err := lm.sm.SetChecked(key, e); err != nil {
+		return err
+	}
+	switch {
+	case expired:
+		lm.unlink(old)
+		lm.pushBack(e)
+	case exists:
+		lm.replace(old, e)
+	default:
+		lm.pushBack(e)
+	}
+	if lm.config.Eviction == EvictFIFO {
+		lm.trimToSizeLocked(lm.config.MaxEntries, EvictedOverCapacity)
+	}
+	return nil
+}
+
+// EvictExpired removes entries older than their maximum age under EvictAge
+// and returns how many were removed. With a Config.SweepBudget, expired
+// entries beyond the budget are left for later calls.
+func (lm *LinkedShrinkableMap[K, V]) EvictExpired() int {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	now := lm.now()
+	if len(lm.ageRules) == 0 {
+		return lm.evictExpiredLocked(now)
+	}
+	// Maximum ages differ by key, so every entry has to be checked. A sweep
+	// cut short by the budget resumes where it stopped.
+	limit := newSweepLimit(lm.config.SweepBudget)
+	var evicted []*linkedEntry[K, V]
+	e := lm.head
+	if lm.sweepAt != nil {
+		e = lm.sweepAt
+	}
+	for e != nil && limit.more(len(evicted)) {
+		next := e.next
+		if lm.expired(e, now) {
+			evicted = append(evicted, e)
+			lm.unlink(e)
+		}
+		e = next
+	}
+	lm.sweepAt = e
+	return lm.removeLocked(evicted, EvictedExpired)
+}
+
+// evictExpiredLocked removes expired entries from the oldest end within the
+// sweep budget. Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) evictExpiredLocked(now time.Time) int {
+	if lm.config.Eviction != EvictAge {
+		return 0
+	}
+	limit := newSweepLimit(lm.config.SweepBudget)
+	n := 0
+	return lm.evictLocked(EvictedExpired, func(e *linkedEntry[K, V]) bool {
+		if !limit.more(n) || !lm.expired(e, now) {
+			return false
+		}
+		n++
+		return true
+	})
+}
+
+// sweepLimit tracks an expiration sweep against its budget
+type sweepLimit struct {
+	budget  SweepBudget
+	start   time.Time
+	checked int
+}
+
+func newSweepLimit(budget SweepBudget) *sweepLimit {
+	s := &sweepLimit{budget: budget}
+	if budget.MaxDuration > 0 {
+		s.start = time.Now()
+	}
+	return s
+}
+
+// more reports whether the sweep may examine another entry after evicting
+// evicted entries. The clock is only read every 64 entries.
+func (s *sweepLimit) more(evicted int) bool {
+	if s.budget.MaxEntries > 0 && evicted >= s.budget.MaxEntries {
+		return false
+	}
+	s.checked++
+	return s.budget.MaxDuration <= 0 || s.checked%64 != 0 || time.Since(s.start) < s.budget.MaxDuration
+}
+
+// maxAge returns the maximum age of key under EvictAge: that of the first
+// matching age rule, or Config.MaxEntryAge
+func (lm *LinkedShrinkableMap[K, V]) maxAge(key K) time.Duration {
+	if len(lm.ageRules) > 0 {
+		s := reflect.ValueOf(key).String()
+		for _, rule := range lm.ageRules {
+			if rule.matches(s) {
+				return rule.MaxAge
+			}
+		}
+	}
+	return lm.config.MaxEntryAge
+}
+
+// matches reports whether key starts with the rule's prefix or matches its pattern
+func (r AgeRule) matches(key string) bool {
+	if r.Pattern == "" {
+		return strings.HasPrefix(key, r.Prefix)
+	}
+	matched, _ := path.Match(r.Pattern, key)
+	return matched
+}
+
+// evictLocked removes entries from the oldest end while more returns true
+// and records them as evictions. The map lock is taken once for all of them.
+// Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) evictLocked(reason EvictionReason, more func(oldest *linkedEntry[K, V]) bool) int {
+	var evicted []*linkedEntry[K, V]
+	for lm.head != nil && more(lm.head) {
+		evicted = append(evicted, lm.head)
+		lm.unlink(lm.head)
+	}
+	return lm.removeLocked(evicted, reason)
+}
+
+// removeLocked deletes entries already unlinked from the list, records them
+// as evictions and passes them to the eviction callback in one batch. Must be
+// called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) removeLocked(evicted []*linkedEntry[K, V], reason EvictionReason) int {
+	if len(evicted) == 0 {
+		return 0
+	}
+	keys := make([]K, len(evicted))
+	for i, e := range evicted {
+		keys[i] = e.key
+	}
+	lm.sm.deleteKeys(keys)
+	lm.sm.metrics.recordEvictions(int64(len(keys)))
+	if lm.onEvict != nil {
+		entries := make([]KeyValue[K, V], len(evicted))
+		for i, e := range evicted {
+			entries[i] = KeyValue[K, V]{Key: e.key, Value: e.value}
+		}
+		lm.onEvict(entries, reason)
+	}
+	return len(evicted)
+}
+
+// TrimOldest removes the n oldest entries and returns how many were removed.
+// Removed entries are counted as evictions.
+func (lm *LinkedShrinkableMap[K, V]) TrimOldest(n int) int {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	trimmed := 0
+	return lm.evictLocked(EvictedTrimmed, func(*linkedEntry[K, V]) bool {
+		trimmed++
+		return trimmed <= n
+	})
+}
+
+// TrimToSize removes the oldest entries until at most size remain and returns
+// how many were removed. Removed entries are counted as evictions.
+func (lm *LinkedShrinkableMap[K, V]) TrimToSize(size int) int {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	return lm.trimToSizeLocked(size, EvictedTrimmed)
+}
+
+// trimToSizeLocked must be called with lm.mu held
+func (lm *LinkedShrinkableMap[K, V]) trimToSizeLocked(size int, reason EvictionReason) int {
+	excess := lm.sm.Len() - int64(max(size, 0))
+	return lm.evictLocked(reason, func(*linkedEntry[K, V]) bool {
+		excess--
+		return excess >= 0
+	})
+}
+
+// expired reports whether e is older than its maximum age under EvictAge
+func (lm *LinkedShrinkableMap[K, V]) expired(e *linkedEntry[K, V], now time.Time) bool {
+	return lm.config.Eviction == EvictAge && e.maxAge > 0 && now.Sub(e.inserted) >= e.maxAge
+}
+
+// Get retrieves the value associated with the given key
+func (lm *LinkedShrinkableMap[K, V]) Get(key K) (V, bool) {
+	e, exists := lm.sm.Get(key)
+	if !exists || lm.expired(e, lm.now()) {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Contains reports whether key is present
+func (lm *LinkedShrinkableMap[K, V]) Contains(key K) bool {
+	_, exists := lm.Get(key)
+	return exists
+}
+
+// Delete removes the entry for the given key
+func (lm *LinkedShrinkableMap[K, V]) Delete(key K) bool {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	return lm.deleteLocked(key)
+}
+
+// deleteLocked must be called with lm.mu held
+func (lm *LinkedShrinkableMap[K, V]) deleteLocked(key K) bool {
+	e, exists := lm.lookupLocked(key)
+	if !exists {
+		return false
+	}
+	lm.sm.Delete(key)
+	lm.unlink(e)
+	return true
+}
+
+// Snapshot returns all entries from oldest to newest
+func (lm *LinkedShrinkableMap[K, V]) Snapshot() []KeyValue[K, V] {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	result := make([]KeyValue[K, V], 0, lm.sm.Len())
+	now := lm.now()
+	for e := lm.nextLive(lm.head, now); e != nil; e = lm.nextLive(e.next, now) {
+		result = append(result, KeyValue[K, V]{Key: e.key, Value: e.value})
+	}
+	return result
+}
+
+// Range calls fn for every entry from oldest to newest until fn returns
+// false. The list is read-locked during the call, so fn must not modify the map.
+func (lm *LinkedShrinkableMap[K, V]) Range(fn func(key K, value V) bool) {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	now := lm.now()
+	for e := lm.nextLive(lm.head, now); e != nil; e = lm.nextLive(e.next, now) {
+		if !fn(e.key, e.value) {
+			return
+		}
+	}
+}
+
+// Oldest returns the earliest inserted entry, or false if the map is empty
+func (lm *LinkedShrinkableMap[K, V]) Oldest() (KeyValue[K, V], bool) {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	if e := lm.nextLive(lm.head, lm.now()); e != nil {
+		return KeyValue[K, V]{Key: e.key, Value: e.value}, true
+	}
+	return KeyValue[K, V]{}, false
+}
+
+// Newest returns the most recently inserted entry, or false if the map is empty
+func (lm *LinkedShrinkableMap[K, V]) Newest() (KeyValue[K, V], bool) {
+	lm.mu.RLock()
+	defer lm.mu.RUnlock()
+
+	now := lm.now()
+	for e := lm.tail; e != nil; e = e.prev {
+		if !lm.expired(e, now) {
+			return KeyValue[K, V]{Key: e.key, Value: e.value}, true
+		}
+	}
+	return KeyValue[K, V]{}, false
+}
+
+// Len returns the current number of items in the map
+func (lm *LinkedShrinkableMap[K, V]) Len() int64 {
+	return lm.sm.Len()
+}
+
+// GetMetrics returns a copy of the current metrics
+func (lm *LinkedShrinkableMap[K, V]) GetMetrics() Metrics {
+	return lm.sm.GetMetrics()
+}
+
+// Stats returns size and capacity statistics
+func (lm *LinkedShrinkableMap[K, V]) Stats() Stats {
+	return lm.sm.Stats()
+}
+
+// TryShrink attempts to shrink the map if conditions are met
+func (lm *LinkedShrinkableMap[K, V]) TryShrink() bool {
+	return lm.sm.TryShrink()
+}
+
+// ForceShrink immediately shrinks the map regardless of conditions
+func (lm *LinkedShrinkableMap[K, V]) ForceShrink() bool {
+	return lm.sm.ForceShrink()
+}
+
+// Status reports whether the map is stopped and the state of its auto-shrink
+// and expiration goroutines
+func (lm *LinkedShrinkableMap[K, V]) Status() Status {
+	status := lm.sm.Status()
+	status.SweepLoop = lm.sweepStatus.status()
+	return status
+}
+
+// Stop terminates the auto-shrink and expiration goroutines
+func (lm *LinkedShrinkableMap[K, V]) Stop() {
+	lm.cancel()
+	<-lm.done
+	lm.sm.Stop()
+}
+
+// lookupLocked returns the list node stored for key, including nodes hidden
+// from reads by Config.ValidateOnGet, without recording a read. Must be
+// called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) lookupLocked(key K) (*linkedEntry[K, V], bool) {
+	lm.sm.mu.RLock()
+	defer lm.sm.mu.RUnlock()
+	e, exists := lm.sm.data[key]
+	return e, exists
+}
+
+// nextLive returns the first entry from e onwards not hidden by age
+// eviction. Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) nextLive(e *linkedEntry[K, V], now time.Time) *linkedEntry[K, V] {
+	for e != nil && lm.expired(e, now) {
+		e = e.next
+	}
+	return e
+}
+
+// pushBack appends e as the newest entry. Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) pushBack(e *linkedEntry[K, V]) {
+	e.prev = lm.tail
+	if lm.tail != nil {
+		lm.tail.next = e
+	} else {
+		lm.head = e
+	}
+	lm.tail = e
+}
+
+// replace puts e at the position of old. Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) replace(old, e *linkedEntry[K, V]) {
+	e.prev, e.next = old.prev, old.next
+	if e.prev != nil {
+		e.prev.next = e
+	} else {
+		lm.head = e
+	}
+	if e.next != nil {
+		e.next.prev = e
+	} else {
+		lm.tail = e
+	}
+	if lm.sweepAt == old {
+		lm.sweepAt = e
+	}
+	old.prev, old.next = nil, nil
+}
+
+// unlink removes e from the list. Must be called with lm.mu held.
+func (lm *LinkedShrinkableMap[K, V]) unlink(e *linkedEntry[K, V]) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		lm.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		lm.tail = e.prev
+	}
+	if lm.sweepAt == e {
+		lm.sweepAt = e.next
+	}
+	e.prev, e.next = nil, nil
+}
@@ -0,0 +1,509 @@
+package shrinkmap
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func linkedKeys[K comparable, V any](lm *LinkedShrinkableMap[K, V]) []K {
+	var keys []K
+	for _, kv := range lm.Snapshot() {
+		keys = append(keys, kv.Key)
+	}
+	return keys
+}
+
+func TestLinkedShrinkableMap(t *testing.T) {
+	t.Run("Insertion Order", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig())
+		defer lm.Stop()
+
+		for i, k := range []string{"c", "a", "d", "b"} {
+			lm.Set(k, i)
+		}
+		lm.Set("a", 10) // update keeps position
+		lm.Delete("d")
+		lm.Set("d", 11) // reinsert moves to the end
+
+		if got := strings.Join(linkedKeys(lm), ","); got != "c,a,b,d" {
+			t.Errorf("Expected order c,a,b,d, got %s", got)
+		}
+		if v, _ := lm.Get("a"); v != 10 {
+			t.Errorf("Expected updated value 10, got %d", v)
+		}
+
+		var visited []string
+		lm.Range(func(key string, value int) bool {
+			visited = append(visited, key)
+			return len(visited) < 2
+		})
+		if strings.Join(visited, ",") != "c,a" {
+			t.Errorf("Expected Range to stop after two entries, got %v", visited)
+		}
+	})
+
+	t.Run("Shrink Keeps Order", func(t *testing.T) {
+		config := DefaultConfig()
+		config.AutoShrinkEnabled = false
+		lm := NewLinked[int, int](config)
+		defer lm.Stop()
+
+		for i := 0; i < 100; i++ {
+			lm.Set(i, i)
+		}
+		for i := 0; i < 100; i += 2 {
+			lm.Delete(i)
+		}
+		if !lm.ForceShrink() {
+			t.Fatal("Expected shrink to run")
+		}
+		keys := linkedKeys(lm)
+		if len(keys) != 50 || keys[0] != 1 || keys[49] != 99 || lm.Len() != 50 {
+			t.Errorf("Unexpected contents after shrink: len=%d first=%d last=%d", len(keys), keys[0], keys[49])
+		}
+		metrics := lm.GetMetrics()
+		if metrics.TotalShrinks() != 1 {
+			t.Errorf("Expected one shrink in metrics, got %d", metrics.TotalShrinks())
+		}
+	})
+
+	t.Run("Value Hooks See Values", func(t *testing.T) {
+		config := DefaultConfig().
+			WithTransformOnSet(func(key, value any) any { return strings.ToLower(value.(string)) }).
+			WithValidators(nil, func(value any) error {
+				if value.(string) == "" {
+					return errors.New("empty")
+				}
+				return nil
+			})
+		lm := NewLinked[string, string](config)
+		defer lm.Stop()
+
+		lm.Set("a", "HELLO")
+		if v, _ := lm.Get("a"); v != "hello" {
+			t.Errorf("Expected transformed value, got %q", v)
+		}
+		if err := lm.Set("b", ""); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected ErrValidation, got %v", err)
+		}
+		if lm.Len() != 1 || len(linkedKeys(lm)) != 1 {
+			t.Error("Rejected writes must not be linked")
+		}
+	})
+
+	t.Run("Concurrent Access", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig())
+		defer lm.Stop()
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func(g int) {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					key := g*1000 + i%50
+					lm.Set(key, i)
+					lm.Get(key)
+					if i%3 == 0 {
+						lm.Delete(key)
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		if int64(len(lm.Snapshot())) != lm.Len() {
+			t.Errorf("List and map diverged: list=%d map=%d", len(lm.Snapshot()), lm.Len())
+		}
+	})
+}
+
+func TestLinkedHiddenEntries(t *testing.T) {
+	lm := NewLinked[string, int](DefaultConfig().WithValidateOnGet(func(_, v any) bool { return v.(int) >= 0 }, false))
+	defer lm.Stop()
+
+	lm.Set("a", -1)
+	lm.Set("b", -1)
+	if lm.Contains("a") {
+		t.Fatal("Expected the invalid entry to be hidden from reads")
+	}
+
+	lm.Set("a", 1)
+	if got := linkedKeys(lm); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("Expected a to keep its single node, got %v", got)
+	}
+	if !lm.Delete("b") || lm.Len() != 1 {
+		t.Errorf("Expected the hidden entry to be deleted, len=%d", lm.Len())
+	}
+	lm.Delete("a")
+	if lm.head != nil || lm.tail != nil {
+		t.Error("Expected an empty list after deleting every entry")
+	}
+}
+
+func TestLinkedOldestNewest(t *testing.T) {
+	lm := NewLinked[string, int](DefaultConfig())
+	defer lm.Stop()
+
+	if _, ok := lm.Oldest(); ok {
+		t.Error("Expected no oldest entry in empty map")
+	}
+	if _, ok := lm.Newest(); ok {
+		t.Error("Expected no newest entry in empty map")
+	}
+
+	lm.Set("a", 1)
+	lm.Set("b", 2)
+	lm.Set("c", 3)
+	lm.Set("a", 10)
+
+	if kv, ok := lm.Oldest(); !ok || kv.Key != "a" || kv.Value != 10 {
+		t.Errorf("Expected oldest a=10, got %v", kv)
+	}
+	if kv, ok := lm.Newest(); !ok || kv.Key != "c" {
+		t.Errorf("Expected newest c, got %v", kv)
+	}
+
+	lm.Delete("a")
+	lm.Delete("c")
+	oldest, _ := lm.Oldest()
+	newest, _ := lm.Newest()
+	if oldest.Key != "b" || newest.Key != "b" {
+		t.Errorf("Expected b to be oldest and newest, got %v and %v", oldest, newest)
+	}
+}
+
+func TestLinkedTrim(t *testing.T) {
+	lm := NewLinked[int, int](DefaultConfig())
+	defer lm.Stop()
+	for i := 0; i < 10; i++ {
+		lm.Set(i, i)
+	}
+
+	if n := lm.TrimOldest(3); n != 3 {
+		t.Errorf("Expected 3 trimmed entries, got %d", n)
+	}
+	if oldest, _ := lm.Oldest(); oldest.Key != 3 {
+		t.Errorf("Expected oldest key 3, got %d", oldest.Key)
+	}
+	if n := lm.TrimToSize(4); n != 3 || lm.Len() != 4 {
+		t.Errorf("Expected 3 trimmed entries and length 4, got %d, %d", n, lm.Len())
+	}
+	if n := lm.TrimToSize(10); n != 0 {
+		t.Errorf("Expected nothing to trim, got %d", n)
+	}
+	if n := lm.TrimOldest(100); n != 4 || lm.Len() != 0 || len(lm.Snapshot()) != 0 {
+		t.Errorf("Expected all entries to be trimmed, got %d", n)
+	}
+
+	metrics := lm.GetMetrics()
+	if metrics.Evictions() != 10 {
+		t.Errorf("Expected 10 evictions, got %d", metrics.Evictions())
+	}
+}
+
+func TestLinkedEviction(t *testing.T) {
+	t.Run("FIFO", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig().WithFIFOEviction(3))
+		defer lm.Stop()
+
+		for i := 0; i < 5; i++ {
+			lm.Set(i, i)
+		}
+		lm.Set(2, 20) // update does not refresh position
+
+		if got := linkedKeys(lm); len(got) != 3 || got[0] != 2 || got[2] != 4 {
+			t.Errorf("Expected keys 2,3,4, got %v", got)
+		}
+		metrics := lm.GetMetrics()
+		if metrics.Evictions() != 2 {
+			t.Errorf("Expected 2 evictions, got %d", metrics.Evictions())
+		}
+	})
+
+	t.Run("Age", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig().WithAgeEviction(time.Minute))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+
+		lm.Set("old", 1)
+		now = now.Add(30 * time.Second)
+		lm.Set("new", 2)
+		lm.Set("old", 3) // update keeps the original insertion time
+		now = now.Add(45 * time.Second)
+
+		if lm.Contains("old") || len(lm.Snapshot()) != 1 {
+			t.Error("Expected old entry to be hidden once expired")
+		}
+		if n := lm.EvictExpired(); n != 1 || lm.Len() != 1 {
+			t.Errorf("Expected one eviction, got %d, len=%d", n, lm.Len())
+		}
+		if v, ok := lm.Get("new"); !ok || v != 2 {
+			t.Errorf("Expected new entry to remain, got %d, %v", v, ok)
+		}
+	})
+
+	t.Run("AgeRules", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig().WithAgeEviction(time.Hour).WithAgeRules(
+			AgeRule{Prefix: "session:", MaxAge: 30 * time.Minute},
+			AgeRule{Pattern: "config:*"},
+		))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+
+		lm.Set("config:mode", 1)
+		lm.Set("user:1", 2)
+		lm.Set("session:a", 3)
+		now = now.Add(45 * time.Minute)
+
+		if lm.Contains("session:a") || !lm.Contains("user:1") {
+			t.Error("Expected only the session entry to be expired")
+		}
+		if newest, ok := lm.Newest(); !ok || newest.Key != "user:1" {
+			t.Errorf("Expected user:1 as newest live entry, got %v, %v", newest, ok)
+		}
+		if n := lm.EvictExpired(); n != 1 || lm.Len() != 2 {
+			t.Errorf("Expected the session entry to be evicted, got %d, len=%d", n, lm.Len())
+		}
+
+		now = now.Add(24 * time.Hour)
+		if got := linkedKeys(lm); len(got) != 1 || got[0] != "config:mode" {
+			t.Errorf("Expected config entry never to expire, got %v", got)
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := DefaultConfig().WithAgeRules(AgeRule{Pattern: "a*", MaxAge: time.Second}).Validate(); err == nil {
+			t.Error("Expected error for age rules without age eviction")
+		}
+		if err := DefaultConfig().WithAgeEviction(time.Minute).WithAgeRules(AgeRule{Pattern: "["}).Validate(); err == nil {
+			t.Error("Expected error for invalid age rule pattern")
+		}
+		if err := DefaultConfig().WithAgeEviction(time.Minute).WithAgeRules(AgeRule{MaxAge: time.Second}).Validate(); err == nil {
+			t.Error("Expected error for age rule without prefix or pattern")
+		}
+		if err := DefaultConfig().WithAgeEviction(time.Minute).WithAgeRules(AgeRule{Prefix: "a", Pattern: "a*"}).Validate(); err == nil {
+			t.Error("Expected error for age rule with both prefix and pattern")
+		}
+		if err := DefaultConfig().WithFIFOEviction(0).Validate(); err == nil {
+			t.Error("Expected error for FIFO eviction without max entries")
+		}
+		if err := DefaultConfig().WithAgeEviction(0).Validate(); err == nil {
+			t.Error("Expected error for age eviction without max age")
+		}
+	})
+
+	t.Run("AgeRulesNonStringKeys", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig().WithAgeEviction(time.Hour).WithAgeRules(
+			AgeRule{Prefix: "1", MaxAge: time.Minute},
+		))
+		defer lm.Stop()
+
+		metrics := lm.GetMetrics()
+		if metrics.TotalErrors() != 1 {
+			t.Errorf("Expected an error for age rules on int keys, got %d", metrics.TotalErrors())
+		}
+		if lm.maxAge(1) != time.Hour {
+			t.Errorf("Expected rules to be ignored, got max age %v", lm.maxAge(1))
+		}
+	})
+
+	t.Run("BackgroundSweep", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig().WithAgeEviction(time.Hour).WithAgeRules(
+			AgeRule{Prefix: "session:", MaxAge: 10 * time.Millisecond},
+		))
+		if !lm.Status().SweepLoop.Running {
+			t.Error("Expected the expiration goroutine to run under age eviction")
+		}
+
+		lm.Set("session:a", 1)
+		lm.Set("user:1", 2)
+		deadline := time.Now().Add(time.Second)
+		for lm.Len() != 1 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if lm.Len() != 1 || !lm.Contains("user:1") {
+			t.Errorf("Expected the idle map to evict the expired session, len=%d", lm.Len())
+		}
+
+		lm.Stop()
+		if status := lm.Status(); status.SweepLoop.Running || status.SweepLoop.LastActivity.IsZero() {
+			t.Errorf("Expected a stopped loop that evicted entries, got %+v", status.SweepLoop)
+		}
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		config := DefaultConfig().WithFIFOEviction(3)
+
+		sm := New[int, int](config)
+		defer sm.Stop()
+		metrics := sm.GetMetrics()
+		if metrics.TotalErrors() != 1 {
+			t.Errorf("Expected New to record an error for eviction settings, got %d", metrics.TotalErrors())
+		}
+
+		g := NewGroup()
+		defer g.Stop()
+		if _, err := NewInGroup[int, int](g, "evicting", config); err == nil {
+			t.Error("Expected NewInGroup to reject eviction settings")
+		}
+
+		lm := NewLinked[int, int](config)
+		defer lm.Stop()
+		metrics = lm.GetMetrics()
+		if metrics.TotalErrors() != 0 {
+			t.Errorf("Expected no errors from NewLinked, got %d", metrics.TotalErrors())
+		}
+	})
+}
+
+func TestLinkedEvictBatch(t *testing.T) {
+	type batch struct {
+		keys   []int
+		reason EvictionReason
+	}
+	record := func(lm *LinkedShrinkableMap[int, int]) *[]batch {
+		var batches []batch
+		lm.OnEvictBatch(func(entries []KeyValue[int, int], reason EvictionReason) {
+			b := batch{reason: reason}
+			for _, kv := range entries {
+				b.keys = append(b.keys, kv.Key)
+			}
+			batches = append(batches, b)
+		})
+		return &batches
+	}
+
+	t.Run("Expired", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig().WithAgeEviction(time.Minute))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+		batches := record(lm)
+
+		for i := 0; i < 100; i++ {
+			lm.Set(i, i)
+		}
+		now = now.Add(2 * time.Minute)
+		lm.Set(100, 100)
+
+		if len(*batches) != 1 || len((*batches)[0].keys) != 100 || (*batches)[0].reason != EvictedExpired {
+			t.Fatalf("Expected one batch of 100 expired entries, got %d batches", len(*batches))
+		}
+		if keys := (*batches)[0].keys; keys[0] != 0 || keys[99] != 99 {
+			t.Errorf("Expected entries oldest first, got %v", keys)
+		}
+	})
+
+	t.Run("Reasons", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig().WithFIFOEviction(3))
+		defer lm.Stop()
+		batches := record(lm)
+
+		for i := 0; i < 4; i++ {
+			lm.Set(i, i)
+		}
+		lm.TrimOldest(2)
+
+		want := []batch{{keys: []int{0}, reason: EvictedOverCapacity}, {keys: []int{1, 2}, reason: EvictedTrimmed}}
+		if len(*batches) != len(want) {
+			t.Fatalf("Expected %d batches, got %v", len(want), *batches)
+		}
+		for i, b := range *batches {
+			if b.reason != want[i].reason || len(b.keys) != len(want[i].keys) || b.keys[0] != want[i].keys[0] {
+				t.Errorf("Batch %d: expected %v, got %v", i, want[i], b)
+			}
+		}
+
+		lm.OnEvictBatch(nil)
+		lm.TrimToSize(0)
+		if len(*batches) != len(want) {
+			t.Error("Expected no calls after removing the callback")
+		}
+	})
+}
+
+func TestLinkedSweepBudget(t *testing.T) {
+	t.Run("Oldest", func(t *testing.T) {
+		lm := NewLinked[int, int](DefaultConfig().WithAgeEviction(time.Minute).WithSweepBudget(10, 0))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+
+		for i := 0; i < 25; i++ {
+			lm.Set(i, i)
+		}
+		now = now.Add(2 * time.Minute)
+
+		lm.Set(100, 100) // sweeps on write within the budget
+		if lm.Len() != 16 || len(lm.Snapshot()) != 1 {
+			t.Errorf("Expected 10 entries swept and the rest hidden, got len=%d", lm.Len())
+		}
+		if n := lm.EvictExpired(); n != 10 {
+			t.Errorf("Expected 10 evictions, got %d", n)
+		}
+		if n := lm.EvictExpired(); n != 5 || lm.Len() != 1 {
+			t.Errorf("Expected remaining 5 evictions, got %d, len=%d", n, lm.Len())
+		}
+	})
+
+	t.Run("RewriteExpired", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig().WithAgeEviction(time.Minute).WithSweepBudget(1, 0))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+
+		lm.Set("a", 1)
+		lm.Set("b", 1)
+		lm.Set("c", 1)
+		now = now.Add(2 * time.Minute)
+
+		// The sweep only evicts a, leaving the expired b in the list
+		lm.Set("b", 2)
+		if v, ok := lm.Get("b"); !ok || v != 2 {
+			t.Errorf("Expected the rewritten entry to be live, got %d, %v", v, ok)
+		}
+		if newest, _ := lm.Newest(); newest.Key != "b" {
+			t.Errorf("Expected the rewritten entry to be the newest, got %v", newest.Key)
+		}
+		if got := linkedKeys(lm); len(got) != 1 || got[0] != "b" {
+			t.Errorf("Expected only b to be live, got %v", got)
+		}
+	})
+
+	t.Run("AgeRulesResume", func(t *testing.T) {
+		lm := NewLinked[string, int](DefaultConfig().WithAgeEviction(time.Minute).
+			WithAgeRules(AgeRule{Pattern: "keep:*"}).WithSweepBudget(2, 0))
+		defer lm.Stop()
+		now := time.Now()
+		lm.now = func() time.Time { return now }
+
+		for _, key := range []string{"keep:a", "x1", "keep:b", "x2", "x3", "keep:c", "x4"} {
+			lm.Set(key, 0)
+		}
+		now = now.Add(2 * time.Minute)
+
+		total := 0
+		for i := 0; i < 3; i++ {
+			n := lm.EvictExpired()
+			if n > 2 {
+				t.Errorf("Sweep %d exceeded the budget: %d", i, n)
+			}
+			total += n
+		}
+		if total != 4 || lm.Len() != 3 {
+			t.Errorf("Expected all 4 expired entries evicted, got %d, len=%d", total, lm.Len())
+		}
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		if err := DefaultConfig().WithSweepBudget(-1, 0).Validate(); err == nil {
+			t.Error("Expected error for negative sweep budget")
+		}
+	})
+}
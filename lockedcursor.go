@@ -0,0 +1,113 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrCursorTimeout is returned by LockedCursor.Err when the cursor released
+// the read lock because its timeout expired before it was closed
+var ErrCursorTimeout = errors.New("shrinkmap: locked cursor timed out")
+
+// LockedCursor iterates over the live map while holding its read lock, so
+// the entries are fully consistent without copying a snapshot. Writers are
+// blocked until Close is called or the timeout expires, whichever is first.
+//
+// The map must not be used by the goroutine reading the cursor until it is
+// closed: a write would deadlock, and so can a read once a writer is waiting.
+type LockedCursor[K comparable, V any] struct {
+	entries  chan KeyValue[K, V]
+	done     chan struct{}
+	finished chan struct{}
+	once     sync.Once
+	timedOut bool // written before finished is closed
+
+	current KeyValue[K, V]
+	valid   bool
+}
+
+// NewLockedCursor acquires the read lock and returns a cursor over the map.
+// The lock is released by Close, at the end of the iteration, or after
+// timeout as a safety net against cursors that are never closed; timeout 0
+// disables the safety net.
+func (sm *ShrinkableMap[K, V]) NewLockedCursor(timeout time.Duration) *LockedCursor[K, V] {
+	c := &LockedCursor[K, V]{
+		entries:  make(chan KeyValue[K, V]),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+	locked := make(chan struct{})
+
+	// Go map iteration cannot be suspended, so the range loop runs in its own
+	// goroutine holding the lock and hands out one entry per Next
+	go func() {
+		defer close(c.finished)
+		defer close(c.entries)
+		sm.mu.RLock()
+		defer sm.mu.RUnlock()
+		close(locked)
+
+		var expired <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			expired = timer.C
+		}
+		for k, v := range sm.data {
+			select {
+			case c.entries <- KeyValue[K, V]{Key: k, Value: sm.copyOnRead(v)}:
+			case <-c.done:
+				return
+			case <-expired:
+				c.timedOut = true
+				return
+			}
+		}
+	}()
+
+	<-locked
+	return c
+}
+
+// Next advances to the next entry and reports whether there is one
+func (c *LockedCursor[K, V]) Next() bool {
+	c.current, c.valid = <-c.entries
+	return c.valid
+}
+
+// Key returns the key of the current entry
+func (c *LockedCursor[K, V]) Key() K {
+	return c.current.Key
+}
+
+// Value returns the value of the current entry
+func (c *LockedCursor[K, V]) Value() V {
+	return c.current.Value
+}
+
+// Entry returns the current entry and whether there is one
+func (c *LockedCursor[K, V]) Entry() (KeyValue[K, V], bool) {
+	return c.current, c.valid
+}
+
+// Close ends the iteration and releases the read lock before returning.
+// Calling it more than once, or after the iteration ended, is a no-op.
+func (c *LockedCursor[K, V]) Close() {
+	c.once.Do(func() { close(c.done) })
+	<-c.finished
+	c.current, c.valid = KeyValue[K, V]{}, false
+}
+
+// Err returns ErrCursorTimeout if the iteration was cut short by the
+// timeout. It is only meaningful once Next has returned false.
+func (c *LockedCursor[K, V]) Err() error {
+	select {
+	case <-c.finished:
+		if c.timedOut {
+			return ErrCursorTimeout
+		}
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLockedCursor(t *testing.T) {
+	newMap := func(t *testing.T) *ShrinkableMap[int, int] {
+		sm := New[int, int](DefaultConfig())
+		t.Cleanup(sm.Stop)
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		return sm
+	}
+
+	t.Run("Consistent Iteration", func(t *testing.T) {
+		sm := newMap(t)
+		c := sm.NewLockedCursor(0)
+		defer c.Close()
+
+		written := make(chan struct{})
+		go func() {
+			sm.Set(1000, 1000)
+			close(written)
+		}()
+
+		sum := 0
+		for c.Next() {
+			if c.Key() != c.Value() {
+				t.Errorf("Unexpected entry %d=%d", c.Key(), c.Value())
+			}
+			sum += c.Value()
+		}
+		if sum != 4950 || c.Err() != nil {
+			t.Errorf("Expected sum 4950 without the concurrent write, got %d (%v)", sum, c.Err())
+		}
+
+		// The lock is released once the iteration ends
+		select {
+		case <-written:
+		case <-time.After(time.Second):
+			t.Fatal("Expected the writer to proceed after the iteration")
+		}
+	})
+
+	t.Run("Close Releases Lock", func(t *testing.T) {
+		sm := newMap(t)
+		c := sm.NewLockedCursor(0)
+		c.Next()
+		c.Close()
+		c.Close()
+
+		if _, ok := c.Entry(); ok || c.Next() {
+			t.Error("Expected the cursor to be exhausted after Close")
+		}
+		if err := sm.SetChecked(1000, 1000); err != nil {
+			t.Errorf("Expected write after Close, got %v", err)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		sm := newMap(t)
+		c := sm.NewLockedCursor(10 * time.Millisecond)
+		defer c.Close()
+		c.Next()
+
+		// A writer blocked by the cursor gets through once it times out
+		if err := sm.SetChecked(1000, 1000); err != nil {
+			t.Fatal(err)
+		}
+		for c.Next() {
+		}
+		if !errors.Is(c.Err(), ErrCursorTimeout) {
+			t.Errorf("Expected ErrCursorTimeout, got %v", c.Err())
+		}
+	})
+}
@@ -0,0 +1,201 @@
+package shrinkmap
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Shrinker is implemented by every *ShrinkableMap regardless of its key and
+// value types, so maps of different types can be registered with one
+// MemoryCoordinator.
+type Shrinker interface {
+	ForceShrink() bool
+	SetMemoryPressure(active bool)
+	deletedRatio() float64
+}
+
+// deletedRatio returns the fraction of tracked items that have been deleted
+// since the last shrink
+func (sm *ShrinkableMap[K, V]) deletedRatio() float64 {
+	itemCount := sm.itemCount.Load()
+	if itemCount == 0 {
+		return 0
+	}
+	return float64(sm.deletedCount.Load()) / float64(itemCount)
+}
+
+// MemoryCoordinatorConfig defines when a MemoryCoordinator shrinks maps
+type MemoryCoordinatorConfig struct {
+	// How often memory usage is compared against the limit
+	CheckInterval time.Duration
+
+	// Fraction of the Go memory limit (0.0 to 1.0) at which maps are shrunk
+	PressureThreshold float64
+
+	// Maps with a lower deleted ratio are not shrunk (0 shrinks any map with deletions)
+	MinDeletedRatio float64
+}
+
+// DefaultMemoryCoordinatorConfig returns the default coordinator configuration
+func DefaultMemoryCoordinatorConfig() MemoryCoordinatorConfig {
+	return MemoryCoordinatorConfig{
+		// Check memory usage every 5 seconds
+		CheckInterval: 5 * time.Second,
+
+		// Start shrinking at 85% of the memory limit
+		PressureThreshold: 0.85,
+
+		// Shrink any map that has deletions
+		MinDeletedRatio: 0,
+	}
+}
+
+// Validate checks if the coordinator configuration is valid
+func (c MemoryCoordinatorConfig) Validate() error {
+	if c.CheckInterval <= 0 {
+		return fmt.Errorf("check interval must be positive")
+	}
+	if c.PressureThreshold <= 0 || c.PressureThreshold > 1 {
+		return fmt.Errorf("pressure threshold must be between 0 and 1")
+	}
+	if c.MinDeletedRatio < 0 || c.MinDeletedRatio >= 1 {
+		return fmt.Errorf("minimum deleted ratio must be between 0 and 1")
+	}
+	return nil
+}
+
+// MemoryCoordinator watches process memory usage relative to the Go memory
+// limit set with debug.SetMemoryLimit or GOMEMLIMIT. When usage reaches the
+// pressure threshold it force-shrinks the registered maps, the map with the
+// largest deleted ratio first, and signals memory pressure to them until usage
+// drops below the threshold again. Without a memory limit it does nothing.
+type MemoryCoordinator struct {
+	config MemoryCoordinatorConfig
+
+	mu   sync.Mutex
+	maps map[Shrinker]struct{}
+
+	// readMemory returns the memory in use and the memory limit in bytes
+	readMemory func() (used, limit uint64)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewMemoryCoordinator creates a coordinator and starts its check loop
+func NewMemoryCoordinator(config MemoryCoordinatorConfig) (*MemoryCoordinator, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &MemoryCoordinator{
+		config:     config,
+		maps:       make(map[Shrinker]struct{}),
+		readMemory: readRuntimeMemory,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go c.run(ctx)
+	return c, nil
+}
+
+// Register adds a map to the coordinator.
+// The returned function removes it again; stopped maps should be unregistered.
+func (c *MemoryCoordinator) Register(m Shrinker) func() {
+	c.mu.Lock()
+	c.maps[m] = struct{}{}
+	c.mu.Unlock()
+
+	return func() {
+		c.mu.Lock()
+		delete(c.maps, m)
+		c.mu.Unlock()
+		m.SetMemoryPressure(false)
+	}
+}
+
+// Check compares memory usage with the limit once, updates the memory
+// pressure signal of the registered maps and, under pressure, shrinks the
+// eligible maps. Returns the number of maps shrunk.
+func (c *MemoryCoordinator) Check() int {
+	used, limit := c.readMemory()
+	pressure := limit != 0 && limit != math.MaxInt64 &&
+		float64(used) >= float64(limit)*c.config.PressureThreshold
+
+	type candidate struct {
+		m     Shrinker
+		ratio float64
+	}
+	c.mu.Lock()
+	candidates := make([]candidate, 0, len(c.maps))
+	for m := range c.maps {
+		m.SetMemoryPressure(pressure)
+		if !pressure {
+			continue
+		}
+		if ratio := m.deletedRatio(); ratio > 0 && ratio >= c.config.MinDeletedRatio {
+			candidates = append(candidates, candidate{m: m, ratio: ratio})
+		}
+	}
+	c.mu.Unlock()
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ratio > candidates[j].ratio
+	})
+
+	shrunk := 0
+	for _, cand := range candidates {
+		if cand.m.ForceShrink() {
+			shrunk++
+		}
+	}
+	return shrunk
+}
+
+// Stop terminates the check loop
+func (c *MemoryCoordinator) Stop() {
+	c.cancel()
+	<-c.done
+}
+
+func (c *MemoryCoordinator) run(ctx context.Context) {
+	defer close(c.done)
+	ticker := time.NewTicker(c.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check()
+		}
+	}
+}
+
+// readRuntimeMemory reports memory counted against the Go memory limit:
+// everything mapped by the runtime minus heap memory returned to the OS
+func readRuntimeMemory() (used, limit uint64) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+
+	for _, s := range samples {
+		if s.Value.Kind() != metrics.KindUint64 {
+			return 0, 0
+		}
+	}
+	used = samples[0].Value.Uint64() - samples[1].Value.Uint64()
+
+	// A negative input reads the limit without changing it
+	return used, uint64(debug.SetMemoryLimit(-1))
+}
@@ -0,0 +1,94 @@
+package shrinkmap
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMemoryCoordinator(t *testing.T) {
+	newCoordinator := func(t *testing.T, used, limit uint64) *MemoryCoordinator {
+		config := DefaultMemoryCoordinatorConfig()
+		config.CheckInterval = time.Hour
+		c, err := NewMemoryCoordinator(config)
+		if err != nil {
+			t.Fatalf("NewMemoryCoordinator failed: %v", err)
+		}
+		t.Cleanup(c.Stop)
+		c.readMemory = func() (uint64, uint64) { return used, limit }
+		return c
+	}
+
+	newMapWithDeletes := func(t *testing.T, items, deletes int) *ShrinkableMap[int, int] {
+		sm := New[int, int](DefaultConfig().WithAutoShrinkEnabled(false))
+		t.Cleanup(sm.Stop)
+		for i := 0; i < items; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < deletes; i++ {
+			sm.Delete(i)
+		}
+		return sm
+	}
+
+	t.Run("Shrinks Under Pressure", func(t *testing.T) {
+		c := newCoordinator(t, 90, 100)
+		mostly := newMapWithDeletes(t, 10, 8)
+		some := newMapWithDeletes(t, 10, 2)
+		clean := newMapWithDeletes(t, 10, 0)
+		c.Register(mostly)
+		c.Register(some)
+		c.Register(clean)
+
+		if shrunk := c.Check(); shrunk != 2 {
+			t.Errorf("Expected 2 maps shrunk, got %d", shrunk)
+		}
+		metrics := clean.GetMetrics()
+		if metrics.TotalShrinks() != 0 {
+			t.Error("Map without deletions should not be shrunk")
+		}
+		if mostly.deletedRatio() != 0 || some.deletedRatio() != 0 {
+			t.Error("Expected deleted counts to be reset by the shrink")
+		}
+	})
+
+	t.Run("No Pressure", func(t *testing.T) {
+		c := newCoordinator(t, 10, 100)
+		c.Register(newMapWithDeletes(t, 10, 5))
+		if shrunk := c.Check(); shrunk != 0 {
+			t.Errorf("Expected no shrink below the threshold, got %d", shrunk)
+		}
+	})
+
+	t.Run("No Memory Limit", func(t *testing.T) {
+		c := newCoordinator(t, 1<<40, math.MaxInt64)
+		c.Register(newMapWithDeletes(t, 10, 5))
+		if shrunk := c.Check(); shrunk != 0 {
+			t.Errorf("Expected no shrink without a memory limit, got %d", shrunk)
+		}
+	})
+
+	t.Run("Unregister", func(t *testing.T) {
+		c := newCoordinator(t, 90, 100)
+		unregister := c.Register(newMapWithDeletes(t, 10, 5))
+		unregister()
+		if shrunk := c.Check(); shrunk != 0 {
+			t.Errorf("Expected unregistered map to be ignored, got %d", shrunk)
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		config := DefaultMemoryCoordinatorConfig()
+		config.PressureThreshold = 1.5
+		if _, err := NewMemoryCoordinator(config); err == nil {
+			t.Error("Expected error for invalid pressure threshold")
+		}
+	})
+
+	t.Run("Runtime Memory", func(t *testing.T) {
+		used, limit := readRuntimeMemory()
+		if used == 0 || limit == 0 {
+			t.Errorf("Expected runtime memory readings, got used=%d limit=%d", used, limit)
+		}
+	})
+}
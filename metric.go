@@ -1,6 +1,7 @@
 package shrinkmap
 
 import (
+	"maps"
 	"sync"
 	"time"
 )
@@ -17,6 +18,7 @@ type Metrics struct {
 	mu                  sync.RWMutex
 	totalShrinks        int64
 	lastShrinkDuration  time.Duration
+	lastShrinkItems     int64
 	totalItemsProcessed int64
 	peakSize            int32
 
@@ -25,6 +27,77 @@ type Metrics struct {
 	lastError     *ErrorRecord
 	errorHistory  []ErrorRecord
 	totalErrors   int64
+	errorsByCode  map[ErrorCode]int64
+
+	// Times of the most recent errors and panics for HealthConfig.ErrorThreshold,
+	// which may exceed the error history; not copied by GetMetrics
+	errorTimes errorTimes
+
+	oversizedValues int64
+	invalidReads    int64
+	evictions       int64
+
+	skippedForGrowth int64
+
+	alerts *alerter
+
+	// Forwards recorded errors to Config.MetricsObserver; not copied by GetMetrics
+	onError func(ErrorEvent)
+
+	// Delivers alerts and observed errors outside the recording goroutine
+	notifier notifier
+}
+
+// notifier runs callbacks in order on a separate goroutine. Errors are often
+// recorded while the map's lock is held, so running callbacks there would
+// deadlock any callback that reads the map. The goroutine exits when the
+// queue is empty and is started again by the next post.
+type notifier struct {
+	mu      sync.Mutex
+	idle    sync.Cond
+	queue   []func()
+	running bool
+}
+
+// post queues fn to run after every callback posted before it
+func (n *notifier) post(fn func()) {
+	n.mu.Lock()
+	n.queue = append(n.queue, fn)
+	if !n.running {
+		n.running = true
+		go n.run()
+	}
+	n.mu.Unlock()
+}
+
+func (n *notifier) run() {
+	n.mu.Lock()
+	for len(n.queue) > 0 {
+		fn := n.queue[0]
+		n.queue[0] = nil
+		n.queue = n.queue[1:]
+		n.mu.Unlock()
+		fn()
+		n.mu.Lock()
+	}
+	n.queue = nil
+	n.running = false
+	if n.idle.L != nil {
+		n.idle.Broadcast()
+	}
+	n.mu.Unlock()
+}
+
+// wait blocks until every posted callback has run
+func (n *notifier) wait() {
+	n.mu.Lock()
+	if n.idle.L == nil {
+		n.idle.L = &n.mu
+	}
+	for n.running {
+		n.idle.Wait()
+	}
+	n.mu.Unlock()
 }
 
 func (m *Metrics) TotalShrinks() int64 {
@@ -39,6 +112,13 @@ func (m *Metrics) LastShrinkDuration() time.Duration {
 	return m.lastShrinkDuration
 }
 
+// LastShrinkItems returns the number of entries copied by the last shrink
+func (m *Metrics) LastShrinkItems() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.lastShrinkItems
+}
+
 func (m *Metrics) TotalItemsProcessed() int64 {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -52,6 +132,13 @@ func (m *Metrics) PeakSize() int32 {
 }
 
 func (m *Metrics) RecordError(err error, stack string) {
+	code := ErrorCodeOf(err)
+	m.recordError(err, stack)
+	m.alerts.record(AlertError, code, err, &m.notifier)
+	m.observeError(code, err)
+}
+
+func (m *Metrics) recordError(err error, stack string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -63,6 +150,8 @@ func (m *Metrics) RecordError(err error, stack string) {
 
 	m.lastError = &record
 	m.totalErrors++
+	m.countErrorLocked(ErrorCodeOf(err))
+	m.errorTimes.add(record.Timestamp)
 
 	if len(m.errorHistory) >= 10 {
 		m.errorHistory = m.errorHistory[1:]
@@ -71,6 +160,12 @@ func (m *Metrics) RecordError(err error, stack string) {
 }
 
 func (m *Metrics) RecordPanic(r interface{}, stack string) {
+	m.recordPanic(r, stack)
+	m.alerts.record(AlertPanic, ErrCodePanic, r, &m.notifier)
+	m.observeError(ErrCodePanic, r)
+}
+
+func (m *Metrics) recordPanic(r interface{}, stack string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -83,6 +178,8 @@ func (m *Metrics) RecordPanic(r interface{}, stack string) {
 	m.lastError = &record
 	m.shrinkPanics++
 	m.lastPanicTime = time.Now()
+	m.countErrorLocked(ErrCodePanic)
+	m.errorTimes.add(record.Timestamp)
 
 	if len(m.errorHistory) >= 10 {
 		m.errorHistory = m.errorHistory[1:]
@@ -126,6 +223,143 @@ func (m *Metrics) TotalErrors() int64 {
 	return m.totalErrors
 }
 
+// ErrorCount returns the number of recorded errors classified as code.
+// Panics are counted under ErrCodePanic.
+func (m *Metrics) ErrorCount(code ErrorCode) int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.errorsByCode[code]
+}
+
+// ErrorsByCode returns the recorded error counts by code, omitting codes
+// that were never recorded
+func (m *Metrics) ErrorsByCode() map[ErrorCode]int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return maps.Clone(m.errorsByCode)
+}
+
+// errorsSince returns the number of errors and panics added to the error
+// history after cutoff, counting at most the last HealthConfig.ErrorThreshold
+func (m *Metrics) errorsSince(cutoff time.Time) int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.errorTimes.since(cutoff)
+}
+
+// errorTimes is a ring of the times of the most recent errors
+type errorTimes struct {
+	times []time.Time
+	next  int
+}
+
+func newErrorTimes(size int) errorTimes {
+	return errorTimes{times: make([]time.Time, 0, max(size, 0))}
+}
+
+func (r *errorTimes) add(t time.Time) {
+	switch {
+	case cap(r.times) == 0:
+	case len(r.times) < cap(r.times):
+		r.times = append(r.times, t)
+	default:
+		r.times[r.next] = t
+		r.next = (r.next + 1) % len(r.times)
+	}
+}
+
+func (r *errorTimes) since(cutoff time.Time) int {
+	n := 0
+	for _, t := range r.times {
+		if t.After(cutoff) {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *errorTimes) reset() {
+	r.times = r.times[:0]
+	r.next = 0
+}
+
+// countError counts err without adding it to the error history
+func (m *Metrics) countError(err error) {
+	m.mu.Lock()
+	m.totalErrors++
+	m.countErrorLocked(ErrorCodeOf(err))
+	m.mu.Unlock()
+	m.observeError(ErrorCodeOf(err), err)
+}
+
+// observeError passes a recorded error or panic to the metrics observer
+func (m *Metrics) observeError(code ErrorCode, err interface{}) {
+	if m.onError != nil {
+		event := ErrorEvent{Time: time.Now(), Code: code, Error: err}
+		m.notifier.post(func() { m.onError(event) })
+	}
+}
+
+func (m *Metrics) countErrorLocked(code ErrorCode) {
+	if m.errorsByCode == nil {
+		m.errorsByCode = make(map[ErrorCode]int64)
+	}
+	m.errorsByCode[code]++
+}
+
+// OversizedValues returns the number of writes rejected with ErrValueTooLarge
+func (m *Metrics) OversizedValues() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.oversizedValues
+}
+
+func (m *Metrics) recordOversizedValue() {
+	m.mu.Lock()
+	m.oversizedValues++
+	m.mu.Unlock()
+}
+
+// InvalidReads returns the number of reads that failed Config.ValidateOnGet
+func (m *Metrics) InvalidReads() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.invalidReads
+}
+
+func (m *Metrics) recordInvalidRead() {
+	m.mu.Lock()
+	m.invalidReads++
+	m.mu.Unlock()
+}
+
+// Evictions returns the number of entries removed by the eviction policy or trimming
+func (m *Metrics) Evictions() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.evictions
+}
+
+func (m *Metrics) recordEvictions(n int64) {
+	m.mu.Lock()
+	m.evictions += n
+	m.mu.Unlock()
+}
+
+// ShrinksSkippedForGrowth returns the number of shrink checks skipped because
+// the map was growing back within Config.GrowthHysteresis
+func (m *Metrics) ShrinksSkippedForGrowth() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.skippedForGrowth
+}
+
+func (m *Metrics) recordSkippedForGrowth() {
+	m.mu.Lock()
+	m.skippedForGrowth++
+	m.mu.Unlock()
+}
+
 // Reset resets all metrics
 func (m *Metrics) Reset() {
 	m.mu.Lock()
@@ -133,6 +367,7 @@ func (m *Metrics) Reset() {
 
 	m.totalShrinks = 0
 	m.lastShrinkDuration = 0
+	m.lastShrinkItems = 0
 	m.totalItemsProcessed = 0
 	m.peakSize = 0
 	m.shrinkPanics = 0
@@ -140,4 +375,10 @@ func (m *Metrics) Reset() {
 	m.lastError = nil
 	m.errorHistory = nil
 	m.totalErrors = 0
+	m.errorsByCode = nil
+	m.errorTimes.reset()
+	m.oversizedValues = 0
+	m.invalidReads = 0
+	m.evictions = 0
+	m.skippedForGrowth = 0
 }
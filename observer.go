@@ -0,0 +1,91 @@
+package shrinkmap
+
+import "time"
+
+// MetricsObserver receives metrics as they are produced, so they can be
+// forwarded to StatsD, Datadog or similar systems without polling GetMetrics.
+// ObserveShrink and ObserveOp are called synchronously from the operation
+// being observed, possibly from many goroutines at once; methods must be safe
+// for concurrent use and should return quickly.
+type MetricsObserver interface {
+	// ObserveShrink is called after every completed shrink
+	ObserveShrink(ShrinkEvent)
+
+	// ObserveOp is called after every set, get, delete, batch and shrink
+	ObserveOp(OpEvent)
+
+	// ObserveError is called for every error or panic recorded in Metrics.
+	// Errors are often recorded while the map is locked, so unlike the other
+	// methods it is called in order on a separate goroutine, and may read the map.
+	ObserveError(ErrorEvent)
+}
+
+// ShrinkEvent describes a completed shrink
+type ShrinkEvent struct {
+	// Name of the map as set by Config.Name
+	Map string
+
+	Time     time.Time
+	Duration time.Duration
+
+	// Whether the shrink was forced rather than triggered by the map's conditions
+	Forced bool
+
+	// Entries copied into the new map and deleted entries it reclaimed
+	ItemsCopied int64
+	Reclaimed   int64
+
+	// Number of entries the new map was allocated for
+	Capacity int
+}
+
+// OpEvent describes a single map operation
+type OpEvent struct {
+	// Name of the map as set by Config.Name
+	Map string
+
+	// Operation name: "set", "get", "delete", "batch" or "shrink"
+	Op       string
+	Duration time.Duration
+}
+
+// ErrorEvent describes an error or panic recorded in Metrics
+type ErrorEvent struct {
+	// Name of the map as set by Config.Name
+	Map string
+
+	Time time.Time
+	Code ErrorCode
+
+	// The error, or the recovered value for panics
+	Error interface{}
+}
+
+// observeErrors forwards errors recorded in the map's metrics to the
+// configured observer
+func (sm *ShrinkableMap[K, V]) observeErrors() {
+	observer, name := sm.config.MetricsObserver, sm.config.Name
+	if observer == nil {
+		return
+	}
+	sm.metrics.onError = func(e ErrorEvent) {
+		e.Map = name
+		observer.ObserveError(e)
+	}
+}
+
+// observeShrink reports a completed shrink to the configured observer
+func (sm *ShrinkableMap[K, V]) observeShrink(start time.Time, forced bool, copied, reclaimed int64, capacity int) {
+	if sm.config.MetricsObserver == nil {
+		return
+	}
+	sm.config.MetricsObserver.ObserveShrink(ShrinkEvent{
+		Map:         sm.config.Name,
+		Time:        start,
+		Duration:    time.Since(start),
+		Forced:      forced,
+		ItemsCopied: copied,
+		Reclaimed:   reclaimed,
+		Capacity:    capacity,
+	})
+}
@@ -0,0 +1,113 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type recordingObserver struct {
+	mu      sync.Mutex
+	shrinks []ShrinkEvent
+	ops     []OpEvent
+	errors  []ErrorEvent
+}
+
+func (o *recordingObserver) ObserveShrink(e ShrinkEvent) {
+	o.mu.Lock()
+	o.shrinks = append(o.shrinks, e)
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) ObserveOp(e OpEvent) {
+	o.mu.Lock()
+	o.ops = append(o.ops, e)
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) ObserveError(e ErrorEvent) {
+	o.mu.Lock()
+	o.errors = append(o.errors, e)
+	o.mu.Unlock()
+}
+
+func TestMetricsObserver(t *testing.T) {
+	t.Run("Operations And Shrinks", func(t *testing.T) {
+		observer := &recordingObserver{}
+		config := DefaultConfig().
+			WithAutoShrinkEnabled(false).
+			WithName("sessions").
+			WithMetricsObserver(observer)
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		sm.Get("a")
+		sm.Delete("a")
+		sm.ApplyBatch(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "c", Value: 3},
+		}})
+		sm.ForceShrink()
+
+		var ops []string
+		for _, e := range observer.ops {
+			if e.Map != "sessions" {
+				t.Errorf("Expected map name in event, got %+v", e)
+			}
+			ops = append(ops, e.Op)
+		}
+		expected := []string{"set", "set", "get", "delete", "batch", "shrink"}
+		if len(ops) != len(expected) {
+			t.Fatalf("Expected operations %v, got %v", expected, ops)
+		}
+		for i := range expected {
+			if ops[i] != expected[i] {
+				t.Errorf("Operation %d: expected %s, got %s", i, expected[i], ops[i])
+			}
+		}
+
+		if len(observer.shrinks) != 1 {
+			t.Fatalf("Expected 1 shrink event, got %d", len(observer.shrinks))
+		}
+		shrink := observer.shrinks[0]
+		if !shrink.Forced || shrink.ItemsCopied != 2 || shrink.Reclaimed != 1 || shrink.Capacity < 2 || shrink.Map != "sessions" {
+			t.Errorf("Unexpected shrink event: %+v", shrink)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		observer := &recordingObserver{}
+		sm := New[string, string](DefaultConfig().
+			WithMaxValueBytes(1, nil).
+			WithRecordAPIErrors(true).
+			WithMetricsObserver(observer))
+		defer sm.Stop()
+
+		sm.Set("a", "too large")
+		sm.metrics.RecordPanic("boom", "")
+		sm.metrics.notifier.wait()
+
+		if len(observer.errors) != 2 {
+			t.Fatalf("Expected 2 error events, got %+v", observer.errors)
+		}
+		if e := observer.errors[0]; e.Code != ErrCodeValueTooLarge || !errors.Is(e.Error.(error), ErrValueTooLarge) {
+			t.Errorf("Unexpected error event: %+v", e)
+		}
+		if e := observer.errors[1]; e.Code != ErrCodePanic || e.Error != "boom" {
+			t.Errorf("Unexpected panic event: %+v", e)
+		}
+	})
+
+	t.Run("Metrics Copies Do Not Observe", func(t *testing.T) {
+		observer := &recordingObserver{}
+		sm := New[string, int](DefaultConfig().WithMetricsObserver(observer))
+		defer sm.Stop()
+
+		metrics := sm.GetMetrics()
+		metrics.RecordError(errors.New("copy"), "")
+		if len(observer.errors) != 0 {
+			t.Errorf("Expected no events from a metrics copy, got %+v", observer.errors)
+		}
+	})
+}
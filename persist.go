@@ -0,0 +1,191 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+const (
+	// maxEntrySize bounds a single encoded entry to guard against corrupt length prefixes
+	maxEntrySize = 1 << 30
+
+	// maxPresize bounds the capacity allocated up front from an untrusted entry count
+	maxPresize = 1 << 20
+)
+
+// Snapshot file layout:
+//
+//	magic    [4]byte  "SHMS"
+//	version  uint16   snapshotVersion
+//	reserved uint16   must be zero
+//	length   uint64   size of the body in bytes
+//	checksum uint32   CRC-32C of the body
+//	body     uvarint entry count, then uvarint-length-prefixed encoded entries
+//
+// All integers in the header are big endian.
+const (
+	snapshotMagic      = "SHMS"
+	snapshotVersion    = 1
+	snapshotHeaderSize = 4 + 2 + 2 + 8 + 4
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrCorruptSnapshot is matched by errors.Is for every CorruptSnapshotError
+var ErrCorruptSnapshot = errors.New("shrinkmap: corrupt snapshot")
+
+// CorruptSnapshotError reports a snapshot that failed integrity verification.
+// Nothing is applied to the map when it is returned.
+type CorruptSnapshotError struct {
+	Reason string
+	Err    error
+}
+
+func (e *CorruptSnapshotError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", ErrCorruptSnapshot, e.Reason, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", ErrCorruptSnapshot, e.Reason)
+}
+
+// Is reports whether target is ErrCorruptSnapshot
+func (e *CorruptSnapshotError) Is(target error) bool {
+	return target == ErrCorruptSnapshot
+}
+
+// Unwrap returns the underlying cause, if any
+func (e *CorruptSnapshotError) Unwrap() error {
+	return e.Err
+}
+
+func corrupt(err error, format string, args ...interface{}) error {
+	return &CorruptSnapshotError{Reason: fmt.Sprintf(format, args...), Err: err}
+}
+
+// SaveTo writes a snapshot of the map to w using GobCodec
+func (sm *ShrinkableMap[K, V]) SaveTo(w io.Writer) error {
+	return sm.SaveToCodec(w, GobCodec[K, V]{})
+}
+
+// LoadFrom replaces the contents of the map with a snapshot written by SaveTo
+func (sm *ShrinkableMap[K, V]) LoadFrom(r io.Reader) error {
+	return sm.LoadFromCodec(r, GobCodec[K, V]{})
+}
+
+// SaveToCodec writes a snapshot of the map to w, encoding entries with codec.
+// The snapshot carries a versioned header with the body length and checksum.
+func (sm *ShrinkableMap[K, V]) SaveToCodec(w io.Writer, codec Codec[K, V]) error {
+	snapshot := sm.Snapshot()
+
+	var body bytes.Buffer
+	var prefix [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(prefix[:], uint64(len(snapshot)))
+	body.Write(prefix[:n])
+
+	for _, kv := range snapshot {
+		data, err := codec.EncodeEntry(kv.Key, kv.Value)
+		if err != nil {
+			return err
+		}
+		n := binary.PutUvarint(prefix[:], uint64(len(data)))
+		body.Write(prefix[:n])
+		body.Write(data)
+	}
+
+	var header [snapshotHeaderSize]byte
+	copy(header[0:4], snapshotMagic)
+	binary.BigEndian.PutUint16(header[4:6], snapshotVersion)
+	binary.BigEndian.PutUint64(header[8:16], uint64(body.Len()))
+	binary.BigEndian.PutUint32(header[16:20], crc32.Checksum(body.Bytes(), crcTable))
+
+	if _, err := w.Write(header[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(body.Bytes())
+	return err
+}
+
+// LoadFromCodec replaces the contents of the map with a snapshot written by
+// SaveToCodec with the same codec. The header and checksum are verified and
+// the snapshot is decoded completely before it is applied, so the map is left
+// untouched if anything fails. Integrity failures are reported as
+// *CorruptSnapshotError matching ErrCorruptSnapshot.
+func (sm *ShrinkableMap[K, V]) LoadFromCodec(r io.Reader, codec Codec[K, V]) error {
+	var header [snapshotHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return corrupt(err, "read header")
+	}
+	if string(header[0:4]) != snapshotMagic {
+		return corrupt(nil, "invalid magic %q", header[0:4])
+	}
+	if version := binary.BigEndian.Uint16(header[4:6]); version != snapshotVersion {
+		return corrupt(nil, "unsupported format version %d", version)
+	}
+	if reserved := binary.BigEndian.Uint16(header[6:8]); reserved != 0 {
+		return corrupt(nil, "non-zero reserved header bits %04x", reserved)
+	}
+	length := binary.BigEndian.Uint64(header[8:16])
+	checksum := binary.BigEndian.Uint32(header[16:20])
+	if length > 1<<62 {
+		return corrupt(nil, "invalid body length %d", length)
+	}
+
+	var body bytes.Buffer
+	n, err := io.Copy(&body, io.LimitReader(r, int64(length)))
+	if err != nil {
+		return fmt.Errorf("read snapshot body: %w", err)
+	}
+	if uint64(n) != length {
+		return corrupt(nil, "truncated body: expected %d bytes, got %d", length, n)
+	}
+	if actual := crc32.Checksum(body.Bytes(), crcTable); actual != checksum {
+		return corrupt(nil, "checksum mismatch: expected %08x, got %08x", checksum, actual)
+	}
+
+	data, err := sm.decodeSnapshotBody(&body, codec)
+	if err != nil {
+		return corrupt(err, "decode body")
+	}
+	sm.replaceData(data, int64(sm.config.InitialCapacity))
+	return nil
+}
+
+// decodeSnapshotBody decodes a verified snapshot body into a new map
+func (sm *ShrinkableMap[K, V]) decodeSnapshotBody(body *bytes.Buffer, codec Codec[K, V]) (map[K]V, error) {
+	count, err := binary.ReadUvarint(body)
+	if err != nil {
+		return nil, fmt.Errorf("read entry count: %w", err)
+	}
+
+	capacity := sm.config.InitialCapacity
+	if count <= maxPresize && int(count) > capacity {
+		capacity = int(count)
+	}
+	data := make(map[K]V, capacity)
+
+	for i := uint64(0); i < count; i++ {
+		size, err := binary.ReadUvarint(body)
+		if err != nil {
+			return nil, fmt.Errorf("read entry %d: %w", i, err)
+		}
+		if size > maxEntrySize || size > uint64(body.Len()) {
+			return nil, fmt.Errorf("entry %d length %d exceeds remaining data", i, size)
+		}
+		// Codecs may retain the buffer, so every entry gets its own
+		buf := make([]byte, size)
+		_, _ = body.Read(buf)
+		key, value, err := codec.DecodeEntry(buf)
+		if err != nil {
+			return nil, fmt.Errorf("decode entry %d: %w", i, err)
+		}
+		data[key] = value
+	}
+	if body.Len() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after %d entries", body.Len(), count)
+	}
+	return data, nil
+}
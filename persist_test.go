@@ -0,0 +1,103 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestPersistence(t *testing.T) {
+	t.Run("Save And Load", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		for i, k := range []string{"a", "b", "c"} {
+			sm.Set(k, i)
+		}
+
+		var buf bytes.Buffer
+		if err := sm.SaveTo(&buf); err != nil {
+			t.Fatalf("SaveTo failed: %v", err)
+		}
+
+		restored := New[string, int](DefaultConfig())
+		defer restored.Stop()
+		restored.Set("stale", 100)
+		if err := restored.LoadFrom(&buf); err != nil {
+			t.Fatalf("LoadFrom failed: %v", err)
+		}
+
+		if restored.Len() != 3 {
+			t.Errorf("Expected length 3, got %d", restored.Len())
+		}
+		if _, exists := restored.Get("stale"); exists {
+			t.Error("Expected existing entries to be replaced")
+		}
+		if v, exists := restored.Get("c"); !exists || v != 2 {
+			t.Errorf("Expected c=2, got %v, exists=%v", v, exists)
+		}
+	})
+
+	t.Run("Load Invalid Data", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		if err := sm.LoadFrom(bytes.NewReader([]byte("garbage"))); err == nil {
+			t.Error("Expected error for invalid snapshot")
+		}
+		if v, exists := sm.Get("a"); !exists || v != 1 {
+			t.Error("Map should be untouched after failed load")
+		}
+	})
+
+	t.Run("Corrupt Snapshots Rejected", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(fmt.Sprintf("key%d", i), i)
+		}
+
+		var buf bytes.Buffer
+		if err := sm.SaveTo(&buf); err != nil {
+			t.Fatalf("SaveTo failed: %v", err)
+		}
+		valid := buf.Bytes()
+
+		flipped := append([]byte(nil), valid...)
+		flipped[len(flipped)-1] ^= 0xff
+
+		badMagic := append([]byte(nil), valid...)
+		badMagic[0] = 'X'
+
+		reserved := append([]byte(nil), valid...)
+		reserved[7] = 1
+
+		cases := map[string][]byte{
+			"truncated header": valid[:10],
+			"truncated body":   valid[:len(valid)-5],
+			"flipped bit":      flipped,
+			"bad magic":        badMagic,
+			"reserved bits":    reserved,
+			"empty":            nil,
+		}
+
+		for name, data := range cases {
+			target := New[string, int](DefaultConfig())
+			target.Set("existing", 1)
+
+			err := target.LoadFrom(bytes.NewReader(data))
+			if !errors.Is(err, ErrCorruptSnapshot) {
+				t.Errorf("%s: expected ErrCorruptSnapshot, got %v", name, err)
+			}
+			var corruptErr *CorruptSnapshotError
+			if !errors.As(err, &corruptErr) || corruptErr.Reason == "" {
+				t.Errorf("%s: expected structured CorruptSnapshotError, got %v", name, err)
+			}
+			if target.Len() != 1 {
+				t.Errorf("%s: map should be untouched, got length %d", name, target.Len())
+			}
+			target.Stop()
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package shrinkmap
+
+import "errors"
+
+// ErrMemoryPressure is wrapped in the *KeyError returned by inserts of new keys
+// while the map is under memory pressure and Config.RejectInsertsUnderPressure
+// is enabled
+var ErrMemoryPressure = errors.New("shrinkmap: new keys rejected under memory pressure")
+
+// SetMemoryPressure signals whether the process is under memory pressure.
+// It is called by a MemoryCoordinator the map is registered with, or by a
+// custom memory watcher. While pressure is active and
+// Config.RejectInsertsUnderPressure is enabled, inserting new keys fails with
+// ErrMemoryPressure; reads, updates of existing keys and deletes still work.
+// The map recovers as soon as pressure is cleared.
+func (sm *ShrinkableMap[K, V]) SetMemoryPressure(active bool) {
+	sm.memoryPressure.Store(active)
+}
+
+// UnderMemoryPressure reports whether memory pressure is currently signaled
+func (sm *ShrinkableMap[K, V]) UnderMemoryPressure() bool {
+	return sm.memoryPressure.Load()
+}
+
+// rejectsInserts reports whether new keys are currently rejected
+func (sm *ShrinkableMap[K, V]) rejectsInserts() bool {
+	return sm.config.RejectInsertsUnderPressure && sm.memoryPressure.Load()
+}
+
+// checkInsertLocked returns a *KeyError wrapping ErrMemoryPressure if key is
+// new and inserts are rejected. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) checkInsertLocked(op string, key K) error {
+	if !sm.rejectsInserts() {
+		return nil
+	}
+	if _, exists := sm.data[key]; !exists {
+		return &KeyError{Op: op, Key: sm.errorKey(key), Err: ErrMemoryPressure}
+	}
+	return nil
+}
+
+// checkBatchLocked applies checkInsertLocked to every set operation of the
+// batch. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) checkBatchLocked(batch BatchOperations[K, V]) error {
+	if !sm.rejectsInserts() {
+		return nil
+	}
+	for _, op := range batch.Operations {
+		if op.Type != BatchSet {
+			continue
+		}
+		if err := sm.checkInsertLocked("batch", op.Key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMemoryPressure(t *testing.T) {
+	newPressuredMap := func(t *testing.T) *ShrinkableMap[string, int] {
+		sm := New[string, int](DefaultConfig().WithRejectInsertsUnderPressure(true))
+		t.Cleanup(sm.Stop)
+		sm.Set("existing", 1)
+		sm.SetMemoryPressure(true)
+		return sm
+	}
+
+	t.Run("Rejects New Keys Only", func(t *testing.T) {
+		sm := newPressuredMap(t)
+
+		err := sm.SetChecked("new", 1)
+		var keyErr *KeyError
+		if !errors.Is(err, ErrMemoryPressure) || !errors.As(err, &keyErr) {
+			t.Fatalf("Expected *KeyError wrapping ErrMemoryPressure, got %v", err)
+		}
+		if keyErr.Op != "set" || keyErr.Key != "new" {
+			t.Errorf("Expected set of key new, got %s of %v", keyErr.Op, keyErr.Key)
+		}
+		if err := sm.TrySet("new", 1); !errors.Is(err, ErrMemoryPressure) {
+			t.Errorf("Expected ErrMemoryPressure from TrySet, got %v", err)
+		}
+		if err := sm.SetChecked("existing", 2); err != nil {
+			t.Errorf("Expected update of existing key to succeed, got %v", err)
+		}
+		if v, _ := sm.Get("existing"); v != 2 {
+			t.Errorf("Expected updated value 2, got %d", v)
+		}
+		if !sm.Delete("existing") {
+			t.Error("Expected delete to succeed under pressure")
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		sm := newPressuredMap(t)
+
+		err := sm.ApplyBatch(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "existing", Value: 2},
+			{Type: BatchSet, Key: "new", Value: 1},
+		}})
+		if !errors.Is(err, ErrMemoryPressure) {
+			t.Errorf("Expected ErrMemoryPressure, got %v", err)
+		}
+		if v, _ := sm.Get("existing"); v != 1 {
+			t.Error("Rejected batch must not be partially applied")
+		}
+	})
+
+	t.Run("Recovers", func(t *testing.T) {
+		sm := newPressuredMap(t)
+		sm.SetMemoryPressure(false)
+		if err := sm.SetChecked("new", 1); err != nil {
+			t.Errorf("Expected insert to succeed after pressure cleared, got %v", err)
+		}
+	})
+
+	t.Run("Disabled By Default", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.SetMemoryPressure(true)
+		if err := sm.SetChecked("new", 1); err != nil {
+			t.Errorf("Expected insert to succeed without degradation mode, got %v", err)
+		}
+	})
+
+	t.Run("Signaled By Coordinator", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithRejectInsertsUnderPressure(true))
+		defer sm.Stop()
+
+		config := DefaultMemoryCoordinatorConfig()
+		c, _ := NewMemoryCoordinator(config)
+		defer c.Stop()
+		used := uint64(90)
+		c.readMemory = func() (uint64, uint64) { return used, 100 }
+		c.Register(sm)
+
+		c.Check()
+		if !errors.Is(sm.SetChecked("new", 1), ErrMemoryPressure) {
+			t.Error("Expected inserts to be rejected under coordinator pressure")
+		}
+		used = 10
+		c.Check()
+		if err := sm.SetChecked("new", 1); err != nil {
+			t.Errorf("Expected recovery once pressure drops, got %v", err)
+		}
+	})
+}
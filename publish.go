@@ -0,0 +1,68 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"time"
+)
+
+// Publish atomically replaces the contents of the map with the contents of staging.
+// Readers observe either the old or the new contents, never a mix of both.
+// The staging map is left empty and may be reused to prepare the next publish.
+//
+// Staged entries were written through the hooks of staging, so they are not
+// transformed or copied again, but they must pass the key and value
+// validators and Config.MaxValueBytes of the map: otherwise Publish returns
+// the first failure and leaves both maps unchanged. The validators run while
+// staging is locked and must not use it. Like other writes, Publish also
+// replaces the contents of a stopped map, and checks for a shrink once the
+// map reaches MaxMapSize.
+func (sm *ShrinkableMap[K, V]) Publish(staging *ShrinkableMap[K, V]) error {
+	if staging == nil {
+		return fmt.Errorf("staging map must not be nil")
+	}
+	if staging == sm {
+		return fmt.Errorf("cannot publish a map into itself")
+	}
+
+	// Take ownership of the staged data without holding both locks at once,
+	// so concurrent publishes in opposite directions cannot deadlock
+	staging.mu.Lock()
+	for k, v := range staging.data {
+		if err := sm.validateWrite("publish", k, v); err != nil {
+			staging.mu.Unlock()
+			return sm.apiError(err)
+		}
+	}
+	data := staging.data
+	sizeHint := staging.sizeHint.Load()
+	staging.data = make(map[K]V, staging.config.InitialCapacity)
+	staging.resetLocked()
+	staging.mu.Unlock()
+
+	if sm.replaceData(data, sizeHint) {
+		sm.TryShrink()
+	}
+	return nil
+}
+
+// resetLocked clears the per-entry state kept alongside the data after the
+// data has been handed over, so a reused staging map starts out as new.
+// Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) resetLocked() {
+	sm.itemCount.Store(0)
+	sm.deletedCount.Store(0)
+	sm.sizeHint.Store(int64(sm.config.InitialCapacity))
+	sm.interner.clear()
+	// Published keys keep the blocks they use alive
+	sm.keys = sm.keys.renew()
+	if sm.hashes != nil {
+		sm.hashes = make(map[K]uint64, sm.config.InitialCapacity)
+	}
+	if sm.generations != nil {
+		sm.generations = make(map[K]uint64, sm.config.InitialCapacity)
+	}
+	if sm.growth != nil {
+		sm.growth.reset(time.Now())
+	}
+	sm.migration = nil
+}
@@ -0,0 +1,171 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestPublish(t *testing.T) {
+	t.Run("Swaps Contents", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("old", 1)
+
+		staging := New[string, int](DefaultConfig())
+		defer staging.Stop()
+		staging.Set("new1", 10)
+		staging.Set("new2", 20)
+
+		if err := sm.Publish(staging); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+
+		if sm.Contains("old") {
+			t.Error("Old contents should be replaced")
+		}
+		if v, _ := sm.Get("new2"); v != 20 || sm.Len() != 2 {
+			t.Errorf("Expected published contents, got new2=%d len=%d", v, sm.Len())
+		}
+		if staging.Len() != 0 {
+			t.Errorf("Staging map should be empty after publish, got %d", staging.Len())
+		}
+
+		// Staging can be reused without affecting the published map
+		staging.Set("next", 1)
+		if sm.Contains("next") {
+			t.Error("Published map should not share storage with staging")
+		}
+	})
+
+	t.Run("Readers Never See Partial State", func(t *testing.T) {
+		const size = 100
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < size; i++ {
+			sm.Set(i, 0)
+		}
+
+		var stop atomic.Bool
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !stop.Load() {
+				snapshot := sm.Snapshot()
+				for _, kv := range snapshot[1:] {
+					if kv.Value != snapshot[0].Value {
+						t.Errorf("Mixed generations in snapshot: %d and %d", snapshot[0].Value, kv.Value)
+						return
+					}
+				}
+			}
+		}()
+
+		for gen := 1; gen <= 50; gen++ {
+			staging := New[int, int](DefaultConfig())
+			for i := 0; i < size; i++ {
+				staging.Set(i, gen)
+			}
+			if err := sm.Publish(staging); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+			staging.Stop()
+		}
+		stop.Store(true)
+		wg.Wait()
+	})
+
+	t.Run("Resets Staging State", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		config := DefaultConfig().WithVerifyImmutable(true).WithTrackGenerations(true)
+		staging := New[string, int](config)
+		defer staging.Stop()
+
+		for round := 0; round < 3; round++ {
+			staging.Set("a", round)
+			staging.Set("b", round)
+			if err := sm.Publish(staging); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+			if len(staging.hashes) != 0 || len(staging.generations) != 0 {
+				t.Fatalf("Round %d: expected staging state to be reset, got %d hashes and %d generations",
+					round, len(staging.hashes), len(staging.generations))
+			}
+		}
+		if v, _ := sm.Get("a"); v != 2 || sm.Len() != 2 {
+			t.Errorf("Expected last published contents, got a=%d len=%d", v, sm.Len())
+		}
+	})
+
+	t.Run("Invalid Arguments", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		if err := sm.Publish(nil); err == nil {
+			t.Error("Expected error for nil staging map")
+		}
+		if err := sm.Publish(sm); err == nil {
+			t.Error("Expected error publishing map into itself")
+		}
+	})
+
+	t.Run("Validates Staged Entries", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithValidators(nil, func(value any) error {
+			if value.(int) < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		}))
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		staging := New[string, int](DefaultConfig())
+		defer staging.Stop()
+		staging.Set("b", 2)
+		staging.Set("c", -1)
+
+		var validationErr *ValidationError
+		if err := sm.Publish(staging); !errors.As(err, &validationErr) {
+			t.Fatalf("Expected ValidationError, got %v", err)
+		}
+		if sm.Len() != 1 || staging.Len() != 2 {
+			t.Errorf("Expected both maps unchanged, got %d and %d entries", sm.Len(), staging.Len())
+		}
+	})
+
+	t.Run("Stopped Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		sm.Stop()
+
+		staging := New[string, int](DefaultConfig())
+		defer staging.Stop()
+		staging.Set("a", 1)
+		if err := sm.Publish(staging); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		if v, ok := sm.Get("a"); !ok || v != 1 {
+			t.Errorf("Expected a stopped map to be replaced like other writes, got %d, %v", v, ok)
+		}
+	})
+
+	t.Run("Max Map Size", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithMaxMapSize(10).WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+
+		staging := New[int, int](DefaultConfig())
+		defer staging.Stop()
+		for i := 0; i < 10; i++ {
+			staging.Set(i, i)
+		}
+		if err := sm.Publish(staging); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		if d := sm.ShrinkDecision(); d.ItemCount != 10 {
+			t.Errorf("Expected reaching MaxMapSize to check for a shrink like Set, got %+v", d)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package shrinkmap
+
+// ReadOnlyMap is a read-only view of a ShrinkableMap.
+// It reflects the live contents of the underlying map but exposes no mutation
+// methods, so it can be handed to subsystems that must not modify the map.
+type ReadOnlyMap[K comparable, V any] struct {
+	sm *ShrinkableMap[K, V]
+}
+
+// Freeze returns a read-only view of the map
+func (sm *ShrinkableMap[K, V]) Freeze() ReadOnlyMap[K, V] {
+	return ReadOnlyMap[K, V]{sm: sm}
+}
+
+// Contains reports whether the key is present in the map
+func (sm *ShrinkableMap[K, V]) Contains(key K) bool {
+	sm.mu.RLock()
+	_, exists := sm.data[key]
+	sm.mu.RUnlock()
+	return exists
+}
+
+// Get retrieves the value associated with the given key
+func (r ReadOnlyMap[K, V]) Get(key K) (V, bool) {
+	return r.sm.Get(key)
+}
+
+// Contains reports whether the key is present in the map
+func (r ReadOnlyMap[K, V]) Contains(key K) bool {
+	return r.sm.Contains(key)
+}
+
+// Len returns the current number of items in the map
+func (r ReadOnlyMap[K, V]) Len() int64 {
+	return r.sm.Len()
+}
+
+// Iterate calls fn for every entry of a snapshot of the map until fn returns false
+func (r ReadOnlyMap[K, V]) Iterate(fn func(key K, value V) bool) {
+	for _, kv := range r.sm.Snapshot() {
+		if !fn(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
+
+// NewIterator creates a new iterator over a snapshot of the map
+func (r ReadOnlyMap[K, V]) NewIterator() *Iterator[K, V] {
+	return r.sm.NewIterator()
+}
@@ -0,0 +1,51 @@
+package shrinkmap
+
+import "testing"
+
+func TestReadOnlyMap(t *testing.T) {
+	sm := New[string, int](DefaultConfig())
+	defer sm.Stop()
+	sm.Set("a", 1)
+	sm.Set("b", 2)
+
+	view := sm.Freeze()
+
+	if v, exists := view.Get("a"); !exists || v != 1 {
+		t.Errorf("Expected a=1, got %v, exists=%v", v, exists)
+	}
+	if !view.Contains("b") || view.Contains("c") {
+		t.Error("Contains returned unexpected result")
+	}
+
+	t.Run("Reflects Live Updates", func(t *testing.T) {
+		sm.Set("c", 3)
+		sm.Delete("a")
+
+		if view.Len() != 2 {
+			t.Errorf("Expected length 2, got %d", view.Len())
+		}
+		if view.Contains("a") || !view.Contains("c") {
+			t.Error("View should reflect changes to the underlying map")
+		}
+	})
+
+	t.Run("Iterate", func(t *testing.T) {
+		sum := 0
+		view.Iterate(func(_ string, v int) bool {
+			sum += v
+			return true
+		})
+		if sum != 5 {
+			t.Errorf("Expected sum 5, got %d", sum)
+		}
+
+		visited := 0
+		view.Iterate(func(string, int) bool {
+			visited++
+			return false
+		})
+		if visited != 1 {
+			t.Errorf("Iterate should stop when fn returns false, visited %d", visited)
+		}
+	})
+}
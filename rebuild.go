@@ -0,0 +1,177 @@
+package shrinkmap
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RebuildFunc fills staging with the complete replacement dataset.
+// Returning an error discards the partial dataset and keeps the current one.
+type RebuildFunc[K comparable, V any] func(ctx context.Context, staging *ShrinkableMap[K, V]) error
+
+// RebuilderConfig controls scheduled rebuilds
+type RebuilderConfig struct {
+	// How often the dataset is rebuilt by Start (0 to rebuild only on demand)
+	Interval time.Duration
+
+	// Maximum duration of a single rebuild (0 for no limit)
+	Timeout time.Duration
+}
+
+// Validate checks if the rebuilder configuration is valid
+func (c RebuilderConfig) Validate() error {
+	if c.Interval < 0 {
+		return fmt.Errorf("rebuild interval must be non-negative")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("rebuild timeout must be non-negative")
+	}
+	return nil
+}
+
+// RebuildMetrics describes the rebuilds run by a Rebuilder
+type RebuildMetrics struct {
+	Swaps        int64
+	Failures     int64
+	LastDuration time.Duration
+	LastSwap     time.Time
+}
+
+// Rebuilder periodically rebuilds the contents of a map in the background and
+// swaps them in atomically with Publish, so readers never observe a partially
+// built dataset. It suits reference-data caches refreshed on a schedule.
+type Rebuilder[K comparable, V any] struct {
+	target  *ShrinkableMap[K, V]
+	staging *ShrinkableMap[K, V]
+	build   RebuildFunc[K, V]
+	config  RebuilderConfig
+
+	rebuildMu sync.Mutex // serializes rebuilds sharing the staging map
+
+	mu      sync.Mutex
+	metrics RebuildMetrics
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewRebuilder creates a rebuilder for target. The staging map uses the
+// target's configuration without auto shrinking.
+func NewRebuilder[K comparable, V any](target *ShrinkableMap[K, V], build RebuildFunc[K, V], config RebuilderConfig) (*Rebuilder[K, V], error) {
+	if target == nil {
+		return nil, fmt.Errorf("rebuild target must not be nil")
+	}
+	if build == nil {
+		return nil, fmt.Errorf("rebuild function must not be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	stagingConfig := target.config
+	stagingConfig.AutoShrinkEnabled = false
+	return &Rebuilder[K, V]{
+		target:  target,
+		staging: New[K, V](stagingConfig),
+		build:   build,
+		config:  config,
+	}, nil
+}
+
+// Rebuild builds a new dataset now and swaps it into the target map.
+// Failures are recorded in the target map's metrics and returned.
+func (r *Rebuilder[K, V]) Rebuild(ctx context.Context) error {
+	r.rebuildMu.Lock()
+	defer r.rebuildMu.Unlock()
+
+	if r.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.config.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := r.build(ctx, r.staging)
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = r.target.Publish(r.staging)
+	}
+	duration := time.Since(start)
+
+	r.mu.Lock()
+	r.metrics.LastDuration = duration
+	if err != nil {
+		r.metrics.Failures++
+	} else {
+		r.metrics.Swaps++
+		r.metrics.LastSwap = time.Now()
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		// Discard the partial dataset
+		r.staging.replaceData(make(map[K]V, r.staging.config.InitialCapacity), int64(r.staging.config.InitialCapacity))
+		err = fmt.Errorf("rebuild: %w", err)
+		r.target.metrics.RecordError(err, "")
+	}
+	return err
+}
+
+// Start rebuilds the dataset every Interval until Stop is called or the
+// target map is stopped
+func (r *Rebuilder[K, V]) Start() error {
+	if r.config.Interval <= 0 {
+		return fmt.Errorf("rebuild interval must be positive to start scheduled rebuilds")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cancel != nil {
+		return fmt.Errorf("rebuilder already started")
+	}
+
+	ctx, cancel := context.WithCancel(r.target.ctx)
+	r.cancel = cancel
+	r.done = make(chan struct{})
+
+	go func(done chan struct{}) {
+		defer close(done)
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = r.Rebuild(ctx)
+			}
+		}
+	}(r.done)
+	return nil
+}
+
+// Stop stops scheduled rebuilds, waits for a running one to finish and
+// releases the staging map. The target map is left running.
+func (r *Rebuilder[K, V]) Stop() {
+	r.mu.Lock()
+	cancel, done := r.cancel, r.done
+	r.cancel, r.done = nil, nil
+	r.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+	r.staging.Stop()
+}
+
+// Metrics returns a copy of the rebuild metrics
+func (r *Rebuilder[K, V]) Metrics() RebuildMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
@@ -0,0 +1,85 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRebuilder(t *testing.T) {
+	t.Run("Swap", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("stale", 1)
+
+		version := 0
+		r, err := NewRebuilder(sm, func(ctx context.Context, staging *ShrinkableMap[string, int]) error {
+			version++
+			return staging.SetChecked("version", version)
+		}, RebuilderConfig{})
+		if err != nil {
+			t.Fatalf("NewRebuilder failed: %v", err)
+		}
+		defer r.Stop()
+
+		for i := 0; i < 2; i++ {
+			if err := r.Rebuild(context.Background()); err != nil {
+				t.Fatalf("Rebuild failed: %v", err)
+			}
+		}
+		if v, _ := sm.Get("version"); v != 2 || sm.Contains("stale") {
+			t.Errorf("Expected only version=2 after rebuilds, got %v", sm.Snapshot())
+		}
+		if m := r.Metrics(); m.Swaps != 2 || m.Failures != 0 || m.LastSwap.IsZero() {
+			t.Errorf("Unexpected metrics: %+v", m)
+		}
+	})
+
+	t.Run("Failure Keeps Current Data", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("current", 1)
+
+		errBuild := errors.New("source unavailable")
+		r, _ := NewRebuilder(sm, func(ctx context.Context, staging *ShrinkableMap[string, int]) error {
+			staging.Set("partial", 1)
+			return errBuild
+		}, RebuilderConfig{})
+		defer r.Stop()
+
+		if err := r.Rebuild(context.Background()); !errors.Is(err, errBuild) {
+			t.Errorf("Expected build error, got %v", err)
+		}
+		if !sm.Contains("current") || sm.Contains("partial") || r.staging.Len() != 0 {
+			t.Error("Expected failed rebuild to leave the map unchanged and discard staging")
+		}
+		metrics := sm.GetMetrics()
+		if r.Metrics().Failures != 1 || metrics.TotalErrors() != 1 {
+			t.Errorf("Expected failure to be recorded, got %+v", r.Metrics())
+		}
+	})
+
+	t.Run("Scheduled", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		var builds atomic.Int64
+		r, _ := NewRebuilder(sm, func(ctx context.Context, staging *ShrinkableMap[string, int]) error {
+			return staging.SetChecked("n", int(builds.Add(1)))
+		}, RebuilderConfig{Interval: 5 * time.Millisecond})
+		if err := r.Start(); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		if err := r.Start(); err == nil {
+			t.Error("Expected error when starting twice")
+		}
+		time.Sleep(50 * time.Millisecond)
+		r.Stop()
+
+		if r.Metrics().Swaps == 0 || !sm.Contains("n") {
+			t.Error("Expected scheduled rebuilds to swap in data")
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ErrMapNotRegistered is returned when a registry has no map with the requested name
+var ErrMapNotRegistered = errors.New("shrinkmap: map not registered")
+
+// ErrMapTypeMismatch is returned when a registered map has different key or value types
+var ErrMapTypeMismatch = errors.New("shrinkmap: map type mismatch")
+
+// Registry shares maps by name between packages.
+// Maps of any key and value types can be registered; Get checks the types at
+// runtime when a map is retrieved.
+type Registry struct {
+	mu   sync.RWMutex
+	maps map[string]any
+}
+
+// DefaultRegistry is a process-wide registry for services that need only one
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{maps: make(map[string]any)}
+}
+
+// Register adds sm to the registry under name. Names must be unique.
+func Register[K comparable, V any](r *Registry, name string, sm *ShrinkableMap[K, V]) error {
+	if sm == nil {
+		return fmt.Errorf("cannot register nil map %q", name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.maps[name]; exists {
+		return fmt.Errorf("map %q is already registered", name)
+	}
+	r.maps[name] = sm
+	return nil
+}
+
+// Get returns the map registered under name. It fails with ErrMapNotRegistered
+// if there is none, or ErrMapTypeMismatch if it is not a *ShrinkableMap[K, V].
+func Get[K comparable, V any](r *Registry, name string) (*ShrinkableMap[K, V], error) {
+	r.mu.RLock()
+	m, exists := r.maps[name]
+	r.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("%w: %q", ErrMapNotRegistered, name)
+	}
+	sm, ok := m.(*ShrinkableMap[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is %T, not %T", ErrMapTypeMismatch, name, m, sm)
+	}
+	return sm, nil
+}
+
+// Unregister removes the map registered under name and reports whether it existed.
+// The map itself is not stopped.
+func (r *Registry) Unregister(name string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, exists := r.maps[name]
+	delete(r.maps, name)
+	return exists
+}
+
+// Names returns the registered names in sorted order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.maps))
+	for name := range r.maps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
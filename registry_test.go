@@ -0,0 +1,60 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Run("Register And Get", func(t *testing.T) {
+		r := NewRegistry()
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		if err := Register(r, "users", sm); err != nil {
+			t.Fatalf("Register failed: %v", err)
+		}
+		if err := Register(r, "users", sm); err == nil {
+			t.Error("Expected error for duplicate name")
+		}
+
+		got, err := Get[string, int](r, "users")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if got != sm {
+			t.Error("Expected the registered map")
+		}
+		if names := r.Names(); len(names) != 1 || names[0] != "users" {
+			t.Errorf("Unexpected names: %v", names)
+		}
+	})
+
+	t.Run("Type Mismatch", func(t *testing.T) {
+		r := NewRegistry()
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		_ = Register(r, "users", sm)
+
+		if _, err := Get[string, string](r, "users"); !errors.Is(err, ErrMapTypeMismatch) {
+			t.Errorf("Expected ErrMapTypeMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Not Registered", func(t *testing.T) {
+		r := NewRegistry()
+		if _, err := Get[string, int](r, "missing"); !errors.Is(err, ErrMapNotRegistered) {
+			t.Errorf("Expected ErrMapNotRegistered, got %v", err)
+		}
+
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		_ = Register(r, "users", sm)
+		if !r.Unregister("users") || r.Unregister("users") {
+			t.Error("Expected Unregister to succeed exactly once")
+		}
+		if _, err := Get[string, int](r, "users"); !errors.Is(err, ErrMapNotRegistered) {
+			t.Errorf("Expected ErrMapNotRegistered after Unregister, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,44 @@
+package shrinkmap
+
+// Rename moves the value stored under oldKey to newKey under a single lock.
+// It returns a *KeyError wrapping ErrKeyNotFound if oldKey is missing, or
+// wrapping ErrKeyExists if newKey is present and overwrite is false, and a
+// *ValidationError if Config.ValidateKey rejects newKey.
+func (sm *ShrinkableMap[K, V]) Rename(oldKey, newKey K, overwrite bool) error {
+	return sm.apiError(sm.rename(oldKey, newKey, overwrite))
+}
+
+func (sm *ShrinkableMap[K, V]) rename(oldKey, newKey K, overwrite bool) error {
+	if sm.config.ValidateKey != nil {
+		if err := sm.config.ValidateKey(newKey); err != nil {
+			return &ValidationError{Op: "rename", Field: "key", Key: sm.errorKey(newKey), Err: err}
+		}
+	}
+
+	sm.mu.Lock()
+
+	value, exists := sm.data[oldKey]
+	if !exists {
+		sm.mu.Unlock()
+		return &KeyError{Op: "rename", Key: sm.errorKey(oldKey), Err: ErrKeyNotFound}
+	}
+	if oldKey == newKey {
+		sm.mu.Unlock()
+		return nil
+	}
+	if _, conflict := sm.data[newKey]; conflict {
+		if !overwrite {
+			sm.mu.Unlock()
+			return &KeyError{Op: "rename", Key: sm.errorKey(newKey), Err: ErrKeyExists}
+		}
+	}
+
+	sm.deleteLocked(oldKey)
+	needsShrink := sm.setLocked(newKey, value)
+	sm.mu.Unlock()
+
+	if needsShrink || sm.config.AutoShrinkEnabled {
+		sm.TryShrink()
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package shrinkmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRename(t *testing.T) {
+	t.Run("Rename To New Key", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("old", 1)
+
+		if err := sm.Rename("old", "new", false); err != nil {
+			t.Fatalf("Rename failed: %v", err)
+		}
+		if sm.Contains("old") {
+			t.Error("Old key should be removed")
+		}
+		if v, _ := sm.Get("new"); v != 1 {
+			t.Errorf("Expected new=1, got %d", v)
+		}
+		if sm.Len() != 1 {
+			t.Errorf("Expected length 1, got %d", sm.Len())
+		}
+	})
+
+	t.Run("Missing Source", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		err := sm.Rename("missing", "new", false)
+		if !errors.Is(err, ErrKeyNotFound) {
+			t.Errorf("Expected ErrKeyNotFound, got %v", err)
+		}
+		var keyErr *KeyError
+		if !errors.As(err, &keyErr) || keyErr.Key != "missing" || keyErr.Op != "rename" {
+			t.Errorf("Expected KeyError for missing key, got %#v", err)
+		}
+	})
+
+	t.Run("Conflicting Destination", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+
+		if err := sm.Rename("a", "b", false); !errors.Is(err, ErrKeyExists) {
+			t.Errorf("Expected ErrKeyExists, got %v", err)
+		}
+		if v, _ := sm.Get("b"); v != 2 || !sm.Contains("a") {
+			t.Error("Map should be unchanged after conflicting rename")
+		}
+
+		if err := sm.Rename("a", "b", true); err != nil {
+			t.Fatalf("Rename with overwrite failed: %v", err)
+		}
+		if v, _ := sm.Get("b"); v != 1 || sm.Contains("a") {
+			t.Errorf("Expected b=1 after overwrite, got %d", v)
+		}
+		if sm.Len() != 1 {
+			t.Errorf("Expected length 1, got %d", sm.Len())
+		}
+	})
+
+	t.Run("Same Key", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		if err := sm.Rename("a", "a", false); err != nil {
+			t.Errorf("Renaming a key to itself should succeed, got %v", err)
+		}
+		if v, _ := sm.Get("a"); v != 1 || sm.Len() != 1 {
+			t.Error("Map should be unchanged")
+		}
+	})
+	t.Run("Invalid New Key", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithValidators(func(key any) error {
+			if key.(string) == "" {
+				return errors.New("empty key")
+			}
+			return nil
+		}, nil))
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		var verr *ValidationError
+		if err := sm.Rename("a", "", false); !errors.As(err, &verr) || verr.Field != "key" {
+			t.Errorf("Expected a key *ValidationError, got %v", err)
+		}
+		if !sm.Contains("a") || sm.Len() != 1 {
+			t.Error("Map should be unchanged")
+		}
+	})
+}
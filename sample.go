@@ -0,0 +1,70 @@
+package shrinkmap
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// SampleWeighted draws up to n distinct entries at random, each with
+// probability proportional to weight(key, value). Entries with a zero,
+// negative or NaN weight are never drawn, so fewer than n entries are returned
+// when not enough entries have a positive weight. Entries failing
+// Config.ValidateOnGet are never drawn.
+//
+// Sampling is a single pass under the read lock (weighted reservoir sampling),
+// so weight must not call back into the map.
+func (sm *ShrinkableMap[K, V]) SampleWeighted(n int, weight func(key K, value V) float64) []KeyValue[K, V] {
+	if n <= 0 {
+		return nil
+	}
+
+	// Each entry gets the key u^(1/w) for uniform u; the n largest keys form a
+	// weighted sample without replacement. Comparing log(u)/w avoids underflow.
+	reservoir := make(sampleHeap[K, V], 0, n)
+
+	sm.mu.RLock()
+	for k, v := range sm.data {
+		if sm.config.ValidateOnGet != nil && !sm.config.ValidateOnGet(k, v) {
+			continue
+		}
+		w := weight(k, v)
+		if !(w > 0) {
+			continue
+		}
+		priority := math.Log(1-rand.Float64()) / w
+		if len(reservoir) < n {
+			heap.Push(&reservoir, sampleItem[K, V]{KeyValue[K, V]{k, v}, priority})
+		} else if priority > reservoir[0].priority {
+			reservoir[0] = sampleItem[K, V]{KeyValue[K, V]{k, v}, priority}
+			heap.Fix(&reservoir, 0)
+		}
+	}
+	sm.mu.RUnlock()
+
+	result := make([]KeyValue[K, V], len(reservoir))
+	for i, item := range reservoir {
+		result[i] = item.entry
+	}
+	sm.copyEntriesOnRead(result)
+	return result
+}
+
+type sampleItem[K comparable, V any] struct {
+	entry    KeyValue[K, V]
+	priority float64
+}
+
+// sampleHeap is a min-heap on priority so the weakest sample is replaced first
+type sampleHeap[K comparable, V any] []sampleItem[K, V]
+
+func (h sampleHeap[K, V]) Len() int           { return len(h) }
+func (h sampleHeap[K, V]) Less(i, j int) bool { return h[i].priority < h[j].priority }
+func (h sampleHeap[K, V]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *sampleHeap[K, V]) Push(x any)        { *h = append(*h, x.(sampleItem[K, V])) }
+func (h *sampleHeap[K, V]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,98 @@
+package shrinkmap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSampleWeighted(t *testing.T) {
+	t.Run("Proportional", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("light", 1)
+		sm.Set("heavy", 9)
+
+		counts := make(map[string]int)
+		const rounds = 20000
+		for i := 0; i < rounds; i++ {
+			sample := sm.SampleWeighted(1, func(_ string, v int) float64 { return float64(v) })
+			if len(sample) != 1 {
+				t.Fatalf("Expected 1 entry, got %d", len(sample))
+			}
+			counts[sample[0].Key]++
+		}
+		if ratio := float64(counts["heavy"]) / rounds; math.Abs(ratio-0.9) > 0.02 {
+			t.Errorf("Expected heavy drawn about 90%% of the time, got %.3f", ratio)
+		}
+	})
+
+	t.Run("Distinct And Bounded", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+
+		sample := sm.SampleWeighted(10, func(k, _ int) float64 { return 1 })
+		if len(sample) != 10 {
+			t.Fatalf("Expected 10 entries, got %d", len(sample))
+		}
+		seen := make(map[int]bool)
+		for _, kv := range sample {
+			if seen[kv.Key] {
+				t.Errorf("Duplicate key %d in sample", kv.Key)
+			}
+			seen[kv.Key] = true
+		}
+
+		if sample := sm.SampleWeighted(500, func(k, _ int) float64 { return 1 }); len(sample) != 100 {
+			t.Errorf("Expected the whole map, got %d entries", len(sample))
+		}
+	})
+
+	t.Run("Non Positive Weights Excluded", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i)
+		}
+
+		sample := sm.SampleWeighted(10, func(k, _ int) float64 {
+			switch {
+			case k < 3:
+				return 0
+			case k < 6:
+				return math.NaN()
+			case k < 8:
+				return -1
+			}
+			return 1
+		})
+		if len(sample) != 2 {
+			t.Errorf("Expected only the 2 positively weighted entries, got %v", sample)
+		}
+		if sample := sm.SampleWeighted(0, func(int, int) float64 { return 1 }); sample != nil {
+			t.Errorf("Expected nil for n=0, got %v", sample)
+		}
+	})
+
+	t.Run("Invalid Entries Excluded", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithValidateOnGet(func(_, value any) bool {
+			return value.(int)%2 == 0
+		}, false))
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Set(i, i)
+		}
+
+		sample := sm.SampleWeighted(10, func(int, int) float64 { return 1 })
+		if len(sample) != 5 {
+			t.Errorf("Expected only the 5 valid entries, got %v", sample)
+		}
+		for _, kv := range sample {
+			if kv.Value%2 != 0 {
+				t.Errorf("Expected no invalid entries, got %v", kv)
+			}
+		}
+	})
+}
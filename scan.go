@@ -0,0 +1,91 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// ErrUnknownPredicate is returned by Scan for a predicate name that was not registered
+var ErrUnknownPredicate = errors.New("shrinkmap: unknown scan predicate")
+
+// ScanFilter selects the entries returned by Scan. Filters are plain values so
+// they can be taken from query parameters of an admin endpoint; all set
+// fields must match.
+type ScanFilter struct {
+	// Keys must start with Prefix; requires string keys
+	Prefix string
+
+	// Keys must match the regular expression Pattern; requires string keys
+	Pattern string
+
+	// Entries must satisfy the predicate registered under this name with
+	// RegisterPredicate
+	Predicate string
+
+	// Maximum number of entries returned (0 for unlimited)
+	Limit int
+}
+
+// RegisterPredicate makes fn available to Scan under name, replacing any
+// predicate registered under the same name. Predicates are called with the
+// map read-locked and must not modify it.
+func (sm *ShrinkableMap[K, V]) RegisterPredicate(name string, fn func(key K, value V) bool) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.predicates == nil {
+		sm.predicates = make(map[string]func(K, V) bool)
+	}
+	sm.predicates[name] = fn
+}
+
+// Scan returns the entries matching filter in no particular order, stopping
+// after filter.Limit matches
+func (sm *ShrinkableMap[K, V]) Scan(filter ScanFilter) ([]KeyValue[K, V], error) {
+	var re *regexp.Regexp
+	if filter.Prefix != "" || filter.Pattern != "" {
+		if err := requireStringKeys[K](); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Pattern != "" {
+		var err error
+		if re, err = regexp.Compile(filter.Pattern); err != nil {
+			return nil, fmt.Errorf("invalid scan pattern %q: %w", filter.Pattern, err)
+		}
+	}
+	if filter.Limit < 0 {
+		return nil, fmt.Errorf("scan limit must be non-negative")
+	}
+
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	var pred func(K, V) bool
+	if filter.Predicate != "" {
+		if pred = sm.predicates[filter.Predicate]; pred == nil {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownPredicate, filter.Predicate)
+		}
+	}
+
+	var result []KeyValue[K, V]
+	for k, v := range sm.data {
+		if filter.Limit > 0 && len(result) == filter.Limit {
+			break
+		}
+		if re != nil || filter.Prefix != "" {
+			s := reflect.ValueOf(k).String()
+			if !strings.HasPrefix(s, filter.Prefix) || (re != nil && !re.MatchString(s)) {
+				continue
+			}
+		}
+		if pred != nil && !pred(k, v) {
+			continue
+		}
+		result = append(result, KeyValue[K, V]{Key: k, Value: v})
+	}
+	sm.copyEntriesOnRead(result)
+	return result, nil
+}
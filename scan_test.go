@@ -0,0 +1,80 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+func TestScan(t *testing.T) {
+	sm := New[string, int](DefaultConfig())
+	defer sm.Stop()
+	for key, value := range map[string]int{"user:1": 10, "user:2": 20, "user:10": 30, "order:1": 40} {
+		sm.Set(key, value)
+	}
+	sm.RegisterPredicate("large", func(_ string, v int) bool { return v >= 20 })
+
+	keys := func(entries []KeyValue[string, int]) []string {
+		var result []string
+		for _, kv := range entries {
+			result = append(result, kv.Key)
+		}
+		sort.Strings(result)
+		return result
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			filter ScanFilter
+			want   []string
+		}{
+			{"All", ScanFilter{}, []string{"order:1", "user:1", "user:10", "user:2"}},
+			{"Prefix", ScanFilter{Prefix: "user:"}, []string{"user:1", "user:10", "user:2"}},
+			{"Pattern", ScanFilter{Pattern: `^user:\d$`}, []string{"user:1", "user:2"}},
+			{"Predicate", ScanFilter{Predicate: "large"}, []string{"order:1", "user:10", "user:2"}},
+			{"Combined", ScanFilter{Prefix: "user:", Pattern: `\d$`, Predicate: "large"}, []string{"user:10", "user:2"}},
+		}
+		for _, tt := range tests {
+			entries, err := sm.Scan(tt.filter)
+			if err != nil {
+				t.Fatalf("%s: %v", tt.name, err)
+			}
+			if got := keys(entries); len(got) != len(tt.want) {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+			} else {
+				for i := range got {
+					if got[i] != tt.want[i] {
+						t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+						break
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		entries, err := sm.Scan(ScanFilter{Prefix: "user:", Limit: 2})
+		if err != nil || len(entries) != 2 {
+			t.Errorf("Expected 2 entries, got %v, %v", entries, err)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := sm.Scan(ScanFilter{Predicate: "missing"}); !errors.Is(err, ErrUnknownPredicate) {
+			t.Errorf("Expected ErrUnknownPredicate, got %v", err)
+		}
+		if _, err := sm.Scan(ScanFilter{Pattern: "("}); err == nil {
+			t.Error("Expected error for invalid pattern")
+		}
+		if _, err := sm.Scan(ScanFilter{Limit: -1}); err == nil {
+			t.Error("Expected error for negative limit")
+		}
+
+		ints := New[int, int](DefaultConfig())
+		defer ints.Stop()
+		if _, err := ints.Scan(ScanFilter{Prefix: "1"}); err == nil {
+			t.Error("Expected error for prefix on non-string keys")
+		}
+	})
+}
@@ -0,0 +1,146 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// shrinkSchedule is a parsed Config.ShrinkSchedule: a set of cron-style
+// windows, each matching the minutes during which shrinking is allowed
+type shrinkSchedule struct {
+	windows  []cronWindow
+	location *time.Location
+}
+
+// cronWindow holds one bit per allowed value of each cron field
+type cronWindow struct {
+	minute, hour, dom, month, dow uint64
+
+	// Whether the day fields start with *, which decides how they combine
+	domStar, dowStar bool
+}
+
+// cronFields lists the name and range of the five cron fields in order
+var cronFields = [5]struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7}, // 0 and 7 are both Sunday
+}
+
+// parseShrinkSchedule parses windows separated by ";", each in the five-field
+// cron format "minute hour day-of-month month day-of-week". Fields accept *,
+// single values, ranges (1-5), lists (1,3,5) and steps (*/15, 0-30/10). An
+// optional "CRON_TZ=<zone>" prefix selects the time zone; local time is used otherwise.
+func parseShrinkSchedule(spec string) (*shrinkSchedule, error) {
+	spec = strings.TrimSpace(spec)
+	s := &shrinkSchedule{location: time.Local}
+	if rest, ok := strings.CutPrefix(spec, "CRON_TZ="); ok {
+		zone, windows, _ := strings.Cut(rest, " ")
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			return nil, fmt.Errorf("shrink schedule: %w", err)
+		}
+		s.location = loc
+		spec = windows
+	}
+
+	for _, expr := range strings.Split(spec, ";") {
+		fields := strings.Fields(expr)
+		if len(fields) != len(cronFields) {
+			return nil, fmt.Errorf("shrink schedule %q: expected %d fields, got %d", expr, len(cronFields), len(fields))
+		}
+		var bits [5]uint64
+		for i, field := range fields {
+			b, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+			if err != nil {
+				return nil, fmt.Errorf("shrink schedule %q: %s: %w", expr, cronFields[i].name, err)
+			}
+			bits[i] = b
+		}
+		// Sunday may be written as 7
+		if bits[4]&(1<<7) != 0 {
+			bits[4] |= 1
+		}
+		s.windows = append(s.windows, cronWindow{
+			minute:  bits[0],
+			hour:    bits[1],
+			dom:     bits[2],
+			month:   bits[3],
+			dow:     bits[4],
+			domStar: strings.HasPrefix(fields[2], "*"),
+			dowStar: strings.HasPrefix(fields[4], "*"),
+		})
+	}
+	return s, nil
+}
+
+// parseCronField returns the set of values matched by a comma-separated field
+func parseCronField(field string, lo, hi int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		if rng != "*" {
+			first, last, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = strconv.Atoi(first); err != nil {
+				return 0, fmt.Errorf("invalid value %q", first)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(last); err != nil {
+					return 0, fmt.Errorf("invalid value %q", last)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// allows reports whether t falls inside one of the windows. As in cron, when
+// both day fields are restricted a day matching either one is allowed.
+func (s *shrinkSchedule) allows(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	t = t.In(s.location)
+	for _, w := range s.windows {
+		if w.minute&(1<<t.Minute()) == 0 || w.hour&(1<<t.Hour()) == 0 || w.month&(1<<int(t.Month())) == 0 {
+			continue
+		}
+		domMatch := w.dom&(1<<t.Day()) != 0
+		dowMatch := w.dow&(1<<int(t.Weekday())) != 0
+		if w.domStar || w.dowStar {
+			if domMatch && dowMatch {
+				return true
+			}
+		} else if domMatch || dowMatch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestShrinkSchedule(t *testing.T) {
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04 Mon", s, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	t.Run("Windows", func(t *testing.T) {
+		tests := []struct {
+			spec    string
+			time    string
+			allowed bool
+		}{
+			{"* 1-4 * * *", "2024-03-05 01:00 Tue", true},
+			{"* 1-4 * * *", "2024-03-05 04:59 Tue", true},
+			{"* 1-4 * * *", "2024-03-05 05:00 Tue", false},
+			{"*/15 * * * *", "2024-03-05 10:30 Tue", true},
+			{"*/15 * * * *", "2024-03-05 10:31 Tue", false},
+			{"0-30/10 * * * *", "2024-03-05 10:20 Tue", true},
+			{"0-30/10 * * * *", "2024-03-05 10:40 Tue", false},
+			{"* * * * 0,6", "2024-03-09 12:00 Sat", true},
+			{"* * * * 7", "2024-03-10 12:00 Sun", true},
+			{"* * * * 1-5", "2024-03-10 12:00 Sun", false},
+			// Both day fields restricted: either one matches
+			{"* * 1 * 0", "2024-03-10 12:00 Sun", true},
+			{"* * 1 * 0", "2024-03-01 12:00 Fri", true},
+			{"* * 1 * 0", "2024-03-02 12:00 Sat", false},
+			{"* * * 12 *", "2024-03-02 12:00 Sat", false},
+			{"* 22-23 * * *; * 0-2 * * *", "2024-03-05 01:30 Tue", true},
+			{"* 22-23 * * *; * 0-2 * * *", "2024-03-05 12:00 Tue", false},
+		}
+		for _, tt := range tests {
+			s, err := parseShrinkSchedule("CRON_TZ=UTC " + tt.spec)
+			if err != nil {
+				t.Fatalf("%q: %v", tt.spec, err)
+			}
+			if got := s.allows(at(tt.time)); got != tt.allowed {
+				t.Errorf("%q at %s: expected %v, got %v", tt.spec, tt.time, tt.allowed, got)
+			}
+		}
+	})
+
+	t.Run("Time Zone", func(t *testing.T) {
+		s, err := parseShrinkSchedule("CRON_TZ=Asia/Seoul * 2 * * *")
+		if err != nil {
+			t.Skipf("Time zone data unavailable: %v", err)
+		}
+		// 02:30 in Seoul (UTC+9)
+		if !s.allows(at("2024-03-04 17:30 Mon")) {
+			t.Error("Expected the window to be evaluated in the given zone")
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, spec := range []string{"* * * *", "60 * * * *", "* 5-2 * * *", "*/0 * * * *", "a * * * *", "CRON_TZ=Nowhere/Land * * * * *"} {
+			if err := DefaultConfig().WithShrinkSchedule(spec).Validate(); err == nil {
+				t.Errorf("Expected error for %q", spec)
+			}
+		}
+	})
+
+	t.Run("Postpones Shrink Outside Window", func(t *testing.T) {
+		// A window covering only an hour away from now
+		hour := (time.Now().Hour() + 12) % 24
+		config := DefaultConfig().
+			WithAutoShrinkEnabled(false).
+			WithMinShrinkInterval(time.Nanosecond).
+			WithShrinkSchedule(fmt.Sprintf("* %d * * *", hour))
+		sm := New[int, int](config)
+		defer sm.Stop()
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 90; i++ {
+			sm.Delete(i)
+		}
+
+		if sm.TryShrink() {
+			t.Fatal("Expected no shrink outside the window")
+		}
+		if d := sm.ShrinkDecision(); d.Reason != DecisionOutsideWindow {
+			t.Errorf("Expected %v, got %v", DecisionOutsideWindow, d.Reason)
+		}
+		if !sm.ForceShrink() {
+			t.Error("Expected ForceShrink to ignore the schedule")
+		}
+
+		// Inside the window the postponed shrink runs
+		sm.schedule, _ = parseShrinkSchedule("* * * * *")
+		for i := 90; i < 99; i++ {
+			sm.Delete(i)
+		}
+		if !sm.TryShrink() {
+			t.Errorf("Expected the shrink to run inside the window, got %v", sm.ShrinkDecision().Reason)
+		}
+	})
+}
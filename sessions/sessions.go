@@ -0,0 +1,321 @@
+// Package sessions implements a session store on top of ShrinkableMap with
+// idle and absolute timeouts. Sessions are created with random identifiers,
+// kept alive by Touch and removed by Invalidate or when they expire. Hooks
+// allow mirroring sessions to persistent storage.
+package sessions
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// ErrSessionNotFound is returned for unknown, invalidated or expired sessions
+var ErrSessionNotFound = errors.New("sessions: session not found")
+
+// Session is a stored session with its application data
+type Session[T any] struct {
+	ID         string
+	Data       T
+	CreatedAt  time.Time
+	LastAccess time.Time
+}
+
+// RemoveReason describes why a session was removed
+type RemoveReason int
+
+const (
+	// Invalidated sessions were removed by Invalidate
+	Invalidated RemoveReason = iota
+	// IdleExpired sessions were not touched within IdleTimeout
+	IdleExpired
+	// AbsoluteExpired sessions outlived AbsoluteTimeout
+	AbsoluteExpired
+)
+
+func (r RemoveReason) String() string {
+	switch r {
+	case Invalidated:
+		return "invalidated"
+	case IdleExpired:
+		return "idle_expired"
+	case AbsoluteExpired:
+		return "absolute_expired"
+	default:
+		return fmt.Sprintf("RemoveReason(%d)", int(r))
+	}
+}
+
+// Hooks mirror session changes to persistent storage. They are called
+// synchronously; an error from OnSave fails the operation that caused it.
+type Hooks[T any] struct {
+	// Called after a session is created or touched
+	OnSave func(Session[T]) error
+
+	// Called after a session is removed
+	OnRemove func(id string, reason RemoveReason)
+}
+
+// Config defines the behavior of a Manager
+type Config[T any] struct {
+	// Sessions not touched for this long expire (0 disables)
+	IdleTimeout time.Duration
+
+	// Sessions expire this long after creation regardless of activity (0 disables)
+	AbsoluteTimeout time.Duration
+
+	// How often expired sessions are removed in the background (0 disables;
+	// expired sessions are still never returned)
+	CleanupInterval time.Duration
+
+	// Generates session identifiers; nil uses 32 random bytes, base64url encoded
+	GenerateID func() (string, error)
+
+	// Persistence hooks
+	Hooks Hooks[T]
+
+	// Configuration of the underlying map
+	Map shrinkmap.Config
+}
+
+// DefaultConfig returns a configuration with a 30 minute idle timeout, a
+// 24 hour absolute timeout and cleanup every minute
+func DefaultConfig[T any]() Config[T] {
+	return Config[T]{
+		IdleTimeout:     30 * time.Minute,
+		AbsoluteTimeout: 24 * time.Hour,
+		CleanupInterval: time.Minute,
+		Map:             shrinkmap.DefaultConfig(),
+	}
+}
+
+// Validate checks if the configuration is valid
+func (c Config[T]) Validate() error {
+	if c.IdleTimeout < 0 || c.AbsoluteTimeout < 0 || c.CleanupInterval < 0 {
+		return fmt.Errorf("timeouts and cleanup interval must be non-negative")
+	}
+	if c.IdleTimeout == 0 && c.AbsoluteTimeout == 0 {
+		return fmt.Errorf("at least one of idle and absolute timeout must be set")
+	}
+	return c.Map.Validate()
+}
+
+// Manager stores sessions keyed by identifier
+type Manager[T any] struct {
+	config Config[T]
+	sm     *shrinkmap.ShrinkableMap[string, Session[T]]
+	now    func() time.Time
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// New creates a Manager and starts background cleanup if configured
+func New[T any](config Config[T]) (*Manager[T], error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if config.GenerateID == nil {
+		config.GenerateID = randomID
+	}
+	m := &Manager[T]{
+		config: config,
+		sm:     shrinkmap.New[string, Session[T]](config.Map),
+		now:    time.Now,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	if config.CleanupInterval > 0 {
+		go m.cleanupLoop()
+	} else {
+		close(m.done)
+	}
+	return m, nil
+}
+
+func randomID() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+// CreateSession stores a new session holding data
+func (m *Manager[T]) CreateSession(data T) (Session[T], error) {
+	id, err := m.config.GenerateID()
+	if err != nil {
+		return Session[T]{}, fmt.Errorf("sessions: generate id: %w", err)
+	}
+	now := m.now()
+	s := Session[T]{ID: id, Data: data, CreatedAt: now, LastAccess: now}
+
+	unlock := m.sm.LockKey(id)
+	defer unlock()
+	if m.sm.Contains(id) {
+		return Session[T]{}, fmt.Errorf("sessions: duplicate session id")
+	}
+	if err := m.save(s); err != nil {
+		return Session[T]{}, err
+	}
+	return s, nil
+}
+
+// Get returns the session without extending its idle timeout
+func (m *Manager[T]) Get(id string) (Session[T], error) {
+	s, ok := m.sm.Get(id)
+	if !ok {
+		return Session[T]{}, ErrSessionNotFound
+	}
+	if _, expired := m.expired(s, m.now()); expired {
+		m.removeIfExpired(id)
+		return Session[T]{}, ErrSessionNotFound
+	}
+	return s, nil
+}
+
+// Touch records activity on the session, extending its idle timeout, and
+// returns the updated session
+func (m *Manager[T]) Touch(id string) (Session[T], error) {
+	unlock := m.sm.LockKey(id)
+	defer unlock()
+
+	s, ok := m.sm.Get(id)
+	if !ok {
+		return Session[T]{}, ErrSessionNotFound
+	}
+	now := m.now()
+	if reason, expired := m.expired(s, now); expired {
+		m.remove(id, reason)
+		return Session[T]{}, ErrSessionNotFound
+	}
+	s.LastAccess = now
+	if err := m.save(s); err != nil {
+		return Session[T]{}, err
+	}
+	return s, nil
+}
+
+// Invalidate removes the session, e.g. on logout
+func (m *Manager[T]) Invalidate(id string) error {
+	unlock := m.sm.LockKey(id)
+	defer unlock()
+
+	if !m.sm.Contains(id) {
+		return ErrSessionNotFound
+	}
+	m.remove(id, Invalidated)
+	return nil
+}
+
+// Restore loads sessions read from persistent storage, e.g. at startup.
+// Expired sessions are skipped; OnSave is not called. Returns the number of
+// sessions restored.
+func (m *Manager[T]) Restore(sessions []Session[T]) (int, error) {
+	now := m.now()
+	restored := 0
+	for _, s := range sessions {
+		if _, expired := m.expired(s, now); expired {
+			continue
+		}
+		if err := m.sm.SetChecked(s.ID, s); err != nil {
+			return restored, err
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// DeleteExpired removes all expired sessions and returns how many were removed
+func (m *Manager[T]) DeleteExpired() int {
+	removed := 0
+	now := m.now()
+	for _, kv := range m.sm.Snapshot() {
+		if _, expired := m.expired(kv.Value, now); expired && m.removeIfExpired(kv.Key) {
+			removed++
+		}
+	}
+	return removed
+}
+
+// Len returns the number of stored sessions, including expired sessions not yet removed
+func (m *Manager[T]) Len() int64 {
+	return m.sm.Len()
+}
+
+// Stop stops background cleanup and the underlying map
+func (m *Manager[T]) Stop() {
+	select {
+	case <-m.stop:
+	default:
+		close(m.stop)
+	}
+	<-m.done
+	m.sm.Stop()
+}
+
+func (m *Manager[T]) cleanupLoop() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.config.CleanupInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			return
+		case <-ticker.C:
+			m.DeleteExpired()
+		}
+	}
+}
+
+// expired reports whether s has expired at now and which timeout applies
+func (m *Manager[T]) expired(s Session[T], now time.Time) (RemoveReason, bool) {
+	if m.config.AbsoluteTimeout > 0 && !now.Before(s.CreatedAt.Add(m.config.AbsoluteTimeout)) {
+		return AbsoluteExpired, true
+	}
+	if m.config.IdleTimeout > 0 && !now.Before(s.LastAccess.Add(m.config.IdleTimeout)) {
+		return IdleExpired, true
+	}
+	return 0, false
+}
+
+// removeIfExpired removes id if it is still expired, re-checking under the
+// key lock so a concurrent Touch is not lost
+func (m *Manager[T]) removeIfExpired(id string) bool {
+	unlock := m.sm.LockKey(id)
+	defer unlock()
+
+	s, ok := m.sm.Get(id)
+	if !ok {
+		return false
+	}
+	reason, expired := m.expired(s, m.now())
+	if !expired {
+		return false
+	}
+	m.remove(id, reason)
+	return true
+}
+
+// save stores s and calls OnSave. Must be called with the key lock held.
+func (m *Manager[T]) save(s Session[T]) error {
+	if err := m.sm.SetChecked(s.ID, s); err != nil {
+		return err
+	}
+	if m.config.Hooks.OnSave != nil {
+		if err := m.config.Hooks.OnSave(s); err != nil {
+			return fmt.Errorf("sessions: save: %w", err)
+		}
+	}
+	return nil
+}
+
+// remove deletes id and calls OnRemove. Must be called with the key lock held.
+func (m *Manager[T]) remove(id string, reason RemoveReason) {
+	if m.sm.Delete(id) && m.config.Hooks.OnRemove != nil {
+		m.config.Hooks.OnRemove(id, reason)
+	}
+}
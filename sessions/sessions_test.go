@@ -0,0 +1,121 @@
+package sessions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newManager(t *testing.T, config Config[string]) (*Manager[string], *time.Time) {
+	m, err := New(config)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	t.Cleanup(m.Stop)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	return m, &now
+}
+
+func TestManager(t *testing.T) {
+	t.Run("Create Touch Invalidate", func(t *testing.T) {
+		m, _ := newManager(t, DefaultConfig[string]())
+
+		s, err := m.CreateSession("alice")
+		if err != nil {
+			t.Fatalf("CreateSession failed: %v", err)
+		}
+		if len(s.ID) == 0 {
+			t.Fatal("Expected generated session id")
+		}
+		if got, err := m.Get(s.ID); err != nil || got.Data != "alice" {
+			t.Errorf("Expected session data alice, got %q, %v", got.Data, err)
+		}
+		if _, err := m.Touch(s.ID); err != nil {
+			t.Errorf("Touch failed: %v", err)
+		}
+		if err := m.Invalidate(s.ID); err != nil {
+			t.Errorf("Invalidate failed: %v", err)
+		}
+		if _, err := m.Get(s.ID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound after Invalidate, got %v", err)
+		}
+		if err := m.Invalidate(s.ID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected ErrSessionNotFound, got %v", err)
+		}
+	})
+
+	t.Run("Idle Timeout", func(t *testing.T) {
+		config := DefaultConfig[string]()
+		config.IdleTimeout = time.Minute
+		m, now := newManager(t, config)
+
+		s, _ := m.CreateSession("alice")
+		*now = now.Add(50 * time.Second)
+		if _, err := m.Touch(s.ID); err != nil {
+			t.Fatalf("Touch failed: %v", err)
+		}
+		*now = now.Add(50 * time.Second)
+		if _, err := m.Get(s.ID); err != nil {
+			t.Errorf("Expected touched session to be alive, got %v", err)
+		}
+		*now = now.Add(time.Minute)
+		if _, err := m.Get(s.ID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected idle session to expire, got %v", err)
+		}
+	})
+
+	t.Run("Absolute Timeout", func(t *testing.T) {
+		config := DefaultConfig[string]()
+		config.IdleTimeout = time.Minute
+		config.AbsoluteTimeout = 2 * time.Minute
+		m, now := newManager(t, config)
+
+		s, _ := m.CreateSession("alice")
+		for i := 0; i < 4; i++ {
+			*now = now.Add(30 * time.Second)
+			m.Touch(s.ID)
+		}
+		if _, err := m.Touch(s.ID); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("Expected session to expire despite activity, got %v", err)
+		}
+	})
+
+	t.Run("Hooks And Cleanup", func(t *testing.T) {
+		saved := map[string]Session[string]{}
+		removed := map[string]RemoveReason{}
+		config := DefaultConfig[string]()
+		config.CleanupInterval = 0
+		config.Hooks = Hooks[string]{
+			OnSave:   func(s Session[string]) error { saved[s.ID] = s; return nil },
+			OnRemove: func(id string, reason RemoveReason) { removed[id] = reason },
+		}
+		m, now := newManager(t, config)
+
+		a, _ := m.CreateSession("a")
+		b, _ := m.CreateSession("b")
+		m.Invalidate(a.ID)
+		*now = now.Add(time.Hour)
+
+		if n := m.DeleteExpired(); n != 1 {
+			t.Errorf("Expected one expired session, got %d", n)
+		}
+		if len(saved) != 2 || removed[a.ID] != Invalidated || removed[b.ID] != IdleExpired {
+			t.Errorf("Unexpected hook calls: saved=%d removed=%v", len(saved), removed)
+		}
+
+		// Sessions restored from storage come back unless expired
+		restored, _ := m.Restore([]Session[string]{saved[a.ID], {ID: "fresh", CreatedAt: *now, LastAccess: *now}})
+		if restored != 1 || m.Len() != 1 {
+			t.Errorf("Expected only fresh session to be restored, got %d", restored)
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		config := DefaultConfig[string]()
+		config.IdleTimeout, config.AbsoluteTimeout = 0, 0
+		if _, err := New(config); err == nil {
+			t.Error("Expected error without timeouts")
+		}
+	})
+}
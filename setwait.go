@@ -0,0 +1,65 @@
+package shrinkmap
+
+import "context"
+
+// SetWait stores a key-value pair like Set, but treats MaxMapSize as a hard
+// limit: inserting a new key into a full map blocks until entries are
+// deleted or evicted. Updates of existing keys never block. It returns
+// ctx.Err() if the context ends first and ErrMapStopped if the map is stopped
+// while waiting. Without a MaxMapSize it behaves like Set.
+//
+// SetWait makes the map usable as a bounded buffer between producers and
+// consumers; only SetWait observes the limit, so Set can still exceed it.
+func (sm *ShrinkableMap[K, V]) SetWait(ctx context.Context, key K, value V) error {
+	return sm.apiError(sm.setWait(ctx, key, value))
+}
+
+func (sm *ShrinkableMap[K, V]) setWait(ctx context.Context, key K, value V) error {
+	defer sm.finishKeyOp("set", sm.startOp(), key)
+	value, err := sm.prepareWrite("set", key, value)
+	if err != nil {
+		return err
+	}
+
+	for {
+		sm.mu.Lock()
+		_, exists := sm.data[key]
+		if exists || sm.config.MaxMapSize == 0 || len(sm.data) < sm.config.MaxMapSize {
+			break
+		}
+		if sm.spaceFreed == nil {
+			sm.spaceFreed = make(chan struct{})
+		}
+		freed := sm.spaceFreed
+		sm.mu.Unlock()
+
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-sm.ctx.Done():
+			return ErrMapStopped
+		}
+	}
+
+	if err := sm.checkInsertLocked("set", key); err != nil {
+		sm.mu.Unlock()
+		return err
+	}
+	needsShrink := sm.setLocked(key, value)
+	sm.mu.Unlock()
+
+	if needsShrink {
+		sm.TryShrink()
+	}
+	return nil
+}
+
+// notifySpaceFreedLocked wakes SetWait callers after entries were removed.
+// Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) notifySpaceFreedLocked() {
+	if sm.spaceFreed != nil {
+		close(sm.spaceFreed)
+		sm.spaceFreed = nil
+	}
+}
@@ -0,0 +1,86 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSetWait(t *testing.T) {
+	config := DefaultConfig().WithAutoShrinkEnabled(false).WithMaxMapSize(2)
+
+	t.Run("Blocks Until Space Frees", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		ctx := context.Background()
+		sm.SetWait(ctx, 1, 1)
+		sm.SetWait(ctx, 2, 2)
+
+		done := make(chan error, 1)
+		go func() { done <- sm.SetWait(ctx, 3, 3) }()
+		select {
+		case err := <-done:
+			t.Fatalf("Expected SetWait to block on a full map, got %v", err)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		// Updating an existing key does not need space
+		if err := sm.SetWait(ctx, 1, 10); err != nil {
+			t.Fatalf("Expected update to succeed, got %v", err)
+		}
+
+		sm.Delete(2)
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Expected SetWait to succeed, got %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected SetWait to resume after a delete")
+		}
+		if v, ok := sm.Get(3); !ok || v != 3 || sm.Len() != 2 {
+			t.Errorf("Expected key 3 stored with 2 entries, got %v, %v, len %d", v, ok, sm.Len())
+		}
+	})
+
+	t.Run("Context Expires", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		sm.Set(1, 1)
+		sm.Set(2, 2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		if err := sm.SetWait(ctx, 3, 3); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+		}
+		if _, ok := sm.Get(3); ok {
+			t.Error("Expected key 3 not stored")
+		}
+	})
+
+	t.Run("Stopped While Waiting", func(t *testing.T) {
+		sm := New[int, int](config)
+		sm.Set(1, 1)
+		sm.Set(2, 2)
+
+		done := make(chan error, 1)
+		go func() { done <- sm.SetWait(context.Background(), 3, 3) }()
+		time.Sleep(10 * time.Millisecond)
+		sm.Stop()
+		if err := <-done; !errors.Is(err, ErrMapStopped) {
+			t.Errorf("Expected ErrMapStopped, got %v", err)
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		sm := New[int, int](config.WithMaxMapSize(0))
+		defer sm.Stop()
+		for i := 0; i < 100; i++ {
+			if err := sm.SetWait(context.Background(), i, i); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+}
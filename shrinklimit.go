@@ -0,0 +1,53 @@
+package shrinkmap
+
+import "sync"
+
+// shrinkLimiter bounds the number of shrink copies running at once across all maps
+var shrinkLimiter = newConcurrencyLimiter()
+
+// SetMaxConcurrentShrinks limits how many maps in the process may copy their
+// entries during a shrink at the same time, so many maps shrinking at the
+// same tick do not cause a CPU and allocation spike. Shrinks beyond the limit
+// wait for a running one to finish. n <= 0 removes the limit, which is the default.
+func SetMaxConcurrentShrinks(n int) {
+	shrinkLimiter.setLimit(n)
+}
+
+// concurrencyLimiter is a semaphore whose limit can change while in use
+type concurrencyLimiter struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+	peak   int // highest number of concurrent holders, for tests
+}
+
+func newConcurrencyLimiter() *concurrencyLimiter {
+	l := &concurrencyLimiter{}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+func (l *concurrencyLimiter) setLimit(n int) {
+	l.mu.Lock()
+	l.limit = max(n, 0)
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
+
+func (l *concurrencyLimiter) acquire() {
+	l.mu.Lock()
+	for l.limit > 0 && l.active >= l.limit {
+		l.cond.Wait()
+	}
+	l.active++
+	l.peak = max(l.peak, l.active)
+	l.mu.Unlock()
+}
+
+func (l *concurrencyLimiter) release() {
+	l.mu.Lock()
+	l.active--
+	l.mu.Unlock()
+	l.cond.Signal()
+}
@@ -0,0 +1,44 @@
+package shrinkmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestMaxConcurrentShrinks(t *testing.T) {
+	SetMaxConcurrentShrinks(2)
+	defer SetMaxConcurrentShrinks(0)
+	shrinkLimiter.mu.Lock()
+	shrinkLimiter.peak = 0
+	shrinkLimiter.mu.Unlock()
+
+	config := DefaultConfig()
+	config.AutoShrinkEnabled = false
+	maps := make([]*ShrinkableMap[int, int], 8)
+	for i := range maps {
+		maps[i] = New[int, int](config)
+		defer maps[i].Stop()
+		for j := 0; j < 20000; j++ {
+			maps[i].Set(j, j)
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, sm := range maps {
+		wg.Add(1)
+		go func(sm *ShrinkableMap[int, int]) {
+			defer wg.Done()
+			if !sm.ForceShrink() {
+				t.Error("Expected shrink to run")
+			}
+		}(sm)
+	}
+	wg.Wait()
+
+	shrinkLimiter.mu.Lock()
+	peak := shrinkLimiter.peak
+	shrinkLimiter.mu.Unlock()
+	if peak > 2 || peak == 0 {
+		t.Errorf("Expected at most 2 concurrent shrinks, got %d", peak)
+	}
+}
@@ -2,6 +2,9 @@ package shrinkmap
 
 import (
 	"context"
+	"errors"
+	"maps"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,18 +15,47 @@ import (
 // The goroutine will continue to run until Stop() is called, even if there are no more references to the map.
 // For transient use cases, ensure to call Stop() when the map is no longer needed to prevent goroutine leaks.
 type ShrinkableMap[K comparable, V any] struct {
+	id             uint64
 	mu             sync.RWMutex
 	data           map[K]V
 	itemCount      atomic.Int64
 	deletedCount   atomic.Int64
+	sizeHint       atomic.Int64
 	config         Config
 	lastShrinkTime atomic.Value
+	schedule       *shrinkSchedule
+	decision       decisionSlot
 	metrics        *Metrics
 	shrinking      atomic.Bool
+	shrinkPending  atomic.Bool
+	ctx            context.Context
 	cancel         context.CancelFunc
 	stopped        atomic.Bool
+	memoryPressure atomic.Bool
+	sinks          []*sinkPump[K, V]
+	history        *history[K, V]
+	watchers       []*watcher[K, V]
+	keyLocks       keyLockTable[K]
+	predicates     map[string]func(K, V) bool // registered for Scan, guarded by mu
+	hashes         map[K]uint64               // value hashes under Config.VerifyImmutable, guarded by mu
+	generations    map[K]uint64               // entry generations under Config.TrackGenerations, guarded by mu
+	generation     uint64                     // last assigned generation, guarded by mu
+	growth         *growthTracker             // nil unless Config.GrowthHysteresis is set
+	shrinkStatus   loopState                  // auto-shrink goroutine, reported by Status
+	interner       *interner[V]
+	keys           *keyArena[K]
+	migration      *migration[K, V] // incremental shrink in progress, guarded by mu
+	apiErrorLimit  apiErrorLimiter
+	spaceFreed     chan struct{} // closed when entries are removed while SetWait callers wait, guarded by mu
+	buffers        sync.Pool     // *[]KeyValue[K, V] reused by pooled snapshots
 }
 
+// freeOSMemory is replaced in tests
+var freeOSMemory = debug.FreeOSMemory
+
+// nextMapID hands out unique map identifiers used to order lock acquisition across maps
+var nextMapID atomic.Uint64
+
 // KeyValue represents a key-value pair for iteration purposes
 type KeyValue[K comparable, V any] struct {
 	Key   K
@@ -32,32 +64,75 @@ type KeyValue[K comparable, V any] struct {
 
 // New creates a new ShrinkableMap with the given configuration
 func New[K comparable, V any](config Config) *ShrinkableMap[K, V] {
+	sm := newMap[K, V](config)
+	if config.AutoShrinkEnabled {
+		sm.shrinkStatus.start()
+		go sm.shrinkLoop(sm.ctx)
+	}
+	return sm
+}
+
+// newMap creates a map without starting its shrink goroutine
+func newMap[K comparable, V any](config Config) *ShrinkableMap[K, V] {
+	// Labels are copied so later changes by the caller do not leak into metrics
+	config.Labels = copyLabels(config.Labels)
 	ctx, cancel := context.WithCancel(context.Background())
 	sm := &ShrinkableMap[K, V]{
-		data:    make(map[K]V, config.InitialCapacity),
-		config:  config,
-		metrics: &Metrics{},
-		cancel:  cancel,
+		id:       nextMapID.Add(1),
+		data:     make(map[K]V, config.InitialCapacity),
+		config:   config,
+		metrics:  &Metrics{alerts: newAlerter(config.Alerts), errorTimes: newErrorTimes(config.Health.ErrorThreshold)},
+		history:  newHistory[K, V](config),
+		growth:   newGrowthTracker(config.GrowthHysteresis),
+		interner: newInterner[V](config),
+		keys:     newKeyArena[K](config),
+		ctx:      ctx,
+		cancel:   cancel,
 	}
 
+	sm.observeErrors()
+	if config.VerifyImmutable {
+		sm.hashes = make(map[K]uint64, config.InitialCapacity)
+	}
+	if config.TrackGenerations {
+		sm.generations = make(map[K]uint64, config.InitialCapacity)
+	}
 	sm.lastShrinkTime.Store(time.Now())
+	if err := config.evictionError(); err != nil {
+		sm.metrics.RecordError(err, "")
+	}
+	if config.ShrinkSchedule != "" {
+		// An invalid schedule is reported and shrinking stays unrestricted
+		schedule, err := parseShrinkSchedule(config.ShrinkSchedule)
+		if err != nil {
+			sm.metrics.RecordError(err, "")
+		}
+		sm.schedule = schedule
+	}
 
 	sm.itemCount.Store(0)
 	sm.deletedCount.Store(0)
-
-	if config.AutoShrinkEnabled {
-		go sm.shrinkLoop(ctx)
-	}
+	sm.sizeHint.Store(int64(config.InitialCapacity))
 	return sm
 }
 
 // Stop terminates the auto-shrink goroutine if it's running
 // This should be called when the map is no longer needed to prevent goroutine leaks
+// Attached sinks are flushed and detached and watch subscriptions end before Stop returns
 func (sm *ShrinkableMap[K, V]) Stop() {
 	if sm.stopped.CompareAndSwap(false, true) {
 		if sm.cancel != nil {
 			sm.cancel()
 		}
+
+		sm.mu.Lock()
+		sinks := sm.sinks
+		sm.sinks = nil
+		sm.watchers = nil
+		sm.mu.Unlock()
+		for _, p := range sinks {
+			p.close()
+		}
 	}
 }
 
@@ -71,55 +146,302 @@ func (sm *ShrinkableMap[K, V]) Snapshot() []KeyValue[K, V] {
 	for k, v := range sm.data {
 		result = append(result, KeyValue[K, V]{Key: k, Value: v})
 	}
+	sm.copyEntriesOnRead(result)
 	return result
 }
 
 // Set stores a key-value pair in the map
+// A write rejected for one of the reasons listed on SetChecked is dropped and
+// recorded in the metrics; use SetChecked to handle the error instead
 func (sm *ShrinkableMap[K, V]) Set(key K, value V) {
+	if err := sm.set(key, value); err != nil {
+		sm.recordAPIError(err)
+	}
+}
+
+// SetChecked stores a key-value pair like Set and returns an error if the
+// write is rejected: a *ValidationError if Config.ValidateKey or
+// Config.ValidateValue rejects the pair, a *KeyError wrapping ErrValueTooLarge
+// if the value exceeds MaxValueBytes, or a *KeyError wrapping ErrMemoryPressure
+// if the key is new and inserts are rejected under memory pressure
+func (sm *ShrinkableMap[K, V]) SetChecked(key K, value V) error {
+	return sm.apiError(sm.set(key, value))
+}
+
+func (sm *ShrinkableMap[K, V]) set(key K, value V) error {
+	defer sm.finishKeyOp("set", sm.startOp(), key)
+	value, err := sm.prepareWrite("set", key, value)
+	if err != nil {
+		return err
+	}
+
 	sm.mu.Lock()
-	_, exists := sm.data[key]
-	sm.data[key] = value
-	if !exists {
-		sm.itemCount.Add(1)
-		sm.updateMetrics(1)
+	if err := sm.checkInsertLocked("set", key); err != nil {
+		sm.mu.Unlock()
+		return err
 	}
-	needsShrink := sm.config.MaxMapSize > 0 && sm.itemCount.Load() >= int64(sm.config.MaxMapSize)
+	needsShrink := sm.setLocked(key, value)
 	sm.mu.Unlock()
 
 	if needsShrink {
 		sm.TryShrink()
 	}
+	return nil
+}
+
+// MustSet stores a key-value pair like SetChecked, but panics instead of
+// returning an error.
+// Intended for tests and init-time population.
+func (sm *ShrinkableMap[K, V]) MustSet(key K, value V) {
+	if err := sm.SetChecked(key, value); err != nil {
+		panic(&KeyError{Op: "set", Key: sm.errorKey(key), Err: err})
+	}
+}
+
+// TrySet stores a key-value pair like Set, but returns ErrWouldBlock
+// immediately instead of waiting if the map lock is held
+func (sm *ShrinkableMap[K, V]) TrySet(key K, value V) error {
+	return sm.apiError(sm.trySet(key, value))
+}
+
+func (sm *ShrinkableMap[K, V]) trySet(key K, value V) error {
+	value, err := sm.prepareWrite("set", key, value)
+	if err != nil {
+		return err
+	}
+	if !sm.mu.TryLock() {
+		return ErrWouldBlock
+	}
+	if err := sm.checkInsertLocked("set", key); err != nil {
+		sm.mu.Unlock()
+		return err
+	}
+	needsShrink := sm.setLocked(key, value)
+	sm.mu.Unlock()
+
+	if needsShrink {
+		sm.TryShrink()
+	}
+	return nil
+}
+
+// setLocked stores the pair and reports whether the map reached MaxMapSize.
+// Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) setLocked(key K, value V) bool {
+	old, exists := sm.data[key]
+	value = sm.interner.acquire(value)
+	if !exists {
+		key = sm.keys.intern(key)
+	}
+	if exists {
+		sm.verifyLocked(key, old)
+	}
+	sm.data[key] = value
+	sm.hashWriteLocked(key, value)
+	sm.nextGenerationLocked(key)
+	if exists {
+		sm.interner.release(old)
+	} else {
+		sm.itemCount.Add(1)
+		sm.accountInsertLocked()
+		if sm.growth != nil {
+			sm.growth.record(time.Now(), 1)
+		}
+	}
+	if sm.migration != nil {
+		sm.migration.set(key, value)
+	}
+	sm.emitChange(ChangeSet, key, value)
+	return sm.config.MaxMapSize > 0 && sm.itemCount.Load() >= int64(sm.config.MaxMapSize)
 }
 
 // Get retrieves the value associated with the given key
+// Entries failing Config.ValidateOnGet are reported as missing
 func (sm *ShrinkableMap[K, V]) Get(key K) (V, bool) {
+	defer sm.finishKeyOp("get", sm.startOp(), key)
 	sm.mu.RLock()
 	value, exists := sm.data[key]
 	sm.mu.RUnlock()
-	return value, exists
+	return sm.checkRead(key, value, exists, true)
+}
+
+// MustGet returns the value for key, panicking with a *KeyError wrapping
+// ErrKeyNotFound if the key is not present
+func (sm *ShrinkableMap[K, V]) MustGet(key K) V {
+	value, exists := sm.Get(key)
+	if !exists {
+		panic(&KeyError{Op: "get", Key: sm.errorKey(key), Err: ErrKeyNotFound})
+	}
+	return value
+}
+
+// GetOrDefault returns the value for key, or def if the key is not present.
+// The default is not stored in the map.
+func (sm *ShrinkableMap[K, V]) GetOrDefault(key K, def V) V {
+	if value, exists := sm.Get(key); exists {
+		return value
+	}
+	return def
+}
+
+// GetOrElse returns the value for key, or the result of fn(key) if the key is
+// not present. fn is called without holding the map lock and its result is not
+// stored in the map.
+func (sm *ShrinkableMap[K, V]) GetOrElse(key K, fn func(K) V) V {
+	if value, exists := sm.Get(key); exists {
+		return value
+	}
+	return fn(key)
+}
+
+// TryGet retrieves the value like Get, but returns ErrWouldBlock
+// immediately instead of waiting if a writer holds the map lock.
+// Invalid entries are reported as missing but never deleted, since that would block.
+func (sm *ShrinkableMap[K, V]) TryGet(key K) (V, bool, error) {
+	if !sm.mu.TryRLock() {
+		var zero V
+		return zero, false, ErrWouldBlock
+	}
+	value, exists := sm.data[key]
+	sm.mu.RUnlock()
+	value, exists = sm.checkRead(key, value, exists, false)
+	return value, exists, nil
 }
 
 // Delete removes the entry for the given key
 func (sm *ShrinkableMap[K, V]) Delete(key K) bool {
+	defer sm.finishKeyOp("delete", sm.startOp(), key)
 	sm.mu.Lock()
-	_, exists := sm.data[key]
+	_, exists := sm.deleteLocked(key)
+	sm.mu.Unlock()
+
+	if exists && sm.config.AutoShrinkEnabled {
+		sm.TryShrink()
+	}
+	return exists
+}
+
+// deleteKeys removes the keys under a single lock and returns how many were present
+func (sm *ShrinkableMap[K, V]) deleteKeys(keys []K) int {
+	sm.mu.Lock()
+	deleted := 0
+	for _, key := range keys {
+		if _, exists := sm.deleteLocked(key); exists {
+			deleted++
+		}
+	}
+	sm.mu.Unlock()
+
+	if deleted > 0 && sm.config.AutoShrinkEnabled {
+		sm.TryShrink()
+	}
+	return deleted
+}
+
+// deleteLocked removes the key and returns the removed value.
+// Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) deleteLocked(key K) (V, bool) {
+	value, exists := sm.data[key]
 	if exists {
 		delete(sm.data, key)
+		sm.verifyLocked(key, value)
+		delete(sm.hashes, key)
+		sm.interner.release(value)
 		sm.deletedCount.Add(1)
+		if sm.growth != nil {
+			sm.growth.record(time.Now(), -1)
+		}
+		if sm.migration != nil {
+			delete(sm.migration.data, key)
+		}
+		sm.notifySpaceFreedLocked()
+		var zero V
+		sm.emitChange(ChangeDelete, key, zero)
+		delete(sm.generations, key)
 	}
+	return value, exists
+}
+
+// replaceData swaps the underlying map and resets the counters to match it.
+// sizeHint is the number of entries data was allocated for or has held.
+// Attached sinks receive the difference between the old and new contents.
+// Reports whether the map reached MaxMapSize.
+func (sm *ShrinkableMap[K, V]) replaceData(data map[K]V, sizeHint int64) (needsShrink bool) {
+	sm.mu.Lock()
+	emit := len(sm.sinks) > 0 || len(sm.watchers) > 0 || sm.history != nil
+	if emit {
+		var zero V
+		for k := range sm.data {
+			if _, exists := data[k]; !exists {
+				sm.emitChange(ChangeDelete, k, zero)
+			}
+		}
+	}
+	sm.renumberLocked(data)
+	if emit {
+		for k, v := range data {
+			sm.emitChange(ChangeSet, k, v)
+		}
+	}
+	sm.internAllLocked(data)
+	// An incremental shrink in progress would copy stale contents
+	sm.migration = nil
+	sm.notifySpaceFreedLocked()
+	sm.rehashLocked(data, false)
+	sm.data = data
+	sm.itemCount.Store(int64(len(data)))
+	sm.deletedCount.Store(0)
+	sm.sizeHint.Store(max(sizeHint, int64(len(data))))
+	sm.updateMetrics(int64(len(data)))
 	sm.mu.Unlock()
+	return sm.config.MaxMapSize > 0 && len(data) >= sm.config.MaxMapSize
+}
 
-	if exists && sm.config.AutoShrinkEnabled {
-		sm.TryShrink()
+// Name returns the name set with Config.Name
+func (sm *ShrinkableMap[K, V]) Name() string {
+	return sm.config.Name
+}
+
+// Labels returns a copy of the label pairs set with Config.Labels
+func (sm *ShrinkableMap[K, V]) Labels() map[string]string {
+	return copyLabels(sm.config.Labels)
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v
 	}
-	return exists
+	return result
 }
 
-// Len returns the current number of items in the map
+// Len returns the current number of items in the map; it is an alias of Size
 func (sm *ShrinkableMap[K, V]) Len() int64 {
+	return sm.Size()
+}
+
+// Size returns the number of live entries
+func (sm *ShrinkableMap[K, V]) Size() int64 {
 	return sm.itemCount.Load() - sm.deletedCount.Load()
 }
 
+// DeletedPending returns the number of entries deleted since the last shrink.
+// Go maps keep the space of deleted entries until they are copied, so this is
+// the garbage the next shrink would reclaim.
+func (sm *ShrinkableMap[K, V]) DeletedPending() int64 {
+	return sm.deletedCount.Load()
+}
+
+// accountInsertLocked updates metrics and the size hint for a new key unless
+// Config.MinimalAccounting is set. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) accountInsertLocked() {
+	if sm.config.MinimalAccounting {
+		return
+	}
+	sm.updateMetrics(1)
+	sm.trackSizeLocked()
+}
+
 func (sm *ShrinkableMap[K, V]) updateMetrics(processedItems int64) {
 	currentSize := sm.itemCount.Load()
 	if currentSize > int64(atomic.LoadInt32(&sm.metrics.peakSize)) {
@@ -141,6 +463,7 @@ func (sm *ShrinkableMap[K, V]) GetMetrics() Metrics {
 	return Metrics{
 		totalShrinks:        sm.metrics.totalShrinks,
 		lastShrinkDuration:  sm.metrics.lastShrinkDuration,
+		lastShrinkItems:     sm.metrics.lastShrinkItems,
 		totalItemsProcessed: sm.metrics.totalItemsProcessed,
 		peakSize:            sm.metrics.peakSize,
 		shrinkPanics:        sm.metrics.shrinkPanics,
@@ -148,105 +471,221 @@ func (sm *ShrinkableMap[K, V]) GetMetrics() Metrics {
 		lastError:           sm.metrics.lastError,
 		errorHistory:        sm.metrics.errorHistory,
 		totalErrors:         sm.metrics.totalErrors,
+		errorsByCode:        maps.Clone(sm.metrics.errorsByCode),
+		oversizedValues:     sm.metrics.oversizedValues,
+		invalidReads:        sm.metrics.invalidReads,
+		evictions:           sm.metrics.evictions,
+		skippedForGrowth:    sm.metrics.skippedForGrowth,
 	}
 }
 
-// shouldShrink determines if the map should be shrunk based on current conditions
-func (sm *ShrinkableMap[K, V]) shouldShrink() bool {
-	itemCount := sm.itemCount.Load()
-	if itemCount == 0 {
-		return false
-	}
-
-	deletedCount := sm.deletedCount.Load()
-	deletedRatio := float64(deletedCount) / float64(itemCount)
-
-	lastShrink := sm.lastShrinkTime.Load().(time.Time)
-	timeToShrink := time.Since(lastShrink) >= sm.config.MinShrinkInterval
-
-	return deletedRatio >= sm.config.ShrinkRatio && timeToShrink
+// shrink creates a new map and copies non-deleted items to it.
+// The outcome is recorded on top of the evaluated decision d.
+func (sm *ShrinkableMap[K, V]) shrink(d Decision) bool {
+	shrunk, _ := sm.shrinkWith(d, ShrinkOptions{})
+	return shrunk
 }
 
-// shrink creates a new map and copies non-deleted items to it
-func (sm *ShrinkableMap[K, V]) shrink() bool {
+// shrinkWith shrinks the map as configured by opts
+func (sm *ShrinkableMap[K, V]) shrinkWith(d Decision, opts ShrinkOptions) (bool, error) {
 	// Prevent concurrent shrink operations
 	if !sm.shrinking.CompareAndSwap(false, true) {
-		return false
+		sm.recordDecision(d, DecisionAlreadyShrinking)
+		return false, nil
 	}
 	defer sm.shrinking.Store(false)
+	defer sm.finishOp("shrink", sm.startOp())
 
 	startTime := time.Now()
 
 	// Calculate new size
 	currentLen := sm.Len()
 	if currentLen == 0 {
-		return false
+		sm.recordDecision(d, DecisionEmpty)
+		return false, nil
+	}
+	newSize := sm.shrinkTargetSize(currentLen)
+	if opts.TargetCapacity > 0 {
+		newSize = max(opts.TargetCapacity, int(currentLen))
 	}
 
-	newSize := int(float64(currentLen) * sm.config.CapacityGrowthFactor)
-	if newSize < sm.config.InitialCapacity {
-		newSize = sm.config.InitialCapacity
+	// A full copy cannot be interrupted, so it is skipped up front if the
+	// last shrink's copy rate says it will not fit the budget
+	if opts.Budget > 0 && opts.Strategy == ShrinkFull && sm.estimateCopyTime(currentLen) > opts.Budget {
+		sm.recordDecision(d, DecisionBudgetExceeded)
+		return false, ErrShrinkBudgetExceeded
 	}
 
-	sm.mu.Lock()
-	// Create and populate new map
-	newMap := make(map[K]V, newSize)
-	for k, v := range sm.data {
-		newMap[k] = v
+	shrinkLimiter.acquire()
+	defer shrinkLimiter.release()
+
+	var reclaimed, newCount int64
+	if opts.Strategy == ShrinkIncremental {
+		var err error
+		reclaimed, newCount, err = sm.shrinkIncremental(newSize, opts, startTime)
+		if err != nil {
+			if errors.Is(err, errShrinkSuperseded) {
+				sm.recordDecision(d, DecisionSuperseded)
+				return false, nil
+			}
+			sm.recordDecision(d, DecisionBudgetExceeded)
+			return false, err
+		}
+	} else {
+		sm.mu.Lock()
+		reclaimed = sm.deletedCount.Load()
+		// Create and populate new map
+		newMap := make(map[K]V, newSize)
+		if sm.keys != nil {
+			// Copy live keys into a fresh arena so blocks of deleted keys are freed
+			keys := sm.keys.renew()
+			for k, v := range sm.data {
+				newMap[keys.intern(k)] = v
+			}
+			sm.keys = keys
+		} else {
+			for k, v := range sm.data {
+				newMap[k] = v
+			}
+		}
+		newCount = sm.installLocked(newMap, newSize)
+		sm.mu.Unlock()
+	}
+
+	sm.updateShrinkMetrics(startTime, newCount)
+	sm.lastShrinkTime.Store(time.Now())
+	sm.recordDecision(d, DecisionShrunk)
+	sm.observeShrink(startTime, d.Forced, newCount, reclaimed, newSize)
+
+	// FreeOSMemory forces a full GC, so it is reserved for large shrinks
+	if sm.config.ReleaseOSMemoryAfterShrink && reclaimed >= int64(sm.config.ReleaseOSMemoryThreshold) {
+		freeOSMemory()
 	}
-	// Update map with new data
+
+	return true, nil
+}
+
+// installLocked replaces the data with the shrunk map allocated for newSize
+// entries and returns its length. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) installLocked(newMap map[K]V, newSize int) int64 {
+	sm.rehashLocked(newMap, true)
+	sm.compactGenerationsLocked(newMap)
 	sm.data = newMap
 	newCount := int64(len(newMap))
 	sm.itemCount.Store(newCount)
 	sm.deletedCount.Store(0)
-	sm.mu.Unlock()
-
-	sm.updateShrinkMetrics(startTime)
-	sm.lastShrinkTime.Store(time.Now())
+	sm.sizeHint.Store(max(int64(newSize), newCount))
+	return newCount
+}
 
-	return true
+// requestShrink runs TryShrink in the background. With Config.CoalesceShrinks
+// a request made while another is still pending is merged into it, so at most
+// one request goroutine waits at a time.
+func (sm *ShrinkableMap[K, V]) requestShrink() {
+	if !sm.config.CoalesceShrinks {
+		go sm.TryShrink()
+		return
+	}
+	if sm.shrinkPending.CompareAndSwap(false, true) {
+		go func() {
+			// Clear the flag first so requests arriving during the shrink check are not lost
+			sm.shrinkPending.Store(false)
+			sm.TryShrink()
+		}()
+	}
 }
 
 // TryShrink attempts to shrink the map if conditions are met
+// The outcome is reported by ShrinkDecision
 func (sm *ShrinkableMap[K, V]) TryShrink() bool {
-	if sm.shouldShrink() {
-		return sm.shrink()
+	d := sm.evaluateShrink(false)
+	if d.Reason != DecisionShrunk {
+		if d.Reason == DecisionGrowing {
+			sm.metrics.recordSkippedForGrowth()
+		}
+		sm.recordDecision(d, d.Reason)
+		return false
 	}
-	return false
+	return sm.shrink(d)
 }
 
 // ForceShrink immediately shrinks the map regardless of conditions
 func (sm *ShrinkableMap[K, V]) ForceShrink() bool {
-	return sm.shrink()
+	return sm.shrink(sm.evaluateShrink(true))
 }
 
-// shrinkLoop runs the periodic shrink check with panic recovery
+// shrinkLoop runs the periodic shrink check, restarting it after panics as
+// allowed by Config.RestartShrinkLoopOnPanic
 func (sm *ShrinkableMap[K, V]) shrinkLoop(ctx context.Context) {
+	backoff := sm.config.ShrinkLoopRestartBackoff
+	for restarts := 0; ; restarts++ {
+		panicked := sm.runShrinkLoop(ctx)
+		if !panicked || !sm.config.RestartShrinkLoopOnPanic ||
+			(sm.config.ShrinkLoopMaxRestarts > 0 && restarts >= sm.config.ShrinkLoopMaxRestarts) {
+			sm.shrinkStatus.exit(panicked)
+			return
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			sm.shrinkStatus.exit(false)
+			return
+		case <-timer.C:
+		}
+		sm.shrinkStatus.restart()
+		backoff = min(backoff*2, sm.config.ShrinkInterval)
+	}
+}
+
+// runShrinkLoop runs the periodic shrink check until ctx is canceled, the
+// Config.ShrinkTrigger channel is closed or a panic occurs, which is
+// recorded in the metrics
+func (sm *ShrinkableMap[K, V]) runShrinkLoop(ctx context.Context) (panicked bool) {
 	defer func() {
 		if r := recover(); r != nil {
-			sm.metrics.mu.Lock()
-			sm.metrics.shrinkPanics++
-			sm.metrics.lastPanicTime = time.Now()
-			sm.metrics.mu.Unlock()
+			sm.metrics.RecordPanic(r, string(debug.Stack()))
+			panicked = true
 		}
 	}()
 
-	ticker := time.NewTicker(sm.config.ShrinkInterval)
-	defer ticker.Stop()
+	// Only one of ticks and trigger is set; receiving from the other blocks forever
+	var ticks <-chan time.Time
+	trigger := sm.config.ShrinkTrigger
+	if trigger == nil {
+		ticker := time.NewTicker(sm.config.ShrinkInterval)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
 
 	for {
 		select {
 		case <-ctx.Done():
-			return
-		case <-ticker.C:
-			sm.TryShrink()
+			return false
+		case <-ticks:
+			sm.shrinkTick()
+		case _, ok := <-trigger:
+			if !ok {
+				return false
+			}
+			sm.shrinkTick()
 		}
 	}
 }
 
-func (sm *ShrinkableMap[K, V]) updateShrinkMetrics(startTime time.Time) {
+// shrinkTick runs one shrink check of the auto-shrink goroutine
+func (sm *ShrinkableMap[K, V]) shrinkTick() {
+	sm.shrinkStatus.tick()
+	if sm.TryShrink() {
+		sm.shrinkStatus.active()
+	}
+}
+
+func (sm *ShrinkableMap[K, V]) updateShrinkMetrics(startTime time.Time, items int64) {
 	sm.metrics.mu.Lock()
 	sm.metrics.totalShrinks++
 	sm.metrics.lastShrinkDuration = time.Since(startTime)
+	sm.metrics.lastShrinkItems = items
 	sm.metrics.mu.Unlock()
 }
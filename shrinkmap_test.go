@@ -1,9 +1,11 @@
 package shrinkmap
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"runtime"
+	"runtime/debug"
 	"strings"
 	"sync"
 	"testing"
@@ -76,6 +78,27 @@ func TestBasicOperations(t *testing.T) {
 			t.Errorf("Expected length 1, got %d", l)
 		}
 	})
+
+	t.Run("Size and DeletedPending", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+
+		sm.Set("test1", 100)
+		sm.Set("test2", 200)
+		sm.Delete("test1")
+
+		if sm.Size() != 1 || sm.Len() != sm.Size() {
+			t.Errorf("Expected size 1, got Size %d, Len %d", sm.Size(), sm.Len())
+		}
+		if sm.DeletedPending() != 1 {
+			t.Errorf("Expected 1 pending deletion, got %d", sm.DeletedPending())
+		}
+
+		sm.ForceShrink()
+		if sm.DeletedPending() != 0 || sm.Size() != 1 {
+			t.Errorf("Expected no pending deletions after shrink, got %d", sm.DeletedPending())
+		}
+	})
 }
 
 // TestShrinking tests the shrinking functionality
@@ -282,25 +305,34 @@ func BenchmarkMapComparison(b *testing.B) {
 		})
 	})
 
-	b.Run("ShrinkableMap", func(b *testing.B) {
-		sm := New[int, int](DefaultConfig())
-		b.ResetTimer()
-		b.RunParallel(func(pb *testing.PB) {
-			counter := 0
-			for pb.Next() {
-				key := counter % itemCount
-				switch counter % 3 {
-				case 0:
-					sm.Set(key, counter)
-				case 1:
-					sm.Get(key)
-				case 2:
-					sm.Delete(key)
+	for _, bc := range []struct {
+		name   string
+		config Config
+	}{
+		{"ShrinkableMap", DefaultConfig()},
+		{"ShrinkableMap Minimal Accounting", DefaultConfig().WithMinimalAccounting(true)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			sm := New[int, int](bc.config)
+			defer sm.Stop()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				counter := 0
+				for pb.Next() {
+					key := counter % itemCount
+					switch counter % 3 {
+					case 0:
+						sm.Set(key, counter)
+					case 1:
+						sm.Get(key)
+					case 2:
+						sm.Delete(key)
+					}
+					counter++
 				}
-				counter++
-			}
+			})
 		})
-	})
+	}
 }
 
 // TestErrorMetrics tests the error tracking functionality
@@ -505,3 +537,274 @@ func getStackTrace() string {
 	n := runtime.Stack(buf, false)
 	return string(buf[:n])
 }
+
+// TestTryOperations tests the non-blocking Try* operations
+func TestTryOperations(t *testing.T) {
+	t.Run("Unlocked Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		if err := sm.TrySet("a", 1); err != nil {
+			t.Fatalf("TrySet failed: %v", err)
+		}
+		val, exists, err := sm.TryGet("a")
+		if err != nil || !exists || val != 1 {
+			t.Errorf("Expected a=1, got %v, exists=%v, err=%v", val, exists, err)
+		}
+		if _, exists, _ := sm.TryGet("missing"); exists {
+			t.Error("Expected false for non-existent key")
+		}
+	})
+
+	t.Run("Locked Map", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sm.Set("a", 1)
+
+		sm.mu.Lock()
+		setErr := sm.TrySet("b", 2)
+		_, _, getErr := sm.TryGet("a")
+		sm.mu.Unlock()
+
+		if setErr != ErrWouldBlock {
+			t.Errorf("Expected ErrWouldBlock from TrySet, got %v", setErr)
+		}
+		if getErr != ErrWouldBlock {
+			t.Errorf("Expected ErrWouldBlock from TryGet, got %v", getErr)
+		}
+		if _, exists := sm.Get("b"); exists {
+			t.Error("Blocked TrySet should not store the value")
+		}
+
+		sm.mu.RLock()
+		_, exists, err := sm.TryGet("a")
+		sm.mu.RUnlock()
+		if err != nil || !exists {
+			t.Errorf("TryGet should succeed alongside other readers, err=%v", err)
+		}
+	})
+}
+
+// TestDefaultValues tests GetOrDefault and GetOrElse
+func TestDefaultValues(t *testing.T) {
+	sm := New[string, int](DefaultConfig())
+	defer sm.Stop()
+	sm.Set("present", 5)
+
+	if v := sm.GetOrDefault("present", 1); v != 5 {
+		t.Errorf("Expected stored value 5, got %d", v)
+	}
+	if v := sm.GetOrDefault("missing", 1); v != 1 {
+		t.Errorf("Expected default 1, got %d", v)
+	}
+
+	calls := 0
+	fallback := func(k string) int {
+		calls++
+		return len(k)
+	}
+	if v := sm.GetOrElse("present", fallback); v != 5 || calls != 0 {
+		t.Errorf("Expected stored value without calling fallback, got %d (calls=%d)", v, calls)
+	}
+	if v := sm.GetOrElse("missing", fallback); v != 7 || calls != 1 {
+		t.Errorf("Expected computed default 7, got %d (calls=%d)", v, calls)
+	}
+
+	if sm.Contains("missing") || sm.Len() != 1 {
+		t.Error("Defaults should not be stored in the map")
+	}
+}
+
+// TestMustOperations tests the panicking MustGet and MustSet variants
+func TestMustOperations(t *testing.T) {
+	expectPanic := func(t *testing.T, target error, fn func()) {
+		t.Helper()
+		defer func() {
+			r := recover()
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, target) {
+				t.Errorf("Expected panic with %v, got %v", target, r)
+			}
+		}()
+		fn()
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		sm.MustSet("a", 1)
+		if v := sm.MustGet("a"); v != 1 {
+			t.Errorf("Expected 1, got %d", v)
+		}
+	})
+
+	t.Run("MustGet Missing Key", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		expectPanic(t, ErrKeyNotFound, func() { sm.MustGet("missing") })
+	})
+
+	t.Run("MustSet Rejected Value", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithMaxValueBytes(1, nil))
+		defer sm.Stop()
+
+		expectPanic(t, ErrValueTooLarge, func() { sm.MustSet("a", "too long") })
+	})
+}
+
+// TestWritesAfterStop tests that Stop only ends background work and the map
+// stays usable
+func TestWritesAfterStop(t *testing.T) {
+	sm := New[string, int](DefaultConfig())
+	sm.Set("a", 1)
+	sm.Stop()
+
+	sm.Set("b", 2)
+	if err := sm.TrySet("c", 3); err != nil {
+		t.Errorf("Expected TrySet to succeed, got %v", err)
+	}
+	batch := BatchOperations[string, int]{
+		Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "d", Value: 4}},
+	}
+	if err := sm.ApplyBatch(batch); err != nil {
+		t.Errorf("Expected ApplyBatch to succeed, got %v", err)
+	}
+	if v, exists := sm.Get("a"); !exists || v != 1 || sm.Len() != 4 {
+		t.Errorf("Expected reads and writes to keep working after Stop, got len=%d", sm.Len())
+	}
+}
+
+func TestReleaseOSMemoryAfterShrink(t *testing.T) {
+	released := 0
+	freeOSMemory = func() { released++ }
+	defer func() { freeOSMemory = debug.FreeOSMemory }()
+
+	newMap := func(config Config) *ShrinkableMap[int, int] {
+		sm := New[int, int](config.WithAutoShrinkEnabled(false))
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		return sm
+	}
+
+	t.Run("Above Threshold", func(t *testing.T) {
+		released = 0
+		sm := newMap(DefaultConfig().WithReleaseOSMemoryAfterShrink(true, 50))
+		defer sm.Stop()
+		for i := 0; i < 60; i++ {
+			sm.Delete(i)
+		}
+		sm.ForceShrink()
+		if released != 1 {
+			t.Errorf("Expected memory to be released once, got %d", released)
+		}
+	})
+
+	t.Run("Below Threshold", func(t *testing.T) {
+		released = 0
+		sm := newMap(DefaultConfig().WithReleaseOSMemoryAfterShrink(true, 50))
+		defer sm.Stop()
+		for i := 0; i < 10; i++ {
+			sm.Delete(i)
+		}
+		sm.ForceShrink()
+		if released != 0 {
+			t.Errorf("Expected no release below the threshold, got %d", released)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		released = 0
+		sm := newMap(DefaultConfig())
+		defer sm.Stop()
+		for i := 0; i < 100; i++ {
+			sm.Delete(i)
+		}
+		sm.Set(0, 0)
+		sm.ForceShrink()
+		if released != 0 {
+			t.Errorf("Expected no release when disabled, got %d", released)
+		}
+	})
+}
+
+func TestNameAndLabels(t *testing.T) {
+	base := DefaultConfig().WithLabel("component", "auth")
+	config := base.WithName("sessions").WithLabel("tier", "hot")
+
+	if len(base.Labels) != 1 {
+		t.Errorf("WithLabel must not modify the original config, got %v", base.Labels)
+	}
+
+	sm := New[string, int](config)
+	defer sm.Stop()
+
+	if sm.Name() != "sessions" {
+		t.Errorf("Expected name sessions, got %q", sm.Name())
+	}
+	labels := sm.Labels()
+	if len(labels) != 2 || labels["component"] != "auth" || labels["tier"] != "hot" {
+		t.Errorf("Unexpected labels: %v", labels)
+	}
+	labels["tier"] = "cold"
+	if sm.Labels()["tier"] != "hot" {
+		t.Error("Labels must return a copy")
+	}
+
+	if err := DefaultConfig().WithLabel("", "x").Validate(); err == nil {
+		t.Error("Expected error for empty label name")
+	}
+}
+
+func TestMinimalAccounting(t *testing.T) {
+	config := DefaultConfig().WithMinimalAccounting(true)
+	config.AutoShrinkEnabled = false
+	sm := New[int, int](config)
+	defer sm.Stop()
+
+	for i := 0; i < 100; i++ {
+		sm.Set(i, i)
+	}
+	for i := 0; i < 50; i++ {
+		sm.Delete(i)
+	}
+
+	metrics := sm.GetMetrics()
+	if metrics.TotalItemsProcessed() != 0 || metrics.PeakSize() != 0 {
+		t.Errorf("Expected per-op metrics to be skipped, got processed=%d peak=%d",
+			metrics.TotalItemsProcessed(), metrics.PeakSize())
+	}
+	if sm.Len() != 50 {
+		t.Errorf("Expected length 50, got %d", sm.Len())
+	}
+	if !sm.ForceShrink() || sm.Len() != 50 {
+		t.Errorf("Expected shrink to work with minimal accounting, len=%d", sm.Len())
+	}
+}
+
+func TestCoalesceShrinks(t *testing.T) {
+	sm := New[int, int](DefaultConfig())
+	defer sm.Stop()
+
+	// While a request is pending, further requests start no goroutines
+	sm.shrinkPending.Store(true)
+	before := runtime.NumGoroutine()
+	for i := 0; i < 100; i++ {
+		sm.ApplyBatch(BatchOperations[int, int]{Operations: []BatchOperation[int, int]{{Type: BatchSet, Key: i, Value: i}}})
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Errorf("Expected coalesced requests to start no goroutines, got %d more", after-before)
+	}
+
+	sm.shrinkPending.Store(false)
+	sm.requestShrink()
+	deadline := time.Now().Add(time.Second)
+	for sm.shrinkPending.Load() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if sm.shrinkPending.Load() {
+		t.Error("Expected pending request to be consumed")
+	}
+}
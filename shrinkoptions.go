@@ -0,0 +1,161 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrShrinkBudgetExceeded is returned by ForceShrinkWithOptions when the
+// shrink cannot complete within ShrinkOptions.Budget. The map is left unchanged.
+var ErrShrinkBudgetExceeded = errors.New("shrinkmap: shrink time budget exceeded")
+
+// errShrinkSuperseded reports that the data was replaced during an incremental shrink
+var errShrinkSuperseded = errors.New("shrinkmap: shrink superseded by replaced data")
+
+// ShrinkStrategy selects how a shrink copies the live entries
+type ShrinkStrategy int
+
+const (
+	// ShrinkFull copies every entry while holding the write lock, like ForceShrink
+	ShrinkFull ShrinkStrategy = iota
+	// ShrinkIncremental copies entries in chunks, releasing the write lock
+	// between chunks so other operations are delayed by one chunk at most.
+	// Writes made during the copy are applied to both maps.
+	ShrinkIncremental
+)
+
+// defaultShrinkChunkSize is the number of entries an incremental shrink copies per lock hold
+const defaultShrinkChunkSize = 1024
+
+// ShrinkOptions overrides the configured shrink behavior for one ForceShrinkWithOptions call
+type ShrinkOptions struct {
+	// Capacity to allocate for the new map; 0 uses Config.CapacityGrowthFactor.
+	// A capacity below the number of entries is raised to it.
+	TargetCapacity int
+
+	// How the entries are copied
+	Strategy ShrinkStrategy
+
+	// Entries copied per lock hold by ShrinkIncremental; 0 uses 1024
+	ChunkSize int
+
+	// Maximum time the shrink may take; 0 means unlimited. An incremental
+	// shrink is abandoned when the budget runs out. A full shrink cannot be
+	// interrupted, so it is skipped if the last shrink's copy rate predicts it
+	// will not fit.
+	Budget time.Duration
+}
+
+// Validate checks if the options are valid
+func (o ShrinkOptions) Validate() error {
+	if o.TargetCapacity < 0 {
+		return fmt.Errorf("target capacity must be non-negative")
+	}
+	if o.Strategy != ShrinkFull && o.Strategy != ShrinkIncremental {
+		return fmt.Errorf("unknown shrink strategy %d", o.Strategy)
+	}
+	if o.ChunkSize < 0 {
+		return fmt.Errorf("chunk size must be non-negative")
+	}
+	if o.Budget < 0 {
+		return fmt.Errorf("budget must be non-negative")
+	}
+	return nil
+}
+
+// ForceShrinkWithOptions immediately shrinks the map regardless of conditions,
+// using opts instead of the configured capacity and copy strategy. It reports
+// whether the map was shrunk; like ForceShrink it returns false without an
+// error if the map is empty or another shrink is in progress.
+func (sm *ShrinkableMap[K, V]) ForceShrinkWithOptions(opts ShrinkOptions) (bool, error) {
+	if err := opts.Validate(); err != nil {
+		return false, err
+	}
+	return sm.shrinkWith(sm.evaluateShrink(true), opts)
+}
+
+// migration holds the map being filled by an incremental shrink
+type migration[K comparable, V any] struct {
+	data map[K]V
+	keys *keyArena[K]
+}
+
+// set stores the pair in the new map, interning the key in the new arena
+func (m *migration[K, V]) set(key K, value V) {
+	if _, exists := m.data[key]; !exists {
+		key = m.keys.intern(key)
+	}
+	m.data[key] = value
+}
+
+// shrinkIncremental copies the live entries into a map allocated for newSize
+// entries in chunks and installs it. It returns the number of deleted entries
+// reclaimed and the new length.
+func (sm *ShrinkableMap[K, V]) shrinkIncremental(newSize int, opts ShrinkOptions, start time.Time) (int64, int64, error) {
+	chunk := opts.ChunkSize
+	if chunk == 0 {
+		chunk = defaultShrinkChunkSize
+	}
+
+	// Go map iteration cannot resume after the lock is released, so the
+	// keys to copy are collected up front
+	sm.mu.Lock()
+	m := &migration[K, V]{data: make(map[K]V, newSize), keys: sm.keys.renew()}
+	sm.migration = m
+	pending := make([]K, 0, len(sm.data))
+	for k := range sm.data {
+		pending = append(pending, k)
+	}
+	sm.mu.Unlock()
+
+	for len(pending) > 0 {
+		if opts.Budget > 0 && time.Since(start) > opts.Budget {
+			sm.mu.Lock()
+			if sm.migration == m {
+				sm.migration = nil
+			}
+			sm.mu.Unlock()
+			return 0, 0, ErrShrinkBudgetExceeded
+		}
+
+		n := min(chunk, len(pending))
+		sm.mu.Lock()
+		if sm.migration != m {
+			sm.mu.Unlock()
+			return 0, 0, errShrinkSuperseded
+		}
+		// Entries written since the keys were collected are already in
+		// the new map; deleted ones are skipped
+		for _, k := range pending[:n] {
+			if v, exists := sm.data[k]; exists {
+				m.set(k, v)
+			}
+		}
+		sm.mu.Unlock()
+		pending = pending[n:]
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.migration != m {
+		return 0, 0, errShrinkSuperseded
+	}
+	sm.migration = nil
+	reclaimed := sm.deletedCount.Load()
+	if m.keys != nil {
+		sm.keys = m.keys
+	}
+	return reclaimed, sm.installLocked(m.data, newSize), nil
+}
+
+// estimateCopyTime extrapolates the time to copy n entries from the last
+// shrink, or returns zero without shrink history
+func (sm *ShrinkableMap[K, V]) estimateCopyTime(n int64) time.Duration {
+	metrics := sm.GetMetrics()
+	copied := metrics.LastShrinkItems()
+	if copied == 0 {
+		return 0
+	}
+	return time.Duration(float64(metrics.LastShrinkDuration()) / float64(copied) * float64(n))
+}
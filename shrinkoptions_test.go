@@ -0,0 +1,130 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestForceShrinkWithOptions(t *testing.T) {
+	config := DefaultConfig().WithAutoShrinkEnabled(false).WithInitialCapacity(1)
+	fill := func(sm *ShrinkableMap[int, int], n, deleted int) {
+		for i := 0; i < n; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < deleted; i++ {
+			sm.Delete(i)
+		}
+	}
+
+	t.Run("Target Capacity", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		fill(sm, 1000, 900)
+
+		shrunk, err := sm.ForceShrinkWithOptions(ShrinkOptions{TargetCapacity: 5000})
+		if !shrunk || err != nil {
+			t.Fatalf("Expected shrink, got %v, %v", shrunk, err)
+		}
+		if stats := sm.Stats(); stats.PeakLen != 5000 || stats.Len != 100 {
+			t.Errorf("Unexpected stats after shrink: %+v", stats)
+		}
+
+		// A target below the number of entries is raised to it
+		sm.ForceShrinkWithOptions(ShrinkOptions{TargetCapacity: 10})
+		if stats := sm.Stats(); stats.PeakLen != 100 {
+			t.Errorf("Expected peak 100, got %d", stats.PeakLen)
+		}
+	})
+
+	t.Run("Incremental With Concurrent Writes", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		fill(sm, 20000, 10000)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 10000; i < 11000; i++ {
+				sm.Delete(i)
+				sm.Set(i+20000, i)
+				sm.Set(i+1000, -i)
+			}
+		}()
+		shrunk, err := sm.ForceShrinkWithOptions(ShrinkOptions{Strategy: ShrinkIncremental, ChunkSize: 16})
+		wg.Wait()
+		if !shrunk || err != nil {
+			t.Fatalf("Expected shrink, got %v, %v", shrunk, err)
+		}
+
+		expected := make(map[int]int)
+		for i := 11000; i < 20000; i++ {
+			expected[i] = i
+		}
+		for i := 10000; i < 11000; i++ {
+			expected[i+20000] = i
+			expected[i+1000] = -i
+		}
+		snapshot := sm.Snapshot()
+		if len(snapshot) != len(expected) || sm.Len() != int64(len(expected)) {
+			t.Fatalf("Expected %d entries, got %d (Len %d)", len(expected), len(snapshot), sm.Len())
+		}
+		for _, kv := range snapshot {
+			if v, ok := expected[kv.Key]; !ok || v != kv.Value {
+				t.Errorf("Unexpected entry %d=%d", kv.Key, kv.Value)
+			}
+		}
+	})
+
+	t.Run("Budget Exceeded", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+		fill(sm, 100000, 50000)
+		before := sm.Snapshot()
+
+		_, err := sm.ForceShrinkWithOptions(ShrinkOptions{Strategy: ShrinkIncremental, ChunkSize: 1, Budget: time.Nanosecond})
+		if !errors.Is(err, ErrShrinkBudgetExceeded) {
+			t.Fatalf("Expected ErrShrinkBudgetExceeded, got %v", err)
+		}
+		if d := sm.ShrinkDecision(); d.Reason != DecisionBudgetExceeded {
+			t.Errorf("Expected %v, got %v", DecisionBudgetExceeded, d.Reason)
+		}
+		if sm.Stats().DeletedSinceShrink != 50000 || len(sm.Snapshot()) != len(before) {
+			t.Error("Expected the map unchanged after an abandoned shrink")
+		}
+
+		// Writes after the abandoned shrink must not reach its discarded map
+		sm.Set(-1, -1)
+		if sm.migration != nil {
+			t.Error("Expected no migration after an abandoned shrink")
+		}
+
+		// With shrink history, a full shrink predicted to overrun is skipped
+		sm.ForceShrink()
+		fill(sm, 100000, 0)
+		metrics := sm.GetMetrics()
+		if metrics.LastShrinkDuration() > 0 {
+			if _, err := sm.ForceShrinkWithOptions(ShrinkOptions{Budget: time.Nanosecond}); !errors.Is(err, ErrShrinkBudgetExceeded) {
+				t.Errorf("Expected ErrShrinkBudgetExceeded for full shrink, got %v", err)
+			}
+		}
+	})
+
+	t.Run("Invalid Options", func(t *testing.T) {
+		sm := New[int, int](config)
+		defer sm.Stop()
+
+		for _, opts := range []ShrinkOptions{
+			{TargetCapacity: -1},
+			{Strategy: ShrinkStrategy(7)},
+			{ChunkSize: -1},
+			{Budget: -time.Second},
+		} {
+			if _, err := sm.ForceShrinkWithOptions(opts); err == nil {
+				t.Errorf("Expected error for %+v", opts)
+			}
+		}
+	})
+}
@@ -0,0 +1,351 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+)
+
+// ChangeType identifies the kind of mutation carried by a ChangeEvent
+type ChangeType int
+
+const (
+	ChangeSet ChangeType = iota
+	ChangeDelete
+)
+
+// String returns a human readable name for the change type
+func (t ChangeType) String() string {
+	switch t {
+	case ChangeSet:
+		return "set"
+	case ChangeDelete:
+		return "delete"
+	default:
+		return fmt.Sprintf("ChangeType(%d)", int(t))
+	}
+}
+
+// ChangeEvent describes a single mutation applied to the map
+type ChangeEvent[K comparable, V any] struct {
+	Type      ChangeType
+	Key       K
+	Value     V // zero value for deletions
+	Timestamp time.Time
+
+	// Generation of the written or deleted entry under
+	// Config.TrackGenerations, see EntryInfo; 0 otherwise
+	Generation uint64
+}
+
+// Sink receives batches of change events from a map.
+// Implementations forward events to external systems such as Kafka, NATS or webhooks.
+// Publish may be retried with the same batch, so implementations should be idempotent.
+type Sink[K comparable, V any] interface {
+	Publish(ctx context.Context, events []ChangeEvent[K, V]) error
+}
+
+// SinkFunc adapts an ordinary function to the Sink interface
+type SinkFunc[K comparable, V any] func(ctx context.Context, events []ChangeEvent[K, V]) error
+
+// Publish calls f(ctx, events)
+func (f SinkFunc[K, V]) Publish(ctx context.Context, events []ChangeEvent[K, V]) error {
+	return f(ctx, events)
+}
+
+// ErrSinkBufferFull is recorded when a change event is dropped because the sink buffer is full
+var ErrSinkBufferFull = errors.New("shrinkmap: sink buffer full, change event dropped")
+
+// ErrSinkPanicked is wrapped in the error recorded when a sink panics while
+// publishing. Sink panics are recorded as errors, not as shrink panics.
+var ErrSinkPanicked = errors.New("shrinkmap: sink panicked")
+
+// SinkConfig controls batching and retry behavior of a sink pump
+type SinkConfig struct {
+	// Maximum number of events delivered in a single Publish call
+	BatchSize int
+
+	// Maximum time events wait in the buffer before being flushed
+	FlushInterval time.Duration
+
+	// Number of buffered events before new events are dropped
+	BufferSize int
+
+	// Number of retries after a failed Publish (0 disables retrying)
+	MaxRetries int
+
+	// Delay before the first retry, doubled after every failed attempt
+	RetryBackoff time.Duration
+
+	// Upper bound for a single Publish call (0 means no timeout)
+	PublishTimeout time.Duration
+}
+
+// DefaultSinkConfig returns the default configuration for sink pumps
+func DefaultSinkConfig() SinkConfig {
+	return SinkConfig{
+		BatchSize:      100,
+		FlushInterval:  time.Second,
+		BufferSize:     10_000,
+		MaxRetries:     3,
+		RetryBackoff:   100 * time.Millisecond,
+		PublishTimeout: 10 * time.Second,
+	}
+}
+
+// Validate checks if the sink configuration is valid
+func (c SinkConfig) Validate() error {
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("sink batch size must be positive")
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("sink flush interval must be positive")
+	}
+	if c.BufferSize <= 0 {
+		return fmt.Errorf("sink buffer size must be positive")
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("sink max retries must be non-negative")
+	}
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("sink retry backoff must be non-negative")
+	}
+	if c.PublishTimeout < 0 {
+		return fmt.Errorf("sink publish timeout must be non-negative")
+	}
+	return nil
+}
+
+// SinkStats reports delivery statistics of an attached sink
+type SinkStats struct {
+	Published int64 // events successfully delivered
+	Failed    int64 // events given up on after all retries
+	Dropped   int64 // events dropped because the buffer was full
+	Retries   int64 // total number of retried Publish calls
+}
+
+// SinkHandle controls a sink attached to a map
+type SinkHandle struct {
+	detach    func()
+	published *atomic.Int64
+	failed    *atomic.Int64
+	dropped   *atomic.Int64
+	retries   *atomic.Int64
+}
+
+// Detach stops delivering events to the sink after flushing buffered events
+func (h *SinkHandle) Detach() {
+	h.detach()
+}
+
+// Stats returns the current delivery statistics of the sink
+func (h *SinkHandle) Stats() SinkStats {
+	return SinkStats{
+		Published: h.published.Load(),
+		Failed:    h.failed.Load(),
+		Dropped:   h.dropped.Load(),
+		Retries:   h.retries.Load(),
+	}
+}
+
+// sinkPump buffers change events and delivers them to a sink in batches
+type sinkPump[K comparable, V any] struct {
+	sink    Sink[K, V]
+	config  SinkConfig
+	metrics *Metrics
+	events  chan ChangeEvent[K, V]
+	done    chan struct{}
+	stopped chan struct{}
+	closed  atomic.Bool
+
+	published atomic.Int64
+	failed    atomic.Int64
+	dropped   atomic.Int64
+	retries   atomic.Int64
+}
+
+// AttachSink starts forwarding every mutation of the map to the given sink.
+// Events are buffered and published in batches by a background goroutine,
+// which is stopped by Detach on the returned handle or by Stop on the map.
+func (sm *ShrinkableMap[K, V]) AttachSink(sink Sink[K, V], config SinkConfig) (*SinkHandle, error) {
+	if sink == nil {
+		return nil, fmt.Errorf("sink must not be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	if sm.stopped.Load() {
+		return nil, fmt.Errorf("cannot attach sink to a stopped map")
+	}
+
+	p := &sinkPump[K, V]{
+		sink:    sink,
+		config:  config,
+		metrics: sm.metrics,
+		events:  make(chan ChangeEvent[K, V], config.BufferSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+
+	sm.mu.Lock()
+	sm.sinks = append(sm.sinks, p)
+	sm.mu.Unlock()
+
+	go p.run(sm.ctx)
+
+	return &SinkHandle{
+		detach: func() {
+			sm.mu.Lock()
+			for i, s := range sm.sinks {
+				if s == p {
+					sm.sinks = append(sm.sinks[:i:i], sm.sinks[i+1:]...)
+					break
+				}
+			}
+			sm.mu.Unlock()
+			p.close()
+		},
+		published: &p.published,
+		failed:    &p.failed,
+		dropped:   &p.dropped,
+		retries:   &p.retries,
+	}, nil
+}
+
+// emitChange forwards an event to the history, watchers and all attached sinks.
+// Must be called with sm.mu held so events are delivered in mutation order.
+func (sm *ShrinkableMap[K, V]) emitChange(typ ChangeType, key K, value V) {
+	if sm.history != nil {
+		sm.history.record(typ, key, value)
+	}
+	if len(sm.sinks) == 0 && len(sm.watchers) == 0 {
+		return
+	}
+	event := ChangeEvent[K, V]{Type: typ, Key: key, Value: value, Timestamp: time.Now(), Generation: sm.generations[key]}
+	sm.notifyWatchers(event)
+	for _, p := range sm.sinks {
+		p.enqueue(sm.copyEvent(event))
+	}
+}
+
+// copyEvent gives each watcher and sink its own copy of the value under
+// Config.CopyOnRead
+func (sm *ShrinkableMap[K, V]) copyEvent(event ChangeEvent[K, V]) ChangeEvent[K, V] {
+	if event.Type == ChangeSet {
+		event.Value = sm.copyOnRead(event.Value)
+	}
+	return event
+}
+
+func (p *sinkPump[K, V]) enqueue(event ChangeEvent[K, V]) {
+	select {
+	case p.events <- event:
+	default:
+		p.dropped.Add(1)
+		p.metrics.RecordError(ErrSinkBufferFull, "")
+	}
+}
+
+func (p *sinkPump[K, V]) close() {
+	if p.closed.CompareAndSwap(false, true) {
+		close(p.done)
+	}
+	<-p.stopped
+}
+
+// run collects events into batches and publishes them until the pump is closed
+func (p *sinkPump[K, V]) run(ctx context.Context) {
+	defer close(p.stopped)
+
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]ChangeEvent[K, V], 0, p.config.BatchSize)
+	flush := func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		p.deliver(ctx, batch)
+		batch = make([]ChangeEvent[K, V], 0, p.config.BatchSize)
+	}
+
+	for {
+		select {
+		case event := <-p.events:
+			batch = append(batch, event)
+			if len(batch) >= p.config.BatchSize {
+				flush(ctx)
+			}
+		case <-ticker.C:
+			flush(ctx)
+		case <-p.done:
+			p.drain(&batch, flush)
+			return
+		case <-ctx.Done():
+			p.drain(&batch, flush)
+			return
+		}
+	}
+}
+
+// drain flushes all buffered events before the pump exits
+func (p *sinkPump[K, V]) drain(batch *[]ChangeEvent[K, V], flush func(context.Context)) {
+	for {
+		select {
+		case event := <-p.events:
+			*batch = append(*batch, event)
+			if len(*batch) >= p.config.BatchSize {
+				flush(context.Background())
+			}
+		default:
+			flush(context.Background())
+			return
+		}
+	}
+}
+
+// deliver publishes a batch, retrying with exponential backoff on failure
+func (p *sinkPump[K, V]) deliver(ctx context.Context, batch []ChangeEvent[K, V]) {
+	backoff := p.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := p.publish(ctx, batch)
+		if err == nil {
+			p.published.Add(int64(len(batch)))
+			return
+		}
+		if attempt >= p.config.MaxRetries || ctx.Err() != nil {
+			p.failed.Add(int64(len(batch)))
+			p.metrics.RecordError(fmt.Errorf("sink publish failed after %d attempts: %w", attempt+1, err), "")
+			return
+		}
+		p.retries.Add(1)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+		backoff *= 2
+	}
+}
+
+// publish invokes the sink with panic recovery and the configured timeout
+func (p *sinkPump[K, V]) publish(ctx context.Context, batch []ChangeEvent[K, V]) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrSinkPanicked, r)
+			p.metrics.RecordError(err, string(debug.Stack()))
+		}
+	}()
+
+	if p.config.PublishTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.PublishTimeout)
+		defer cancel()
+	}
+	return p.sink.Publish(ctx, batch)
+}
@@ -0,0 +1,183 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingSink struct {
+	mu      sync.Mutex
+	events  []ChangeEvent[string, int]
+	batches int
+	fail    int
+}
+
+func (s *recordingSink) Publish(_ context.Context, events []ChangeEvent[string, int]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail > 0 {
+		s.fail--
+		return errors.New("temporary failure")
+	}
+	s.batches++
+	s.events = append(s.events, events...)
+	return nil
+}
+
+func (s *recordingSink) snapshot() ([]ChangeEvent[string, int], int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ChangeEvent[string, int](nil), s.events...), s.batches
+}
+
+func TestSink(t *testing.T) {
+	sinkConfig := DefaultSinkConfig()
+	sinkConfig.BatchSize = 2
+	sinkConfig.FlushInterval = 10 * time.Millisecond
+	sinkConfig.RetryBackoff = time.Millisecond
+
+	t.Run("Events Delivered In Order", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		sink := &recordingSink{}
+
+		handle, err := sm.AttachSink(sink, sinkConfig)
+		if err != nil {
+			t.Fatalf("AttachSink failed: %v", err)
+		}
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		sm.Delete("a")
+		_ = sm.ApplyBatch(BatchOperations[string, int]{
+			Operations: []BatchOperation[string, int]{
+				{Type: BatchSet, Key: "c", Value: 3},
+			},
+		})
+
+		sm.Stop()
+
+		events, batches := sink.snapshot()
+		if len(events) != 4 {
+			t.Fatalf("Expected 4 events, got %d", len(events))
+		}
+		if batches < 2 {
+			t.Errorf("Expected events to be batched, got %d batches", batches)
+		}
+
+		expected := []struct {
+			typ ChangeType
+			key string
+		}{
+			{ChangeSet, "a"}, {ChangeSet, "b"}, {ChangeDelete, "a"}, {ChangeSet, "c"},
+		}
+		for i, e := range expected {
+			if events[i].Type != e.typ || events[i].Key != e.key {
+				t.Errorf("Event %d: expected %v %s, got %v %s", i, e.typ, e.key, events[i].Type, events[i].Key)
+			}
+		}
+
+		if stats := handle.Stats(); stats.Published != 4 {
+			t.Errorf("Expected 4 published events, got %d", stats.Published)
+		}
+	})
+
+	t.Run("Retry On Failure", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sink := &recordingSink{fail: 2}
+
+		handle, err := sm.AttachSink(sink, sinkConfig)
+		if err != nil {
+			t.Fatalf("AttachSink failed: %v", err)
+		}
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		handle.Detach()
+
+		stats := handle.Stats()
+		if stats.Published != 2 || stats.Retries != 2 || stats.Failed != 0 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+	})
+
+	t.Run("Failure Recorded After Retries", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sink := &recordingSink{fail: 100}
+
+		handle, err := sm.AttachSink(sink, sinkConfig)
+		if err != nil {
+			t.Fatalf("AttachSink failed: %v", err)
+		}
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		handle.Detach()
+
+		if stats := handle.Stats(); stats.Failed != 2 {
+			t.Errorf("Expected 2 failed events, got %d", stats.Failed)
+		}
+		metrics := sm.GetMetrics()
+		if metrics.TotalErrors() == 0 {
+			t.Error("Expected sink failure to be recorded in metrics")
+		}
+	})
+
+	t.Run("Panic Recorded As Error", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		config := sinkConfig
+		config.MaxRetries = 0
+		sink := SinkFunc[string, int](func(context.Context, []ChangeEvent[string, int]) error {
+			panic("sink failed")
+		})
+
+		handle, err := sm.AttachSink(sink, config)
+		if err != nil {
+			t.Fatalf("AttachSink failed: %v", err)
+		}
+		sm.Set("a", 1)
+		handle.Detach()
+
+		metrics := sm.GetMetrics()
+		if metrics.TotalPanics() != 0 {
+			t.Errorf("Expected no shrink panics, got %d", metrics.TotalPanics())
+		}
+		if metrics.ErrorCount(ErrCodeSinkPanic) == 0 {
+			t.Errorf("Expected sink panic recorded as error, got %v", metrics.ErrorsByCode())
+		}
+		if healthy, report := sm.Healthy(); !healthy {
+			t.Errorf("Expected a sink panic not to fail health checks, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("Detached Sink Receives Nothing", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		sink := &recordingSink{}
+
+		handle, err := sm.AttachSink(sink, sinkConfig)
+		if err != nil {
+			t.Fatalf("AttachSink failed: %v", err)
+		}
+		handle.Detach()
+
+		sm.Set("a", 1)
+		if events, _ := sink.snapshot(); len(events) != 0 {
+			t.Errorf("Expected no events after detach, got %d", len(events))
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		if _, err := sm.AttachSink(&recordingSink{}, SinkConfig{}); err == nil {
+			t.Error("Expected error for invalid sink config")
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package shrinkmap
+
+import "time"
+
+// startOp returns the start time of an operation, or the zero time if
+// neither slow operation reporting nor a MetricsObserver is configured
+func (sm *ShrinkableMap[K, V]) startOp() time.Time {
+	if sm.config.MetricsObserver == nil && (sm.config.SlowOpThreshold <= 0 || sm.config.OnSlowOp == nil) {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// finishOp reports an operation without a key to the MetricsObserver and, if
+// it exceeded SlowOpThreshold, to OnSlowOp
+func (sm *ShrinkableMap[K, V]) finishOp(op string, start time.Time) {
+	if start.IsZero() {
+		return
+	}
+	d := sm.observeOp(op, start)
+	if sm.slowOp(d) {
+		sm.config.OnSlowOp(op, d, nil)
+	}
+}
+
+// finishKeyOp reports an operation on key to the MetricsObserver and, if it
+// exceeded SlowOpThreshold, to OnSlowOp. The key is only boxed when the
+// operation was slow.
+func (sm *ShrinkableMap[K, V]) finishKeyOp(op string, start time.Time, key K) {
+	if start.IsZero() {
+		return
+	}
+	d := sm.observeOp(op, start)
+	if sm.slowOp(d) {
+		sm.config.OnSlowOp(op, d, key)
+	}
+}
+
+// observeOp reports the operation to the MetricsObserver and returns its duration
+func (sm *ShrinkableMap[K, V]) observeOp(op string, start time.Time) time.Duration {
+	d := time.Since(start)
+	if sm.config.MetricsObserver != nil {
+		sm.config.MetricsObserver.ObserveOp(OpEvent{Map: sm.config.Name, Op: op, Duration: d})
+	}
+	return d
+}
+
+// slowOp reports whether an operation taking d is reported to OnSlowOp
+func (sm *ShrinkableMap[K, V]) slowOp(d time.Duration) bool {
+	return sm.config.SlowOpThreshold > 0 && sm.config.OnSlowOp != nil && d >= sm.config.SlowOpThreshold
+}
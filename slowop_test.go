@@ -0,0 +1,61 @@
+package shrinkmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowOperations(t *testing.T) {
+	type slowOp struct {
+		op  string
+		key any
+	}
+
+	t.Run("Reports Slow Operations", func(t *testing.T) {
+		var ops []slowOp
+		config := DefaultConfig().
+			WithAutoShrinkEnabled(false).
+			WithSlowOpThreshold(time.Nanosecond, func(op string, d time.Duration, key any) {
+				ops = append(ops, slowOp{op: op, key: key})
+			})
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		sm.Get("a")
+		sm.Delete("a")
+		sm.ApplyBatch(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "b", Value: 2},
+		}})
+		sm.ForceShrink()
+
+		expected := []slowOp{{"set", "a"}, {"get", "a"}, {"delete", "a"}, {"batch", nil}, {"shrink", nil}}
+		if len(ops) != len(expected) {
+			t.Fatalf("Expected %d slow operations, got %v", len(expected), ops)
+		}
+		for i, want := range expected {
+			if ops[i] != want {
+				t.Errorf("Operation %d: expected %v, got %v", i, want, ops[i])
+			}
+		}
+	})
+
+	t.Run("Fast Operations Not Reported", func(t *testing.T) {
+		reported := 0
+		config := DefaultConfig().WithSlowOpThreshold(time.Hour, func(string, time.Duration, any) { reported++ })
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		sm.Set("a", 1)
+		sm.Get("a")
+		if reported != 0 {
+			t.Errorf("Expected no slow operations, got %d", reported)
+		}
+	})
+
+	t.Run("Threshold Without Callback", func(t *testing.T) {
+		if err := DefaultConfig().WithSlowOpThreshold(time.Second, nil).Validate(); err == nil {
+			t.Error("Expected error for threshold without callback")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package shrinkmap
+
+// PooledSnapshot is a snapshot whose buffer is reused by later snapshots of
+// the same map once released, so frequent snapshots create little garbage
+type PooledSnapshot[K comparable, V any] struct {
+	// Entries of the map at the time of the snapshot, in no particular order.
+	// Entries must not be used after Release.
+	Entries []KeyValue[K, V]
+
+	sm  *ShrinkableMap[K, V]
+	buf *[]KeyValue[K, V]
+}
+
+// PooledSnapshot returns a snapshot like Snapshot, using a pooled buffer.
+// Call Release once the entries are no longer needed.
+func (sm *ShrinkableMap[K, V]) PooledSnapshot() *PooledSnapshot[K, V] {
+	buf := sm.snapshotBuffer()
+	return &PooledSnapshot[K, V]{Entries: *buf, sm: sm, buf: buf}
+}
+
+// Release returns the buffer to the pool. Calling it more than once is a no-op.
+func (s *PooledSnapshot[K, V]) Release() {
+	if s.buf != nil {
+		s.sm.releaseBuffer(s.buf)
+		s.buf = nil
+		s.Entries = nil
+	}
+}
+
+// All returns a function iterating over a snapshot of the map, usable with
+// range-over-func as an iter.Seq2. The pooled snapshot buffer is released
+// when the iteration ends.
+func (sm *ShrinkableMap[K, V]) All() func(yield func(K, V) bool) {
+	return func(yield func(K, V) bool) {
+		s := sm.PooledSnapshot()
+		defer s.Release()
+		for _, kv := range s.Entries {
+			if !yield(kv.Key, kv.Value) {
+				return
+			}
+		}
+	}
+}
+
+// snapshotBuffer fills a pooled buffer with the current entries
+func (sm *ShrinkableMap[K, V]) snapshotBuffer() *[]KeyValue[K, V] {
+	buf, _ := sm.buffers.Get().(*[]KeyValue[K, V])
+	if buf == nil {
+		buf = new([]KeyValue[K, V])
+	}
+
+	sm.mu.RLock()
+	if n := len(sm.data); cap(*buf) < n {
+		*buf = make([]KeyValue[K, V], 0, n)
+	}
+	for k, v := range sm.data {
+		*buf = append(*buf, KeyValue[K, V]{Key: k, Value: v})
+	}
+	sm.mu.RUnlock()
+	sm.copyEntriesOnRead(*buf)
+	return buf
+}
+
+// releaseBuffer clears buf so it retains no entries and returns it to the pool
+func (sm *ShrinkableMap[K, V]) releaseBuffer(buf *[]KeyValue[K, V]) {
+	clear((*buf)[:cap(*buf)])
+	*buf = (*buf)[:0]
+	sm.buffers.Put(buf)
+}
@@ -0,0 +1,63 @@
+package shrinkmap
+
+import "testing"
+
+func TestPooledSnapshots(t *testing.T) {
+	sm := New[int, int](DefaultConfig())
+	defer sm.Stop()
+	for i := 0; i < 100; i++ {
+		sm.Set(i, i)
+	}
+
+	t.Run("Release", func(t *testing.T) {
+		s := sm.PooledSnapshot()
+		if len(s.Entries) != 100 {
+			t.Fatalf("Expected 100 entries, got %d", len(s.Entries))
+		}
+		buf := s.buf
+		s.Release()
+		s.Release()
+		if s.Entries != nil || len(*buf) != 0 || (*buf)[:1][0] != (KeyValue[int, int]{}) {
+			t.Error("Expected released buffer to be emptied and cleared")
+		}
+
+		// A reused buffer holds only the new snapshot
+		sm.Delete(0)
+		s = sm.PooledSnapshot()
+		defer s.Release()
+		if len(s.Entries) != 99 {
+			t.Errorf("Expected 99 entries, got %d", len(s.Entries))
+		}
+	})
+
+	t.Run("All", func(t *testing.T) {
+		sum, count := 0, 0
+		sm.All()(func(k, v int) bool {
+			sum += v
+			count++
+			return true
+		})
+		if count != 99 || sum != 4950 {
+			t.Errorf("Expected 99 entries summing to 4950, got %d and %d", count, sum)
+		}
+
+		count = 0
+		sm.All()(func(k, v int) bool {
+			count++
+			return count < 5
+		})
+		if count != 5 {
+			t.Errorf("Expected iteration to stop after 5 entries, got %d", count)
+		}
+	})
+
+	t.Run("Iterator Release", func(t *testing.T) {
+		it := sm.NewIterator()
+		it.Get()
+		it.Release()
+		if it.Next() {
+			t.Error("Expected released iterator to be exhausted")
+		}
+		it.Release()
+	})
+}
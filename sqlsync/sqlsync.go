@@ -0,0 +1,147 @@
+// Package sqlsync provides a write-behind adapter that mirrors ShrinkableMap
+// mutations into a relational table through database/sql.
+//
+// Every Set becomes an UPSERT and every Delete becomes a DELETE statement.
+// Statements are batched by the map's sink pump and executed in a single
+// transaction per batch, turning the map into a write-back cache in front of
+// the table.
+package sqlsync
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// Config defines how map entries are written to the database
+type Config[K comparable, V any] struct {
+	// Statement executed for every Set, e.g.
+	// "INSERT INTO cache (k, v) VALUES ($1, $2) ON CONFLICT (k) DO UPDATE SET v = EXCLUDED.v"
+	UpsertQuery string
+
+	// Statement executed for every Delete, e.g. "DELETE FROM cache WHERE k = $1"
+	DeleteQuery string
+
+	// Converts a key into the statement arguments identifying the row
+	KeyArgs func(K) []any
+
+	// Converts a value into the statement arguments following the key arguments
+	ValueArgs func(V) []any
+
+	// Batching, flush interval and retry behavior of the write-behind pump
+	Sink shrinkmap.SinkConfig
+}
+
+// Validate checks if the configuration is valid
+func (c Config[K, V]) Validate() error {
+	if c.UpsertQuery == "" {
+		return fmt.Errorf("upsert query must not be empty")
+	}
+	if c.DeleteQuery == "" {
+		return fmt.Errorf("delete query must not be empty")
+	}
+	if c.KeyArgs == nil {
+		return fmt.Errorf("key args function must not be nil")
+	}
+	if c.ValueArgs == nil {
+		return fmt.Errorf("value args function must not be nil")
+	}
+	return c.Sink.Validate()
+}
+
+// Writer is a shrinkmap.Sink that applies change events to a database table
+type Writer[K comparable, V any] struct {
+	db     *sql.DB
+	config Config[K, V]
+}
+
+// NewWriter creates a Writer for the given database and configuration
+func NewWriter[K comparable, V any](db *sql.DB, config Config[K, V]) (*Writer[K, V], error) {
+	if db == nil {
+		return nil, fmt.Errorf("database must not be nil")
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return &Writer[K, V]{db: db, config: config}, nil
+}
+
+// Attach creates a Writer and attaches it to the map as a sink.
+// Detach the returned handle or Stop the map to flush pending writes.
+func Attach[K comparable, V any](sm *shrinkmap.ShrinkableMap[K, V], db *sql.DB, config Config[K, V]) (*shrinkmap.SinkHandle, error) {
+	w, err := NewWriter(db, config)
+	if err != nil {
+		return nil, err
+	}
+	return sm.AttachSink(w, config.Sink)
+}
+
+// Publish writes a batch of change events in a single transaction.
+// Only the last event per key is applied, so retried batches are idempotent.
+func (w *Writer[K, V]) Publish(ctx context.Context, events []shrinkmap.ChangeEvent[K, V]) (err error) {
+	events = collapse(events)
+
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	var upsert, del *sql.Stmt
+	for _, event := range events {
+		switch event.Type {
+		case shrinkmap.ChangeSet:
+			if upsert == nil {
+				if upsert, err = tx.PrepareContext(ctx, w.config.UpsertQuery); err != nil {
+					return fmt.Errorf("prepare upsert: %w", err)
+				}
+				defer upsert.Close()
+			}
+			args := append([]any(nil), w.config.KeyArgs(event.Key)...)
+			args = append(args, w.config.ValueArgs(event.Value)...)
+			if _, err = upsert.ExecContext(ctx, args...); err != nil {
+				return fmt.Errorf("upsert: %w", err)
+			}
+		case shrinkmap.ChangeDelete:
+			if del == nil {
+				if del, err = tx.PrepareContext(ctx, w.config.DeleteQuery); err != nil {
+					return fmt.Errorf("prepare delete: %w", err)
+				}
+				defer del.Close()
+			}
+			if _, err = del.ExecContext(ctx, w.config.KeyArgs(event.Key)...); err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// collapse keeps only the most recent event for every key, preserving order
+func collapse[K comparable, V any](events []shrinkmap.ChangeEvent[K, V]) []shrinkmap.ChangeEvent[K, V] {
+	last := make(map[K]int, len(events))
+	for i, event := range events {
+		last[event.Key] = i
+	}
+	if len(last) == len(events) {
+		return events
+	}
+
+	result := make([]shrinkmap.ChangeEvent[K, V], 0, len(last))
+	for i, event := range events {
+		if last[event.Key] == i {
+			result = append(result, event)
+		}
+	}
+	return result
+}
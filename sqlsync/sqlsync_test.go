@@ -0,0 +1,198 @@
+package sqlsync
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jongyunha/shrinkmap"
+)
+
+// fakeDriver records executed statements and applies them to an in-memory table
+type fakeDriver struct {
+	mu        sync.Mutex
+	table     map[string]int64
+	execs     int
+	commits   int
+	rollbacks int
+	failExec  int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d: d}, nil }
+
+type fakeConn struct {
+	d       *fakeDriver
+	pending []func(map[string]int64)
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{c: c, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { c.pending = nil; return &fakeTx{c: c}, nil }
+
+type fakeTx struct{ c *fakeConn }
+
+func (tx *fakeTx) Commit() error {
+	d := tx.c.d
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, apply := range tx.c.pending {
+		apply(d.table)
+	}
+	d.commits++
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.c.d.mu.Lock()
+	tx.c.d.rollbacks++
+	tx.c.d.mu.Unlock()
+	return nil
+}
+
+type fakeStmt struct {
+	c     *fakeConn
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	d := s.c.d
+	d.mu.Lock()
+	d.execs++
+	if d.failExec > 0 {
+		d.failExec--
+		d.mu.Unlock()
+		return nil, errors.New("exec failed")
+	}
+	d.mu.Unlock()
+
+	key := args[0].(string)
+	switch s.query {
+	case "UPSERT":
+		value := args[1].(int64)
+		s.c.pending = append(s.c.pending, func(t map[string]int64) { t[key] = value })
+	case "DELETE":
+		s.c.pending = append(s.c.pending, func(t map[string]int64) { delete(t, key) })
+	}
+	return driver.RowsAffected(1), nil
+}
+
+var driverSeq int
+
+func openFake(t *testing.T) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{table: make(map[string]int64)}
+	driverSeq++
+	name := fmt.Sprintf("sqlsync-fake-%d", driverSeq)
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("open fake db: %v", err)
+	}
+	return db, d
+}
+
+func testConfig() Config[string, int] {
+	sinkConfig := shrinkmap.DefaultSinkConfig()
+	sinkConfig.FlushInterval = 10 * time.Millisecond
+	sinkConfig.RetryBackoff = time.Millisecond
+	return Config[string, int]{
+		UpsertQuery: "UPSERT",
+		DeleteQuery: "DELETE",
+		KeyArgs:     func(k string) []any { return []any{k} },
+		ValueArgs:   func(v int) []any { return []any{int64(v)} },
+		Sink:        sinkConfig,
+	}
+}
+
+func TestWriter(t *testing.T) {
+	t.Run("Write Behind", func(t *testing.T) {
+		db, d := openFake(t)
+		defer db.Close()
+
+		sm := shrinkmap.New[string, int](shrinkmap.DefaultConfig())
+		handle, err := Attach(sm, db, testConfig())
+		if err != nil {
+			t.Fatalf("Attach failed: %v", err)
+		}
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		sm.Set("a", 3)
+		sm.Delete("b")
+		handle.Detach()
+		sm.Stop()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if len(d.table) != 1 || d.table["a"] != 3 {
+			t.Errorf("Unexpected table contents: %v", d.table)
+		}
+	})
+
+	t.Run("Retry After Failed Batch", func(t *testing.T) {
+		db, d := openFake(t)
+		defer db.Close()
+		d.failExec = 1
+
+		w, err := NewWriter(db, testConfig())
+		if err != nil {
+			t.Fatalf("NewWriter failed: %v", err)
+		}
+
+		events := []shrinkmap.ChangeEvent[string, int]{
+			{Type: shrinkmap.ChangeSet, Key: "a", Value: 1},
+		}
+		if err := w.Publish(context.Background(), events); err == nil {
+			t.Fatal("Expected first publish to fail")
+		}
+		if err := w.Publish(context.Background(), events); err != nil {
+			t.Fatalf("Expected retry to succeed: %v", err)
+		}
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if d.rollbacks != 1 || d.commits != 1 {
+			t.Errorf("Expected 1 rollback and 1 commit, got %d and %d", d.rollbacks, d.commits)
+		}
+		if d.table["a"] != 1 {
+			t.Errorf("Expected a=1, got %v", d.table)
+		}
+	})
+
+	t.Run("Invalid Config", func(t *testing.T) {
+		db, _ := openFake(t)
+		defer db.Close()
+
+		if _, err := NewWriter(db, Config[string, int]{}); err == nil {
+			t.Error("Expected error for empty config")
+		}
+	})
+}
+
+func TestCollapse(t *testing.T) {
+	events := []shrinkmap.ChangeEvent[string, int]{
+		{Type: shrinkmap.ChangeSet, Key: "a", Value: 1},
+		{Type: shrinkmap.ChangeSet, Key: "b", Value: 2},
+		{Type: shrinkmap.ChangeDelete, Key: "a"},
+	}
+
+	result := collapse(events)
+	if len(result) != 2 {
+		t.Fatalf("Expected 2 events, got %d", len(result))
+	}
+	if result[0].Key != "b" || result[1].Key != "a" || result[1].Type != shrinkmap.ChangeDelete {
+		t.Errorf("Unexpected collapsed events: %+v", result)
+	}
+}
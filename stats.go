@@ -0,0 +1,88 @@
+package shrinkmap
+
+import "math/bits"
+
+// Go maps store entries in groups of mapGroupSlots slots and grow once the
+// average load exceeds mapMaxLoad slots per group
+const (
+	mapGroupSlots = 8
+	mapMaxLoad    = 7
+)
+
+// Stats describes the current size of the map and an estimate of the memory
+// allocated for it
+type Stats struct {
+	// Number of live entries
+	Len int64
+
+	// Entries deleted since the underlying map was last reallocated
+	DeletedSinceShrink int64
+
+	// Largest number of entries the underlying map has been allocated for or
+	// has held since it was last reallocated. Go maps never release memory on
+	// delete, so allocation follows this high-water mark rather than Len.
+	PeakLen int64
+
+	// Estimated number of slot groups (buckets) allocated for PeakLen entries
+	EstimatedBuckets int64
+
+	// Estimated number of entries the allocated buckets hold before the map grows
+	EstimatedCapacity int64
+
+	// Number of distinct values shared by the entries when Config.InternValues is set
+	InternedValues int64
+
+	// Bytes allocated for key storage when Config.InternKeys is set
+	KeyArenaBytes int64
+
+	// Entries in the hot and cold tiers of a TieredShrinkableMap and the
+	// encoded size of the cold entries; Len counts both tiers
+	HotLen    int64
+	ColdLen   int64
+	ColdBytes int64
+}
+
+// UnusedCapacity returns the estimated number of allocated but unused entry slots
+func (s Stats) UnusedCapacity() int64 {
+	return max(s.EstimatedCapacity-s.Len, 0)
+}
+
+// Stats returns current size statistics. The allocation figures are estimated
+// from the map's growth history, since the runtime does not expose the size of
+// a map's backing storage.
+func (sm *ShrinkableMap[K, V]) Stats() Stats {
+	peak := sm.sizeHint.Load()
+	buckets := estimateBuckets(peak)
+	stats := Stats{
+		Len:                sm.Len(),
+		DeletedSinceShrink: sm.deletedCount.Load(),
+		PeakLen:            peak,
+		EstimatedBuckets:   buckets,
+		EstimatedCapacity:  buckets * mapMaxLoad,
+	}
+	if sm.interner != nil || sm.keys != nil {
+		sm.mu.RLock()
+		stats.InternedValues = int64(sm.interner.len())
+		stats.KeyArenaBytes = sm.keys.bytes()
+		sm.mu.RUnlock()
+	}
+	return stats
+}
+
+// trackSizeLocked raises the size hint to the current number of entries.
+// Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) trackSizeLocked() {
+	if n := int64(len(sm.data)); n > sm.sizeHint.Load() {
+		sm.sizeHint.Store(n)
+	}
+}
+
+// estimateBuckets returns the smallest power-of-two number of groups whose
+// maximum load fits n entries
+func estimateBuckets(n int64) int64 {
+	if n <= mapGroupSlots {
+		return 1
+	}
+	groups := (n + mapMaxLoad - 1) / mapMaxLoad
+	return 1 << bits.Len64(uint64(groups-1))
+}
@@ -0,0 +1,52 @@
+package shrinkmap
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	t.Run("Tracks Peak After Deletes", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithAutoShrinkEnabled(false).WithInitialCapacity(0))
+		defer sm.Stop()
+
+		for i := 0; i < 1000; i++ {
+			sm.Set(i, i)
+		}
+		for i := 0; i < 900; i++ {
+			sm.Delete(i)
+		}
+
+		stats := sm.Stats()
+		if stats.Len != 100 || stats.PeakLen != 1000 || stats.DeletedSinceShrink != 900 {
+			t.Errorf("Unexpected stats: %+v", stats)
+		}
+		if stats.EstimatedCapacity < 1000 || stats.UnusedCapacity() < 900 {
+			t.Errorf("Expected capacity to follow the peak, got %+v", stats)
+		}
+
+		sm.ForceShrink()
+		stats = sm.Stats()
+		if stats.PeakLen >= 1000 || stats.DeletedSinceShrink != 0 {
+			t.Errorf("Expected shrink to reset the peak, got %+v", stats)
+		}
+		if stats.EstimatedCapacity < stats.Len {
+			t.Errorf("Capacity %d below length %d", stats.EstimatedCapacity, stats.Len)
+		}
+	})
+
+	t.Run("Initial Capacity", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithInitialCapacity(100))
+		defer sm.Stop()
+
+		if stats := sm.Stats(); stats.PeakLen != 100 || stats.EstimatedCapacity < 100 {
+			t.Errorf("Expected preallocated capacity, got %+v", stats)
+		}
+	})
+
+	t.Run("Bucket Estimate", func(t *testing.T) {
+		cases := map[int64]int64{0: 1, 8: 1, 9: 2, 14: 2, 15: 4, 1000: 256}
+		for n, want := range cases {
+			if got := estimateBuckets(n); got != want {
+				t.Errorf("estimateBuckets(%d) = %d, want %d", n, got, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package shrinkmap
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Status reports the state of a map's background goroutines, so services can
+// detect a loop that died, for example after a panic, and alert or restart it
+type Status struct {
+	// Whether Stop has been called
+	Stopped bool
+
+	// The auto-shrink goroutine, enabled by Config.AutoShrinkEnabled; for maps
+	// in a Group, the group's periodic checks of the map
+	ShrinkLoop LoopStatus
+
+	// The demotion goroutine of a TieredShrinkableMap, enabled by a positive
+	// Config.ColdAfter; always disabled for other maps
+	DemoteLoop LoopStatus
+
+	// The expiration goroutine of a LinkedShrinkableMap, enabled by
+	// Config.Eviction EvictAge; always disabled for other maps
+	SweepLoop LoopStatus
+}
+
+// LoopStatus describes a background goroutine
+type LoopStatus struct {
+	// Whether the goroutine was started for this map
+	Enabled bool
+
+	// Whether the goroutine is still running
+	Running bool
+
+	// Whether the goroutine ended because of a panic
+	Panicked bool
+
+	// Number of times the goroutine was restarted after a panic
+	Restarts int64
+
+	// When the goroutine was started, last woke up, and last did work
+	// (shrank, demoted or expired entries); zero if it never did
+	Started      time.Time
+	LastTick     time.Time
+	LastActivity time.Time
+}
+
+// loopState tracks a background goroutine for Status
+type loopState struct {
+	enabled      atomic.Bool
+	running      atomic.Bool
+	panicked     atomic.Bool
+	restarts     atomic.Int64
+	started      atomic.Int64 // unix nanoseconds
+	lastTick     atomic.Int64
+	lastActivity atomic.Int64
+}
+
+// start marks the goroutine as running. It is called before the goroutine is
+// launched, so Status never misses it.
+func (s *loopState) start() {
+	s.enabled.Store(true)
+	s.running.Store(true)
+	s.started.Store(time.Now().UnixNano())
+}
+
+// tick records that the goroutine woke up
+func (s *loopState) tick() {
+	s.lastTick.Store(time.Now().UnixNano())
+}
+
+// active records that the goroutine did work
+func (s *loopState) active() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+// restart records that the goroutine was restarted after a panic
+func (s *loopState) restart() {
+	s.restarts.Add(1)
+}
+
+// exit marks the goroutine as ended
+func (s *loopState) exit(panicked bool) {
+	s.panicked.Store(panicked)
+	s.running.Store(false)
+}
+
+func (s *loopState) status() LoopStatus {
+	return LoopStatus{
+		Enabled:      s.enabled.Load(),
+		Running:      s.running.Load(),
+		Panicked:     s.panicked.Load(),
+		Restarts:     s.restarts.Load(),
+		Started:      unixTime(s.started.Load()),
+		LastTick:     unixTime(s.lastTick.Load()),
+		LastActivity: unixTime(s.lastActivity.Load()),
+	}
+}
+
+// unixTime converts unix nanoseconds to a time, mapping 0 to the zero time
+func unixTime(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
+
+// Status reports whether the map is stopped and the state of its auto-shrink goroutine
+func (sm *ShrinkableMap[K, V]) Status() Status {
+	return Status{
+		Stopped:    sm.stopped.Load(),
+		ShrinkLoop: sm.shrinkStatus.status(),
+	}
+}
+
+// Status reports whether the map is stopped and the state of the demotion
+// goroutine and the auto-shrink goroutine of the hot tier
+func (tm *TieredShrinkableMap[K, V]) Status() Status {
+	status := tm.hot.Status()
+	status.DemoteLoop = tm.demoteStatus.status()
+	return status
+}
@@ -0,0 +1,172 @@
+package shrinkmap
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitFor polls cond until it holds or the timeout expires
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// deleteWithoutShrink deletes keys 0 to n-1, leaving the shrink to the shrink loop
+func deleteWithoutShrink(sm *ShrinkableMap[int, int], n int) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	for i := 0; i < n; i++ {
+		sm.deleteLocked(i)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Run("ShrinkLoop", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithShrinkInterval(5 * time.Millisecond).WithMinShrinkInterval(0))
+		defer sm.Stop()
+
+		status := sm.Status()
+		if !status.ShrinkLoop.Enabled || !status.ShrinkLoop.Running || status.ShrinkLoop.Started.IsZero() {
+			t.Errorf("Expected a running shrink loop, got %+v", status.ShrinkLoop)
+		}
+		if status.DemoteLoop.Enabled {
+			t.Error("Expected no demotion loop for a plain map")
+		}
+
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.LastActivity.IsZero() })
+		if sm.Status().ShrinkLoop.LastTick.IsZero() {
+			t.Error("Expected the last tick to be recorded")
+		}
+
+		sm.Stop()
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+		if status := sm.Status(); !status.Stopped || status.ShrinkLoop.Panicked {
+			t.Errorf("Expected a cleanly stopped map, got %+v", status)
+		}
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithShrinkInterval(5 * time.Millisecond).WithMinShrinkInterval(0).
+			WithTargetCapacity(func(live, peak int64, config Config) int { panic("boom") }))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+		if status := sm.Status(); !status.ShrinkLoop.Panicked || status.Stopped {
+			t.Errorf("Expected the loop to have died from a panic, got %+v", status)
+		}
+	})
+
+	t.Run("RestartAfterPanic", func(t *testing.T) {
+		var calls atomic.Int64
+		sm := New[int, int](DefaultConfig().WithShrinkInterval(5*time.Millisecond).WithMinShrinkInterval(0).
+			WithShrinkLoopRestart(0, time.Millisecond).
+			WithTargetCapacity(func(live, peak int64, config Config) int {
+				if calls.Add(1) == 1 {
+					panic("boom")
+				}
+				return GrowthFactorCapacity(live, peak, config)
+			}))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.LastActivity.IsZero() })
+		status := sm.Status()
+		if !status.ShrinkLoop.Running || status.ShrinkLoop.Restarts != 1 {
+			t.Errorf("Expected the loop to be restarted once and running, got %+v", status.ShrinkLoop)
+		}
+		metrics := sm.GetMetrics()
+		if metrics.TotalPanics() != 1 {
+			t.Errorf("Expected 1 recorded panic, got %d", metrics.TotalPanics())
+		}
+	})
+
+	t.Run("MaxRestarts", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithShrinkInterval(5*time.Millisecond).WithMinShrinkInterval(0).
+			WithShrinkLoopRestart(2, time.Millisecond).
+			WithTargetCapacity(func(live, peak int64, config Config) int { panic("boom") }))
+		defer sm.Stop()
+
+		for i := 0; i < 100; i++ {
+			sm.Set(i, i)
+		}
+		deleteWithoutShrink(sm, 90)
+		waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+		status := sm.Status()
+		if !status.ShrinkLoop.Panicked || status.ShrinkLoop.Restarts != 2 {
+			t.Errorf("Expected the loop to give up after 2 restarts, got %+v", status.ShrinkLoop)
+		}
+		metrics := sm.GetMetrics()
+		if metrics.TotalPanics() != 3 {
+			t.Errorf("Expected 3 recorded panics, got %d", metrics.TotalPanics())
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig().WithAutoShrinkEnabled(false))
+		defer sm.Stop()
+		if status := sm.Status(); status.ShrinkLoop.Enabled || status.ShrinkLoop.Running {
+			t.Errorf("Expected no shrink loop, got %+v", status.ShrinkLoop)
+		}
+	})
+
+	t.Run("Tiered", func(t *testing.T) {
+		tm := NewTiered[string, int](DefaultConfig().WithColdAfter(5*time.Millisecond), nil)
+		defer tm.Stop()
+		tm.Set("a", 1)
+
+		waitFor(t, func() bool { return !tm.Status().DemoteLoop.LastActivity.IsZero() })
+		tm.Stop()
+		if status := tm.Status(); status.DemoteLoop.Running || !status.Stopped {
+			t.Errorf("Expected stopped loops, got %+v", status)
+		}
+	})
+}
+
+func TestShrinkTrigger(t *testing.T) {
+	trigger := make(chan struct{})
+	sm := New[int, int](DefaultConfig().WithShrinkInterval(time.Millisecond).WithMinShrinkInterval(0).
+		WithShrinkTrigger(trigger))
+	defer sm.Stop()
+
+	for i := 0; i < 100; i++ {
+		sm.Set(i, i)
+	}
+	deleteWithoutShrink(sm, 90)
+
+	time.Sleep(20 * time.Millisecond)
+	metrics := sm.GetMetrics()
+	if status := sm.Status(); !status.ShrinkLoop.LastTick.IsZero() || metrics.TotalShrinks() != 0 {
+		t.Fatalf("Expected no shrink checks without a trigger, got %+v", status.ShrinkLoop)
+	}
+
+	trigger <- struct{}{}
+	waitFor(t, func() bool { return !sm.Status().ShrinkLoop.LastActivity.IsZero() })
+	metrics = sm.GetMetrics()
+	if metrics.TotalShrinks() != 1 || sm.deletedCount.Load() != 0 {
+		t.Errorf("Expected one shrink per trigger, got %d", metrics.TotalShrinks())
+	}
+
+	close(trigger)
+	waitFor(t, func() bool { return !sm.Status().ShrinkLoop.Running })
+	if status := sm.Status(); status.ShrinkLoop.Panicked || status.Stopped {
+		t.Errorf("Expected the loop to end cleanly when the trigger is closed, got %+v", status)
+	}
+}
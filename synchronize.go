@@ -0,0 +1,87 @@
+package shrinkmap
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// SyncOptions controls SyncTo
+type SyncOptions[V any] struct {
+	// Reports whether two values are equal; unchanged entries are not written.
+	// Defaults to reflect.DeepEqual.
+	Equal func(a, b V) bool
+
+	// Keep entries in dst that are not present in src instead of deleting them
+	KeepExtra bool
+}
+
+// SyncResult reports the operations applied by SyncTo
+type SyncResult struct {
+	Set     int
+	Deleted int
+}
+
+// SyncTo makes dst match a snapshot of src by applying only the differing
+// sets and deletes to dst in a single batch. Unchanged entries are neither
+// written nor reported to dst's sinks and watchers. Values pass through the
+// write hooks of dst, without its lock held, before they are compared.
+func SyncTo[K comparable, V any](src, dst *ShrinkableMap[K, V], opts SyncOptions[V]) (SyncResult, error) {
+	var result SyncResult
+	if src == nil || dst == nil {
+		return result, fmt.Errorf("source and destination maps must not be nil")
+	}
+	if src == dst {
+		return result, fmt.Errorf("source and destination must be different maps")
+	}
+	equal := opts.Equal
+	if equal == nil {
+		equal = func(a, b V) bool { return reflect.DeepEqual(a, b) }
+	}
+
+	src.mu.RLock()
+	sets := BatchOperations[K, V]{Operations: make([]BatchOperation[K, V], 0, len(src.data))}
+	for k, v := range src.data {
+		sets.Operations = append(sets.Operations, BatchOperation[K, V]{Type: BatchSet, Key: k, Value: v})
+	}
+	src.mu.RUnlock()
+
+	// Compare the values dst would store, so transformed values are not
+	// rewritten on every sync
+	sets, err := dst.prepareBatch(sets)
+	if err != nil {
+		return result, err
+	}
+
+	dst.mu.Lock()
+	var batch BatchOperations[K, V]
+	for _, op := range sets.Operations {
+		if current, exists := dst.data[op.Key]; !exists || !equal(current, op.Value) {
+			batch.Operations = append(batch.Operations, op)
+			result.Set++
+		}
+	}
+	if !opts.KeepExtra {
+		synced := make(map[K]struct{}, len(sets.Operations))
+		for _, op := range sets.Operations {
+			synced[op.Key] = struct{}{}
+		}
+		for k := range dst.data {
+			if _, exists := synced[k]; !exists {
+				batch.Operations = append(batch.Operations, BatchOperation[K, V]{Type: BatchDelete, Key: k})
+				result.Deleted++
+			}
+		}
+	}
+
+	if err := dst.checkBatchLocked(batch); err != nil {
+		dst.mu.Unlock()
+		return SyncResult{}, err
+	}
+	needsShrink := dst.applyBatchLocked(batch)
+	dst.mu.Unlock()
+
+	if needsShrink || (result.Deleted > 0 && dst.config.AutoShrinkEnabled) {
+		dst.TryShrink()
+	}
+	return result, nil
+}
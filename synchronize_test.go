@@ -0,0 +1,111 @@
+package shrinkmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSyncTo(t *testing.T) {
+	t.Run("Applies Diff", func(t *testing.T) {
+		src := New[string, int](DefaultConfig())
+		dst := New[string, int](DefaultConfig())
+		defer src.Stop()
+		defer dst.Stop()
+
+		src.Set("same", 1)
+		src.Set("changed", 2)
+		src.Set("added", 3)
+		dst.Set("same", 1)
+		dst.Set("changed", 0)
+		dst.Set("extra", 4)
+
+		events := make(chan ChangeEvent[string, int], 10)
+		dst.Watch(events, nil)
+
+		result, err := SyncTo(src, dst, SyncOptions[int]{})
+		if err != nil {
+			t.Fatalf("SyncTo failed: %v", err)
+		}
+		if result.Set != 2 || result.Deleted != 1 {
+			t.Errorf("Unexpected result: %+v", result)
+		}
+		if len(events) != 3 {
+			t.Errorf("Expected only 3 changes to be applied, got %d", len(events))
+		}
+		if dst.Len() != 3 || dst.Contains("extra") {
+			t.Errorf("Expected dst to match src, got %v", dst.Snapshot())
+		}
+		if v, _ := dst.Get("changed"); v != 2 {
+			t.Errorf("Expected changed=2, got %d", v)
+		}
+	})
+
+	t.Run("Keep Extra", func(t *testing.T) {
+		src := New[string, int](DefaultConfig())
+		dst := New[string, int](DefaultConfig())
+		defer src.Stop()
+		defer dst.Stop()
+		dst.Set("extra", 1)
+
+		result, err := SyncTo(src, dst, SyncOptions[int]{KeepExtra: true})
+		if err != nil || result.Deleted != 0 || !dst.Contains("extra") {
+			t.Errorf("Expected extra entry to be kept, got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("Custom Equal", func(t *testing.T) {
+		src := New[string, []int](DefaultConfig())
+		dst := New[string, []int](DefaultConfig())
+		defer src.Stop()
+		defer dst.Stop()
+		src.Set("a", []int{1, 2})
+		dst.Set("a", []int{1, 3})
+
+		sameLength := func(a, b []int) bool { return len(a) == len(b) }
+		result, _ := SyncTo(src, dst, SyncOptions[[]int]{Equal: sameLength})
+		if result.Set != 0 {
+			t.Errorf("Expected custom equality to skip the write, got %+v", result)
+		}
+	})
+	t.Run("Transformed Values", func(t *testing.T) {
+		src := New[string, int](DefaultConfig())
+		defer src.Stop()
+		var dst *ShrinkableMap[string, int]
+		dst = New[string, int](DefaultConfig().WithTransformOnSet(func(k, v any) any {
+			// Hooks may read the destination
+			dst.Get(k.(string))
+			return v.(int) * 10
+		}))
+		defer dst.Stop()
+		src.Set("a", 1)
+		src.Set("b", 2)
+
+		sync := func() SyncResult {
+			t.Helper()
+			done := make(chan SyncResult, 1)
+			go func() {
+				result, err := SyncTo(src, dst, SyncOptions[int]{})
+				if err != nil {
+					t.Errorf("SyncTo failed: %v", err)
+				}
+				done <- result
+			}()
+			select {
+			case result := <-done:
+				return result
+			case <-time.After(time.Second):
+				t.Fatal("SyncTo deadlocked on a hook reading the destination")
+				return SyncResult{}
+			}
+		}
+		if result := sync(); result.Set != 2 {
+			t.Errorf("Expected 2 sets, got %+v", result)
+		}
+		if v, _ := dst.Get("b"); v != 20 {
+			t.Errorf("Expected transformed value 20, got %d", v)
+		}
+		if result := sync(); result.Set != 0 {
+			t.Errorf("Expected unchanged transformed values not to be rewritten, got %+v", result)
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package shrinkmap
+
+// SyncMap adapts a ShrinkableMap to the method set of sync.Map, so code written
+// against sync.Map can switch to a shrinking map without a rewrite.
+// Keys and values of the wrong type are treated as absent by lookups and cause
+// a panic when stored, mirroring a failed type assertion; a nil value is
+// stored as the zero value of V, so nil round-trips when V is an interface
+// type. Reads apply Config.ValidateOnGet like Get. Stores go through
+// the same value hooks, validation and memory pressure checks as Set, and
+// panic like MustSet where SetChecked would fail.
+type SyncMap[K comparable, V any] struct {
+	sm *ShrinkableMap[K, V]
+}
+
+// AsSyncMap returns a sync.Map compatible view of the map
+func (sm *ShrinkableMap[K, V]) AsSyncMap() *SyncMap[K, V] {
+	return &SyncMap[K, V]{sm: sm}
+}
+
+// storedValue converts a value passed to a store to V, mapping nil to the
+// zero value
+func storedValue[V any](value any) V {
+	if v, ok := value.(V); ok || value == nil {
+		return v
+	}
+	return value.(V) // panics like a failed type assertion
+}
+
+// mustPrepare runs the write hooks of Set on the pair and returns the value
+// to store, panicking like MustSet if the pair is rejected
+func (m *SyncMap[K, V]) mustPrepare(key K, value V) V {
+	value, err := m.sm.prepareWrite("set", key, value)
+	if err != nil {
+		m.fail(key, err)
+	}
+	return value
+}
+
+// mustInsertLocked panics like MustSet if storing key would be rejected
+// under memory pressure. Must be called with m.sm.mu held; it is released
+// before panicking.
+func (m *SyncMap[K, V]) mustInsertLocked(key K) {
+	if err := m.sm.checkInsertLocked("set", key); err != nil {
+		m.sm.mu.Unlock()
+		m.fail(key, err)
+	}
+}
+
+// fail panics with err like MustSet
+func (m *SyncMap[K, V]) fail(key K, err error) {
+	panic(&KeyError{Op: "set", Key: m.sm.errorKey(key), Err: m.sm.apiError(err)})
+}
+
+// Load returns the value stored in the map for a key, or nil if no value is present
+func (m *SyncMap[K, V]) Load(key any) (value any, ok bool) {
+	k, ok := key.(K)
+	if !ok {
+		return nil, false
+	}
+	v, ok := m.sm.Get(k)
+	if !ok {
+		return nil, false
+	}
+	return v, true
+}
+
+// Store sets the value for a key
+func (m *SyncMap[K, V]) Store(key, value any) {
+	m.sm.MustSet(key.(K), storedValue[V](value))
+}
+
+// LoadOrStore returns the existing value for the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+// An entry failing Config.ValidateOnGet is absent to Load, so it is replaced.
+func (m *SyncMap[K, V]) LoadOrStore(key, value any) (actual any, loaded bool) {
+	k := key.(K)
+	v := storedValue[V](value)
+	prepared := false
+	for {
+		m.sm.mu.RLock()
+		existing, exists := m.sm.data[k]
+		m.sm.mu.RUnlock()
+		if exists {
+			if actual, valid := m.sm.checkRead(k, existing, true, false); valid {
+				return actual, true
+			}
+		}
+		if !prepared {
+			v, prepared = m.mustPrepare(k, v), true
+		}
+
+		// Start over if the entry changed while the hooks ran
+		m.sm.mu.Lock()
+		current, found := m.sm.data[k]
+		if found != exists || (found && !sameValue(current, existing)) {
+			m.sm.mu.Unlock()
+			continue
+		}
+		if !found {
+			m.mustInsertLocked(k)
+		}
+		needsShrink := m.sm.setLocked(k, v)
+		m.sm.mu.Unlock()
+
+		if needsShrink {
+			m.sm.TryShrink()
+		}
+		return m.sm.copyOnRead(v), false
+	}
+}
+
+// LoadAndDelete deletes the value for a key, returning the previous value if any
+func (m *SyncMap[K, V]) LoadAndDelete(key any) (value any, loaded bool) {
+	k, ok := key.(K)
+	if !ok {
+		return nil, false
+	}
+
+	m.sm.mu.Lock()
+	v, loaded := m.sm.deleteLocked(k)
+	m.sm.mu.Unlock()
+
+	if !loaded {
+		return nil, false
+	}
+	if m.sm.config.AutoShrinkEnabled {
+		m.sm.TryShrink()
+	}
+	return v, true
+}
+
+// Delete deletes the value for a key
+func (m *SyncMap[K, V]) Delete(key any) {
+	m.LoadAndDelete(key)
+}
+
+// Swap swaps the value for a key and returns the previous value if any
+func (m *SyncMap[K, V]) Swap(key, value any) (previous any, loaded bool) {
+	k := key.(K)
+	v := m.mustPrepare(k, storedValue[V](value))
+
+	m.sm.mu.Lock()
+	m.mustInsertLocked(k)
+	old, loaded := m.sm.data[k]
+	needsShrink := m.sm.setLocked(k, v)
+	m.sm.mu.Unlock()
+
+	if needsShrink {
+		m.sm.TryShrink()
+	}
+	if !loaded {
+		return nil, false
+	}
+	return m.sm.copyOnRead(old), true
+}
+
+// CompareAndSwap swaps the old and new values for key if the value stored in
+// the map is equal to old. The value type must be comparable. The write hooks
+// only run, and may panic, if the stored value matches.
+func (m *SyncMap[K, V]) CompareAndSwap(key, old, new any) (swapped bool) {
+	k, ok := key.(K)
+	if !ok {
+		return false
+	}
+	m.sm.mu.RLock()
+	current, exists := m.sm.data[k]
+	m.sm.mu.RUnlock()
+	if !exists || any(current) != old {
+		return false
+	}
+	v := m.mustPrepare(k, storedValue[V](new))
+
+	m.sm.mu.Lock()
+	current, exists = m.sm.data[k]
+	if !exists || any(current) != old {
+		m.sm.mu.Unlock()
+		return false
+	}
+	m.sm.setLocked(k, v)
+	m.sm.mu.Unlock()
+	return true
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old.
+// The value type must be comparable.
+func (m *SyncMap[K, V]) CompareAndDelete(key, old any) (deleted bool) {
+	k, ok := key.(K)
+	if !ok {
+		return false
+	}
+
+	m.sm.mu.Lock()
+	current, exists := m.sm.data[k]
+	if !exists || any(current) != old {
+		m.sm.mu.Unlock()
+		return false
+	}
+	m.sm.deleteLocked(k)
+	m.sm.mu.Unlock()
+
+	if m.sm.config.AutoShrinkEnabled {
+		m.sm.TryShrink()
+	}
+	return true
+}
+
+// Range calls f sequentially for each key and value present in a snapshot of
+// the map. If f returns false, range stops the iteration.
+func (m *SyncMap[K, V]) Range(f func(key, value any) bool) {
+	for _, kv := range m.sm.Snapshot() {
+		if !f(kv.Key, kv.Value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// syncMapAPI is the method set shared by sync.Map and SyncMap
+type syncMapAPI interface {
+	Load(key any) (value any, ok bool)
+	Store(key, value any)
+	LoadOrStore(key, value any) (actual any, loaded bool)
+	LoadAndDelete(key any) (value any, loaded bool)
+	Delete(key any)
+	Swap(key, value any) (previous any, loaded bool)
+	CompareAndSwap(key, old, new any) (swapped bool)
+	CompareAndDelete(key, old any) (deleted bool)
+	Range(f func(key, value any) bool)
+}
+
+var (
+	_ syncMapAPI = (*sync.Map)(nil)
+	_ syncMapAPI = (*SyncMap[string, int])(nil)
+)
+
+func TestSyncMap(t *testing.T) {
+	sm := New[string, int](DefaultConfig())
+	defer sm.Stop()
+
+	// Run the same script against sync.Map and the adapter
+	for name, m := range map[string]syncMapAPI{"sync.Map": &sync.Map{}, "SyncMap": sm.AsSyncMap()} {
+		t.Run(name, func(t *testing.T) {
+			m.Store("a", 1)
+			if v, ok := m.Load("a"); !ok || v != 1 {
+				t.Errorf("Load: expected 1, got %v", v)
+			}
+			if _, ok := m.Load(42); ok {
+				t.Error("Load with wrong key type should miss")
+			}
+
+			if actual, loaded := m.LoadOrStore("a", 2); !loaded || actual != 1 {
+				t.Errorf("LoadOrStore existing: got %v, %v", actual, loaded)
+			}
+			if actual, loaded := m.LoadOrStore("b", 2); loaded || actual != 2 {
+				t.Errorf("LoadOrStore new: got %v, %v", actual, loaded)
+			}
+
+			if prev, loaded := m.Swap("a", 10); !loaded || prev != 1 {
+				t.Errorf("Swap: got %v, %v", prev, loaded)
+			}
+			if m.CompareAndSwap("a", 1, 20) {
+				t.Error("CompareAndSwap should fail for stale old value")
+			}
+			if !m.CompareAndSwap("a", 10, 20) {
+				t.Error("CompareAndSwap should succeed for current value")
+			}
+			if m.CompareAndDelete("a", 10) || !m.CompareAndDelete("a", 20) {
+				t.Error("CompareAndDelete returned unexpected result")
+			}
+
+			if v, loaded := m.LoadAndDelete("b"); !loaded || v != 2 {
+				t.Errorf("LoadAndDelete: got %v, %v", v, loaded)
+			}
+			m.Store("c", 3)
+			m.Delete("c")
+
+			m.Store("d", 4)
+			m.Store("e", 5)
+			sum := 0
+			m.Range(func(_, v any) bool {
+				sum += v.(int)
+				return true
+			})
+			if sum != 9 {
+				t.Errorf("Range: expected sum 9, got %d", sum)
+			}
+		})
+	}
+
+	if sm.Len() != 2 {
+		t.Errorf("Expected 2 entries in the underlying map, got %d", sm.Len())
+	}
+}
+
+func TestSyncMapWriteHooks(t *testing.T) {
+	sm := New[string, int](DefaultConfig().
+		WithValidators(nil, func(v any) error {
+			if v.(int) < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		}).
+		WithTransformOnSet(func(_, v any) any { return v.(int) * 10 }))
+	defer sm.Stop()
+	m := sm.AsSyncMap()
+
+	if actual, loaded := m.LoadOrStore("a", 1); loaded || actual != 10 {
+		t.Errorf("LoadOrStore: expected the transformed value, got %v, %v", actual, loaded)
+	}
+	if prev, loaded := m.Swap("a", 2); !loaded || prev != 10 {
+		t.Errorf("Swap: got %v, %v", prev, loaded)
+	}
+	if !m.CompareAndSwap("a", 20, 3) {
+		t.Error("CompareAndSwap should succeed for the stored value")
+	}
+	if v, _ := sm.Get("a"); v != 30 {
+		t.Errorf("Expected writes to be transformed, got %d", v)
+	}
+
+	for name, store := range map[string]func(){
+		"LoadOrStore":    func() { m.LoadOrStore("b", -1) },
+		"Swap":           func() { m.Swap("b", -1) },
+		"CompareAndSwap": func() { m.CompareAndSwap("a", 30, -1) },
+	} {
+		func() {
+			defer func() {
+				if r := recover(); r == nil {
+					t.Errorf("%s: expected a panic for an invalid value", name)
+				}
+			}()
+			store()
+		}()
+	}
+	if v, _ := sm.Get("a"); v != 30 || sm.Contains("b") {
+		t.Errorf("Rejected writes must not modify the map, got %v", sm.Snapshot())
+	}
+
+	// Hooks only run for values that are actually stored
+	if m.CompareAndSwap("b", 1, -1) || m.CompareAndSwap("a", 1, -1) {
+		t.Error("CompareAndSwap should fail without storing for a mismatch")
+	}
+	if actual, loaded := m.LoadOrStore("a", -1); !loaded || actual != 30 {
+		t.Errorf("LoadOrStore should load the existing value, got %v, %v", actual, loaded)
+	}
+}
+
+func TestSyncMapNilValues(t *testing.T) {
+	sm := New[string, any](DefaultConfig())
+	defer sm.Stop()
+	m := sm.AsSyncMap()
+
+	m.Store("a", nil)
+	if v, ok := m.Load("a"); !ok || v != nil {
+		t.Errorf("Expected stored nil, got %v, %v", v, ok)
+	}
+	if actual, loaded := m.LoadOrStore("b", nil); loaded || actual != nil {
+		t.Errorf("LoadOrStore: got %v, %v", actual, loaded)
+	}
+	if prev, loaded := m.Swap("b", 1); !loaded || prev != nil {
+		t.Errorf("Swap: got %v, %v", prev, loaded)
+	}
+	if !m.CompareAndSwap("a", nil, 2) || !m.CompareAndSwap("a", 2, nil) {
+		t.Error("CompareAndSwap should swap to and from nil")
+	}
+}
+
+func TestSyncMapValidateOnGet(t *testing.T) {
+	sm := New[string, int](DefaultConfig().WithValidateOnGet(func(_, v any) bool { return v.(int) >= 0 }, false))
+	defer sm.Stop()
+	m := sm.AsSyncMap()
+	sm.Set("a", -1)
+
+	if _, ok := m.Load("a"); ok {
+		t.Error("Load should hide an invalid entry")
+	}
+	if actual, loaded := m.LoadOrStore("a", 1); loaded || actual != 1 {
+		t.Errorf("LoadOrStore should replace an invalid entry, got %v, %v", actual, loaded)
+	}
+	if v, _ := sm.Get("a"); v != 1 {
+		t.Errorf("Expected the new value to be stored, got %d", v)
+	}
+}
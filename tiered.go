@@ -0,0 +1,311 @@
+package shrinkmap
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// tieredEntry is a hot tier entry. The value is never modified once the
+// entry is stored; the access time is updated by reads.
+type tieredEntry[V any] struct {
+	value    V
+	accessed atomic.Int64 // unix nanoseconds
+}
+
+// TieredShrinkableMap keeps recently used entries in a hot tier and moves
+// entries idle for Config.ColdAfter to a cold tier, where values are stored
+// encoded with a Codec. Cold entries stay in memory but usually take far less
+// of it, and are moved back to the hot tier when read by Get. This sits
+// between keeping everything hot and evicting idle entries.
+//
+// Both tiers are ShrinkableMaps and shrink independently. Value hooks apply
+// to values written by Set; moving entries between tiers does not rerun
+// them. Metrics describe the hot tier; Config.DeleteInvalidOnGet and
+// retained history are not supported.
+type TieredShrinkableMap[K comparable, V any] struct {
+	mu        sync.Mutex // serializes writes and moves between tiers
+	hot       *ShrinkableMap[K, *tieredEntry[V]]
+	cold      *ShrinkableMap[K, []byte]
+	coldBytes atomic.Int64
+	codec     Codec[K, V]
+	config    Config
+	now       func() time.Time
+	cancel    context.CancelFunc
+	done      chan struct{}
+
+	demoteStatus loopState // demotion goroutine, reported by Status
+}
+
+// NewTiered creates a new tiered map with the given configuration. Cold
+// values are encoded with codec, or with GobCodec if codec is nil. With a
+// positive Config.ColdAfter, idle entries are demoted every ColdAfter.
+func NewTiered[K comparable, V any](config Config, codec Codec[K, V]) *TieredShrinkableMap[K, V] {
+	if codec == nil {
+		codec = GobCodec[K, V]{}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	tm := &TieredShrinkableMap[K, V]{
+		hot: New[K, *tieredEntry[V]](entryConfig(config,
+			func(e *tieredEntry[V]) V { return e.value },
+			func(e *tieredEntry[V], v V) { e.value = v })),
+		cold:   New[K, []byte](coldConfig(config)),
+		codec:  codec,
+		config: config,
+		now:    time.Now,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	if config.ColdAfter > 0 {
+		tm.demoteStatus.start()
+		go tm.demoteLoop(ctx)
+	} else {
+		close(tm.done)
+	}
+	return tm
+}
+
+// coldConfig strips the hooks and reporting that apply to values as written
+// by callers from the configuration of the cold tier
+func coldConfig(config Config) Config {
+	if config.Name != "" {
+		config.Name += "/cold"
+	}
+	config = config.withoutEviction()
+	config.ValidateKey = nil
+	config.ValidateValue = nil
+	config.TransformOnSet = nil
+	config.ValidateOnGet = nil
+	config.DeleteInvalidOnGet = false
+	config.MaxValueBytes = 0
+	config.HistoryRetention = 0
+	config.InternValues = false
+	config.CopyOnWrite = false
+	config.CopyOnRead = false
+	config.CloneValue = nil
+	config.RejectInsertsUnderPressure = false
+	config.RecordAPIErrors = false
+	config.SlowOpThreshold = 0
+	config.OnSlowOp = nil
+	config.MetricsObserver = nil
+	config.Alerts = nil
+	return config
+}
+
+// Set stores the pair in the hot tier, replacing any cold copy
+func (tm *TieredShrinkableMap[K, V]) Set(key K, value V) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	e := &tieredEntry[V]{value: value}
+	e.accessed.Store(tm.now().UnixNano())
+	if err := tm.hot.SetChecked(key, e); err != nil {
+		return err
+	}
+	tm.deleteColdLocked(key)
+	return nil
+}
+
+// Get retrieves the value associated with the given key. A cold entry is
+// decoded and moved to the hot tier.
+func (tm *TieredShrinkableMap[K, V]) Get(key K) (V, bool) {
+	if e, ok := tm.hot.Get(key); ok {
+		e.accessed.Store(tm.now().UnixNano())
+		return e.value, true
+	}
+	if !tm.cold.Contains(key) {
+		var zero V
+		return zero, false
+	}
+	return tm.promote(key)
+}
+
+// promote moves a cold entry to the hot tier and returns its value
+func (tm *TieredShrinkableMap[K, V]) promote(key K) (V, bool) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	// The entry may have been written or promoted since the caller looked
+	if e, ok := tm.hot.Get(key); ok {
+		e.accessed.Store(tm.now().UnixNano())
+		return e.value, true
+	}
+	var zero V
+	data, ok := tm.cold.Get(key)
+	if !ok {
+		return zero, false
+	}
+	_, value, err := tm.codec.DecodeEntry(data)
+	if err != nil {
+		tm.hot.metrics.RecordError(&KeyError{Op: "promote", Key: tm.hot.errorKey(key), Err: err}, "")
+		return zero, false
+	}
+	e := &tieredEntry[V]{value: value}
+	e.accessed.Store(tm.now().UnixNano())
+	tm.hot.moveIn(key, e)
+	tm.deleteColdLocked(key)
+	return value, true
+}
+
+// Demote moves entries not read or written for Config.ColdAfter to the cold
+// tier and returns how many were moved. Entries that fail to encode stay hot
+// and are recorded as errors.
+func (tm *TieredShrinkableMap[K, V]) Demote() int {
+	if tm.config.ColdAfter <= 0 {
+		return 0
+	}
+	return tm.demote(tm.now().Add(-tm.config.ColdAfter))
+}
+
+// DemoteAll moves every hot entry to the cold tier and returns how many were moved
+func (tm *TieredShrinkableMap[K, V]) DemoteAll() int {
+	return tm.demote(time.Time{})
+}
+
+// demote moves entries last accessed before cutoff; the zero cutoff moves all
+func (tm *TieredShrinkableMap[K, V]) demote(cutoff time.Time) int {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	var keys []K
+	for _, kv := range tm.hot.Snapshot() {
+		if !cutoff.IsZero() && kv.Value.accessed.Load() >= cutoff.UnixNano() {
+			continue
+		}
+		data, err := tm.codec.EncodeEntry(kv.Key, kv.Value.value)
+		if err != nil {
+			tm.hot.metrics.RecordError(&KeyError{Op: "demote", Key: tm.hot.errorKey(kv.Key), Err: err}, "")
+			continue
+		}
+		// Stored cold before leaving the hot tier, so readers always find it
+		tm.deleteColdLocked(kv.Key)
+		tm.cold.moveIn(kv.Key, data)
+		tm.coldBytes.Add(int64(len(data)))
+		keys = append(keys, kv.Key)
+	}
+	if len(keys) > 0 {
+		tm.hot.deleteKeys(keys)
+	}
+	return len(keys)
+}
+
+// demoteLoop runs Demote every Config.ColdAfter until ctx is canceled
+func (tm *TieredShrinkableMap[K, V]) demoteLoop(ctx context.Context) {
+	defer close(tm.done)
+	defer tm.demoteStatus.exit(false)
+	ticker := time.NewTicker(tm.config.ColdAfter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tm.demoteStatus.tick()
+			if tm.Demote() > 0 {
+				tm.demoteStatus.active()
+			}
+		}
+	}
+}
+
+// Contains reports whether key is present in either tier without promoting it
+func (tm *TieredShrinkableMap[K, V]) Contains(key K) bool {
+	return tm.hot.Contains(key) || tm.cold.Contains(key)
+}
+
+// Delete removes the entry for the given key from whichever tier holds it
+func (tm *TieredShrinkableMap[K, V]) Delete(key K) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.hot.Delete(key) || tm.deleteColdLocked(key)
+}
+
+// deleteColdLocked removes the cold copy of key. Must be called with tm.mu held.
+func (tm *TieredShrinkableMap[K, V]) deleteColdLocked(key K) bool {
+	data, ok := tm.cold.Get(key)
+	if !ok {
+		return false
+	}
+	tm.cold.Delete(key)
+	tm.coldBytes.Add(-int64(len(data)))
+	return true
+}
+
+// Snapshot returns all entries of both tiers without promoting cold entries.
+// Cold entries that fail to decode are omitted.
+func (tm *TieredShrinkableMap[K, V]) Snapshot() []KeyValue[K, V] {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	hot, cold := tm.hot.Snapshot(), tm.cold.Snapshot()
+	result := make([]KeyValue[K, V], 0, len(hot)+len(cold))
+	for _, kv := range hot {
+		result = append(result, KeyValue[K, V]{Key: kv.Key, Value: kv.Value.value})
+	}
+	for _, kv := range cold {
+		if _, value, err := tm.codec.DecodeEntry(kv.Value); err == nil {
+			result = append(result, KeyValue[K, V]{Key: kv.Key, Value: value})
+		}
+	}
+	return result
+}
+
+// Len returns the current number of items in both tiers
+func (tm *TieredShrinkableMap[K, V]) Len() int64 {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return tm.hot.Len() + tm.cold.Len()
+}
+
+// GetMetrics returns a copy of the current metrics of the hot tier
+func (tm *TieredShrinkableMap[K, V]) GetMetrics() Metrics {
+	return tm.hot.GetMetrics()
+}
+
+// Stats returns size and capacity statistics of the hot tier along with the
+// sizes of both tiers
+func (tm *TieredShrinkableMap[K, V]) Stats() Stats {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	stats := tm.hot.Stats()
+	stats.HotLen = stats.Len
+	stats.ColdLen = tm.cold.Len()
+	stats.ColdBytes = tm.coldBytes.Load()
+	stats.Len += stats.ColdLen
+	return stats
+}
+
+// TryShrink attempts to shrink both tiers if their conditions are met
+func (tm *TieredShrinkableMap[K, V]) TryShrink() bool {
+	hot := tm.hot.TryShrink()
+	return tm.cold.TryShrink() || hot
+}
+
+// ForceShrink immediately shrinks both tiers regardless of conditions
+func (tm *TieredShrinkableMap[K, V]) ForceShrink() bool {
+	hot := tm.hot.ForceShrink()
+	return tm.cold.ForceShrink() || hot
+}
+
+// Stop terminates the demotion and auto-shrink goroutines
+func (tm *TieredShrinkableMap[K, V]) Stop() {
+	tm.cancel()
+	<-tm.done
+	tm.hot.Stop()
+	tm.cold.Stop()
+}
+
+// moveIn stores an entry moved from another map, skipping the write hooks
+// and memory pressure checks it passed when first written
+func (sm *ShrinkableMap[K, V]) moveIn(key K, value V) {
+	sm.mu.Lock()
+	needsShrink := sm.setLocked(key, value)
+	sm.mu.Unlock()
+
+	if needsShrink {
+		sm.TryShrink()
+	}
+}
@@ -0,0 +1,137 @@
+package shrinkmap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTieredShrinkableMap(t *testing.T) {
+	newTiered := func(t *testing.T, config Config) (*TieredShrinkableMap[string, string], *time.Time) {
+		tm := NewTiered[string, string](config, JSONCodec[string, string]{})
+		t.Cleanup(tm.Stop)
+		now := time.Now()
+		tm.now = func() time.Time { return now }
+		return tm, &now
+	}
+
+	t.Run("Demotion And Promotion", func(t *testing.T) {
+		tm, now := newTiered(t, DefaultConfig().WithAutoShrinkEnabled(false).WithColdAfter(time.Hour))
+
+		tm.Set("idle", "a")
+		tm.Set("busy", "b")
+		*now = now.Add(40 * time.Minute)
+		tm.Get("busy")
+		*now = now.Add(40 * time.Minute)
+
+		if n := tm.Demote(); n != 1 {
+			t.Fatalf("Expected 1 demoted entry, got %d", n)
+		}
+		stats := tm.Stats()
+		if stats.Len != 2 || stats.HotLen != 1 || stats.ColdLen != 1 || stats.ColdBytes == 0 {
+			t.Errorf("Unexpected tier sizes: %+v", stats)
+		}
+		if !tm.Contains("idle") || tm.Stats().ColdLen != 1 {
+			t.Error("Expected Contains to find the cold entry without promoting it")
+		}
+
+		if v, ok := tm.Get("idle"); !ok || v != "a" {
+			t.Errorf("Expected promoted value a, got %q, %v", v, ok)
+		}
+		stats = tm.Stats()
+		if stats.HotLen != 2 || stats.ColdLen != 0 || stats.ColdBytes != 0 {
+			t.Errorf("Expected both entries hot after promotion, got %+v", stats)
+		}
+	})
+
+	t.Run("Writes And Deletes Cold Entries", func(t *testing.T) {
+		tm, _ := newTiered(t, DefaultConfig().WithAutoShrinkEnabled(false))
+
+		tm.Set("a", "1")
+		tm.Set("b", "2")
+		if n := tm.DemoteAll(); n != 2 {
+			t.Fatalf("Expected 2 demoted entries, got %d", n)
+		}
+		if n := tm.Demote(); n != 0 {
+			t.Errorf("Expected no demotion without ColdAfter, got %d", n)
+		}
+
+		tm.Set("a", "10")
+		if v, _ := tm.Get("a"); v != "10" || tm.Stats().ColdLen != 1 {
+			t.Errorf("Expected the write to replace the cold copy, got %q, %+v", v, tm.Stats())
+		}
+		if !tm.Delete("b") || tm.Contains("b") || tm.Len() != 1 || tm.Stats().ColdBytes != 0 {
+			t.Errorf("Expected the cold entry to be deleted, got %+v", tm.Stats())
+		}
+
+		tm.Set("c", "3")
+		tm.DemoteAll()
+		if snapshot := tm.Snapshot(); len(snapshot) != 2 {
+			t.Errorf("Expected 2 entries in snapshot, got %v", snapshot)
+		}
+	})
+
+	t.Run("Value Hooks", func(t *testing.T) {
+		transforms := 0
+		config := DefaultConfig().WithMaxValueBytes(3, nil)
+		config.TransformOnSet = func(key, value any) any {
+			transforms++
+			return value.(string) + "!"
+		}
+		tm, _ := newTiered(t, config)
+
+		if err := tm.Set("a", "too long"); err == nil {
+			t.Error("Expected the hot tier to reject an oversized value")
+		}
+		tm.Set("b", "x")
+		tm.DemoteAll()
+		if v, _ := tm.Get("b"); v != "x!" || transforms != 2 {
+			t.Errorf("Expected the value to be transformed once, got %q after %d transforms", v, transforms)
+		}
+	})
+
+	t.Run("Background Demotion", func(t *testing.T) {
+		tm := NewTiered[string, int](DefaultConfig().WithColdAfter(10*time.Millisecond), nil)
+		defer tm.Stop()
+
+		tm.Set("a", 1)
+		deadline := time.Now().Add(2 * time.Second)
+		for tm.Stats().ColdLen != 1 && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if stats := tm.Stats(); stats.ColdLen != 1 {
+			t.Fatalf("Expected the idle entry to be demoted, got %+v", stats)
+		}
+		if v, ok := tm.Get("a"); !ok || v != 1 {
+			t.Errorf("Expected gob-decoded value 1, got %d, %v", v, ok)
+		}
+	})
+
+	t.Run("Concurrent Access", func(t *testing.T) {
+		tm := NewTiered[int, int](DefaultConfig(), nil)
+		defer tm.Stop()
+
+		var wg sync.WaitGroup
+		for w := 0; w < 4; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 200; i++ {
+					key := w*1000 + i
+					tm.Set(key, i)
+					if i%10 == 0 {
+						tm.DemoteAll()
+					}
+					if v, ok := tm.Get(key); !ok || v != i {
+						t.Errorf("Expected %d for key %d, got %d, %v", i, key, v, ok)
+						return
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		if tm.Len() != 800 {
+			t.Errorf("Expected 800 entries, got %d", tm.Len())
+		}
+	})
+}
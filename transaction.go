@@ -0,0 +1,207 @@
+package shrinkmap
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"unsafe"
+)
+
+// MapBatch binds a batch of operations to the map it is applied to.
+// Batches for maps with different key and value types can be combined in
+// one ApplyAtomic call.
+type MapBatch interface {
+	nilMap() bool
+	mapID() uint64
+	lock()
+	unlock()
+	prepare() error
+	stage() error
+	apply()
+	afterCommit()
+}
+
+type mapBatch[K comparable, V any] struct {
+	sm       *ShrinkableMap[K, V]
+	batch    BatchOperations[K, V]
+	prepared BatchOperations[K, V]
+}
+
+// WithBatch binds batch to sm for use with ApplyAtomic
+func WithBatch[K comparable, V any](sm *ShrinkableMap[K, V], batch BatchOperations[K, V]) MapBatch {
+	return &mapBatch[K, V]{sm: sm, batch: batch}
+}
+
+func (b *mapBatch[K, V]) nilMap() bool  { return b.sm == nil }
+func (b *mapBatch[K, V]) mapID() uint64 { return b.sm.id }
+func (b *mapBatch[K, V]) lock()         { b.sm.mu.Lock() }
+func (b *mapBatch[K, V]) unlock()       { b.sm.mu.Unlock() }
+func (b *mapBatch[K, V]) apply()        { b.sm.applyBatchLocked(b.prepared) }
+
+// prepare runs the write hooks on the batch. It does not need the map lock.
+func (b *mapBatch[K, V]) prepare() error {
+	prepared, failed := b.sm.prepareBatchOps(b.batch, BatchAtomic)
+	if len(failed) > 0 {
+		return failed[0]
+	}
+	b.prepared = prepared
+	return nil
+}
+
+// stage checks the prepared batch against the current contents of the map.
+// Must be called with the map lock held.
+func (b *mapBatch[K, V]) stage() error {
+	staged, failed := b.sm.stageBatchLocked(b.prepared, nil, BatchAtomic, nil)
+	if len(failed) > 0 {
+		return failed[0]
+	}
+	b.prepared = staged
+	return nil
+}
+
+func (b *mapBatch[K, V]) afterCommit() {
+	if b.sm.config.AutoShrinkEnabled {
+		b.sm.requestShrink()
+	}
+}
+
+// ApplyAtomic applies the batches to their maps as a single all-or-nothing
+// transaction. The locks of all involved maps are acquired in a global order,
+// so concurrent transactions over overlapping maps cannot deadlock. Every batch
+// is validated before anything is applied; if any validation fails no map is
+// modified. Batches targeting the same map are applied in argument order.
+// The write hooks run before any lock is taken, like for Set.
+func ApplyAtomic(batches ...MapBatch) error {
+	for i, b := range batches {
+		if b == nil {
+			return fmt.Errorf("batch %d is nil", i)
+		}
+		if b.nilMap() {
+			return fmt.Errorf("batch %d targets a nil map", i)
+		}
+	}
+
+	for i, b := range batches {
+		if err := b.prepare(); err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+	}
+
+	// Lock every distinct map once, ordered by map id
+	ordered := make([]MapBatch, len(batches))
+	copy(ordered, batches)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].mapID() < ordered[j].mapID()
+	})
+	var locked []MapBatch
+	for i, b := range ordered {
+		if i > 0 && b.mapID() == ordered[i-1].mapID() {
+			continue
+		}
+		b.lock()
+		locked = append(locked, b)
+	}
+
+	err := func() error {
+		for i, b := range batches {
+			if err := b.stage(); err != nil {
+				return fmt.Errorf("batch %d: %w", i, err)
+			}
+		}
+		for _, b := range batches {
+			b.apply()
+		}
+		return nil
+	}()
+
+	for i := len(locked) - 1; i >= 0; i-- {
+		locked[i].unlock()
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, b := range locked {
+		b.afterCommit()
+	}
+	return nil
+}
+
+// sameValue reports whether a and b have the same representation in memory,
+// i.e. b is still the value a was read as rather than a value stored since.
+// It works for any type, including ones that are not comparable.
+func sameValue[V any](a, b V) bool {
+	size := unsafe.Sizeof(a)
+	if size == 0 {
+		return true
+	}
+	return bytes.Equal(
+		unsafe.Slice((*byte)(unsafe.Pointer(&a)), size),
+		unsafe.Slice((*byte)(unsafe.Pointer(&b)), size),
+	)
+}
+
+// Move atomically removes key from src and stores its value in dst.
+// Both maps are locked while the entry is moved, so concurrent readers and
+// movers observe the entry in exactly one of the maps. Returns ErrKeyNotFound
+// if src does not contain key; an existing entry in dst is overwritten.
+// The value passes through the write hooks of dst as if written by Set, and
+// the move fails without changing either map if dst rejects it.
+// Returns ErrMemoryPressure if dst rejects new keys under memory pressure.
+//
+// Like Set, the hooks run without any lock held. If the entry changes in src
+// while they run, the move starts over with the new value.
+func Move[K comparable, V any](src, dst *ShrinkableMap[K, V], key K) error {
+	if src == nil || dst == nil {
+		return fmt.Errorf("source and destination maps must not be nil")
+	}
+	if src == dst {
+		return fmt.Errorf("source and destination must be different maps")
+	}
+
+	first, second := src, dst
+	if second.id < first.id {
+		first, second = second, first
+	}
+
+	var dstNeedsShrink bool
+	for {
+		src.mu.RLock()
+		value, exists := src.data[key]
+		src.mu.RUnlock()
+		if !exists {
+			return ErrKeyNotFound
+		}
+		prepared, err := dst.prepareWrite("move", key, value)
+		if err != nil {
+			return err
+		}
+
+		first.mu.Lock()
+		second.mu.Lock()
+		current, exists := src.data[key]
+		changed := !exists || !sameValue(current, value)
+		if !changed {
+			if err = dst.checkInsertLocked("move", key); err == nil {
+				src.deleteLocked(key)
+				dstNeedsShrink = dst.setLocked(key, prepared)
+			}
+		}
+		second.mu.Unlock()
+		first.mu.Unlock()
+
+		if err != nil {
+			return err
+		}
+		if !changed {
+			break
+		}
+	}
+	if src.config.AutoShrinkEnabled {
+		src.TryShrink()
+	}
+	if dstNeedsShrink {
+		dst.TryShrink()
+	}
+	return nil
+}
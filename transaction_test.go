@@ -0,0 +1,285 @@
+package shrinkmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestApplyAtomic(t *testing.T) {
+	t.Run("Forward And Reverse Index", func(t *testing.T) {
+		forward := New[string, int](DefaultConfig())
+		defer forward.Stop()
+		reverse := New[int, string](DefaultConfig())
+		defer reverse.Stop()
+
+		err := ApplyAtomic(
+			WithBatch(forward, BatchOperations[string, int]{
+				Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "alice", Value: 1}},
+			}),
+			WithBatch(reverse, BatchOperations[int, string]{
+				Operations: []BatchOperation[int, string]{{Type: BatchSet, Key: 1, Value: "alice"}},
+			}),
+		)
+		if err != nil {
+			t.Fatalf("ApplyAtomic failed: %v", err)
+		}
+
+		if v, _ := forward.Get("alice"); v != 1 {
+			t.Errorf("Expected alice=1, got %d", v)
+		}
+		if v, _ := reverse.Get(1); v != "alice" {
+			t.Errorf("Expected 1=alice, got %q", v)
+		}
+	})
+
+	t.Run("All Or Nothing", func(t *testing.T) {
+		a := New[string, int](DefaultConfig())
+		defer a.Stop()
+		b := New[string, int](DefaultConfig())
+		defer b.Stop()
+
+		err := ApplyAtomic(
+			WithBatch(a, BatchOperations[string, int]{
+				Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "x", Value: 1}},
+			}),
+			WithBatch(b, BatchOperations[string, int]{
+				Operations: []BatchOperation[string, int]{{Type: BatchOpType(99), Key: "y"}},
+			}),
+		)
+		if err == nil {
+			t.Fatal("Expected error for invalid operation")
+		}
+		if a.Len() != 0 || b.Len() != 0 {
+			t.Error("No map should be modified when a batch fails validation")
+		}
+	})
+
+	t.Run("Same Map Twice", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+
+		err := ApplyAtomic(
+			WithBatch(sm, BatchOperations[string, int]{
+				Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "k", Value: 1}},
+			}),
+			WithBatch(sm, BatchOperations[string, int]{
+				Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "k", Value: 2}},
+			}),
+		)
+		if err != nil {
+			t.Fatalf("ApplyAtomic failed: %v", err)
+		}
+		if v, _ := sm.Get("k"); v != 2 {
+			t.Errorf("Expected batches applied in order, got k=%d", v)
+		}
+	})
+
+	t.Run("Opposite Order Does Not Deadlock", func(t *testing.T) {
+		a := New[int, int](DefaultConfig())
+		defer a.Stop()
+		b := New[int, int](DefaultConfig())
+		defer b.Stop()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				for j := 0; j < 200; j++ {
+					first, second := a, b
+					if id%2 == 1 {
+						first, second = b, a
+					}
+					ops := BatchOperations[int, int]{
+						Operations: []BatchOperation[int, int]{{Type: BatchSet, Key: j, Value: id}},
+					}
+					if err := ApplyAtomic(WithBatch(first, ops), WithBatch(second, ops)); err != nil {
+						t.Errorf("ApplyAtomic failed: %v", err)
+						return
+					}
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		for j := 0; j < 200; j++ {
+			va, _ := a.Get(j)
+			vb, _ := b.Get(j)
+			if va != vb {
+				t.Fatalf("Maps diverged at key %d: %d != %d", j, va, vb)
+			}
+		}
+	})
+
+	t.Run("Hooks Run Without Locks", func(t *testing.T) {
+		var a, b *ShrinkableMap[string, int]
+		readBoth := func(k, v any) any {
+			a.Get(k.(string))
+			b.Get(k.(string))
+			return v
+		}
+		a = New[string, int](DefaultConfig().WithTransformOnSet(readBoth))
+		defer a.Stop()
+		b = New[string, int](DefaultConfig().WithTransformOnSet(readBoth))
+		defer b.Stop()
+
+		set := BatchOperations[string, int]{Operations: []BatchOperation[string, int]{{Type: BatchSet, Key: "k", Value: 1}}}
+		done := make(chan error, 1)
+		go func() { done <- ApplyAtomic(WithBatch(a, set), WithBatch(b, set)) }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("ApplyAtomic failed: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("ApplyAtomic deadlocked on a hook reading the maps")
+		}
+		if !a.Contains("k") || !b.Contains("k") {
+			t.Error("Expected both batches to be applied")
+		}
+	})
+
+	t.Run("Nil Map", func(t *testing.T) {
+		if err := ApplyAtomic(WithBatch[string, int](nil, BatchOperations[string, int]{})); err == nil {
+			t.Error("Expected error for nil map")
+		}
+	})
+}
+
+func TestMove(t *testing.T) {
+	t.Run("Moves Entry", func(t *testing.T) {
+		pending := New[string, int](DefaultConfig())
+		defer pending.Stop()
+		active := New[string, int](DefaultConfig())
+		defer active.Stop()
+		pending.Set("job", 42)
+
+		if err := Move(pending, active, "job"); err != nil {
+			t.Fatalf("Move failed: %v", err)
+		}
+		if pending.Contains("job") {
+			t.Error("Entry should be removed from source")
+		}
+		if v, _ := active.Get("job"); v != 42 {
+			t.Errorf("Expected job=42 in destination, got %d", v)
+		}
+		if pending.Len() != 0 || active.Len() != 1 {
+			t.Errorf("Unexpected lengths: src=%d dst=%d", pending.Len(), active.Len())
+		}
+	})
+
+	t.Run("Missing Key", func(t *testing.T) {
+		a := New[string, int](DefaultConfig())
+		defer a.Stop()
+		b := New[string, int](DefaultConfig())
+		defer b.Stop()
+
+		if err := Move(a, b, "missing"); err != ErrKeyNotFound {
+			t.Errorf("Expected ErrKeyNotFound, got %v", err)
+		}
+		if err := Move(a, a, "missing"); err == nil {
+			t.Error("Expected error moving within the same map")
+		}
+	})
+
+	t.Run("Destination Write Hooks", func(t *testing.T) {
+		a := New[string, int](DefaultConfig())
+		defer a.Stop()
+		b := New[string, int](DefaultConfig().
+			WithTransformOnSet(func(_, v any) any { return v.(int) * 10 }).
+			WithValidators(nil, func(v any) error {
+				if v.(int) > 100 {
+					return errors.New("too large")
+				}
+				return nil
+			}))
+		defer b.Stop()
+		a.Set("ok", 1)
+		a.Set("big", 20)
+
+		if err := Move(a, b, "ok"); err != nil {
+			t.Fatalf("Move failed: %v", err)
+		}
+		if v, _ := b.Get("ok"); v != 10 {
+			t.Errorf("Expected the moved value to be transformed, got %d", v)
+		}
+		var verr *ValidationError
+		if err := Move(a, b, "big"); !errors.As(err, &verr) {
+			t.Errorf("Expected a *ValidationError, got %v", err)
+		}
+		if !a.Contains("big") || b.Contains("big") {
+			t.Error("A rejected move must leave both maps unchanged")
+		}
+	})
+
+	t.Run("Hooks Run Without Locks", func(t *testing.T) {
+		var a, b *ShrinkableMap[string, int]
+		rewritten := false
+		a = New[string, int](DefaultConfig())
+		defer a.Stop()
+		b = New[string, int](DefaultConfig().WithTransformOnSet(func(k, v any) any {
+			// Hooks may read both maps, and the entry may change meanwhile
+			a.Get(k.(string))
+			b.Get(k.(string))
+			if !rewritten {
+				rewritten = true
+				a.Set(k.(string), 2)
+			}
+			return v.(int) * 10
+		}))
+		defer b.Stop()
+		a.Set("job", 1)
+
+		done := make(chan error, 1)
+		go func() { done <- Move(a, b, "job") }()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatalf("Move failed: %v", err)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Move deadlocked on a hook reading the maps")
+		}
+		if v, _ := b.Get("job"); v != 20 || a.Contains("job") {
+			t.Errorf("Expected the rewritten value to be moved, got %d", v)
+		}
+	})
+
+	t.Run("Concurrent Moves Preserve Value Once", func(t *testing.T) {
+		a := New[int, int](DefaultConfig())
+		defer a.Stop()
+		b := New[int, int](DefaultConfig())
+		defer b.Stop()
+		const keys = 100
+		for i := 0; i < keys; i++ {
+			a.Set(i, i)
+		}
+
+		var wg sync.WaitGroup
+		for g := 0; g < 4; g++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				for round := 0; round < 50; round++ {
+					for i := 0; i < keys; i++ {
+						if id%2 == 0 {
+							_ = Move(a, b, i)
+						} else {
+							_ = Move(b, a, i)
+						}
+					}
+				}
+			}(g)
+		}
+		wg.Wait()
+
+		for i := 0; i < keys; i++ {
+			inA, inB := a.Contains(i), b.Contains(i)
+			if inA == inB {
+				t.Fatalf("Key %d should be in exactly one map (a=%v, b=%v)", i, inA, inB)
+			}
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrValidation is matched by errors.Is for every ValidationError
+var ErrValidation = errors.New("shrinkmap: validation failed")
+
+// ValidationError reports a key or value rejected by Config.ValidateKey or
+// Config.ValidateValue. Nothing is written when it is returned.
+type ValidationError struct {
+	Op    string // operation that was rejected, e.g. "set" or "batch"
+	Field string // "key" or "value"
+	Key   interface{}
+	Err   error
+}
+
+func (e *ValidationError) Error() string {
+	if e.Op != "" {
+		return fmt.Sprintf("%s: %s: invalid %s for key %v: %v", ErrValidation, e.Op, e.Field, e.Key, e.Err)
+	}
+	return fmt.Sprintf("%s: invalid %s for key %v: %v", ErrValidation, e.Field, e.Key, e.Err)
+}
+
+// Is reports whether target is ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// Unwrap returns the error returned by the validation function
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// SizerFunc returns the size of a value in bytes for Config.MaxValueBytes
+type SizerFunc func(value any) int
+
+// defaultSizer measures string and byte slice values, including named types
+// based on them. Values of other types measure 0.
+func defaultSizer(value any) int {
+	switch v := value.(type) {
+	case string:
+		return len(v)
+	case []byte:
+		return len(v)
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Kind() == reflect.String:
+		return rv.Len()
+	case rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8:
+		return rv.Len()
+	}
+	return 0
+}
+
+// prepareWrite applies Config.TransformOnSet to a key-value pair and validates
+// the result before it is written. op names the operation in returned errors.
+// It does not need the map lock.
+func (sm *ShrinkableMap[K, V]) prepareWrite(op string, key K, value V) (V, error) {
+	if sm.config.TransformOnSet != nil {
+		result := sm.config.TransformOnSet(key, value)
+		transformed, ok := result.(V)
+		if !ok {
+			return value, &KeyError{Op: op, Key: sm.errorKey(key), Err: fmt.Errorf("transform returned %T, want %T", result, value)}
+		}
+		value = transformed
+	}
+	if sm.config.CopyOnWrite {
+		cloned, err := sm.cloneValue(value)
+		if err != nil {
+			return value, &KeyError{Op: op, Key: sm.errorKey(key), Err: err}
+		}
+		value = cloned
+	}
+	return value, sm.validateWrite(op, key, value)
+}
+
+// validateWrite applies the key and value validators and Config.MaxValueBytes
+// to a pair about to be written
+func (sm *ShrinkableMap[K, V]) validateWrite(op string, key K, value V) error {
+	if sm.config.ValidateKey != nil {
+		if err := sm.config.ValidateKey(key); err != nil {
+			return &ValidationError{Op: op, Field: "key", Key: sm.errorKey(key), Err: err}
+		}
+	}
+	if sm.config.ValidateValue != nil {
+		if err := sm.config.ValidateValue(value); err != nil {
+			return &ValidationError{Op: op, Field: "value", Key: sm.errorKey(key), Err: err}
+		}
+	}
+	if sm.config.MaxValueBytes > 0 {
+		sizer := sm.config.ValueSizer
+		if sizer == nil {
+			sizer = defaultSizer
+		}
+		if sizer(value) > sm.config.MaxValueBytes {
+			sm.metrics.recordOversizedValue()
+			return &KeyError{Op: op, Key: sm.errorKey(key), Err: ErrValueTooLarge}
+		}
+	}
+	return nil
+}
+
+// prepareBatch applies prepareWrite to every set operation of the batch.
+// The batch is copied if values are transformed or cloned, so the caller's
+// batch is never modified and holds no references to stored values.
+func (sm *ShrinkableMap[K, V]) prepareBatch(batch BatchOperations[K, V]) (BatchOperations[K, V], error) {
+	prepared, failed := sm.prepareBatchOps(batch, BatchAtomic)
+	if len(failed) > 0 {
+		return prepared, failed[0].Err
+	}
+	return prepared, nil
+}
+
+// prepareBatchOps is prepareBatch reporting every failing operation. In
+// atomic mode it stops at the first failure.
+func (sm *ShrinkableMap[K, V]) prepareBatchOps(batch BatchOperations[K, V], mode BatchMode) (BatchOperations[K, V], []*BatchError) {
+	if sm.config.TransformOnSet != nil || sm.config.CopyOnWrite {
+		batch = BatchOperations[K, V]{Operations: append([]BatchOperation[K, V](nil), batch.Operations...)}
+	}
+	var failed []*BatchError
+	for i, op := range batch.Operations {
+		if op.Type != BatchSet {
+			continue
+		}
+		value, err := sm.prepareWrite("batch", op.Key, op.Value)
+		if err != nil {
+			failed = append(failed, &BatchError{Index: i, Key: sm.errorKey(op.Key), Err: err})
+			if mode == BatchAtomic {
+				return batch, failed
+			}
+			continue
+		}
+		batch.Operations[i].Value = value
+	}
+	return batch, failed
+}
+
+// checkRead applies Config.ValidateOnGet to a value read for key. Invalid
+// entries are reported as missing and, if repair is set and
+// Config.DeleteInvalidOnGet is enabled, deleted from the map.
+func (sm *ShrinkableMap[K, V]) checkRead(key K, value V, exists, repair bool) (V, bool) {
+	if !exists || sm.config.ValidateOnGet == nil || sm.config.ValidateOnGet(key, value) {
+		if exists {
+			value = sm.copyOnRead(value)
+		}
+		return value, exists
+	}
+	sm.metrics.recordInvalidRead()
+	if repair && sm.config.DeleteInvalidOnGet {
+		sm.deleteInvalid(key)
+	}
+	var zero V
+	return zero, false
+}
+
+// deleteInvalid deletes key if its current value still fails ValidateOnGet,
+// so a concurrent write of a valid value is not lost
+func (sm *ShrinkableMap[K, V]) deleteInvalid(key K) {
+	sm.mu.Lock()
+	value, exists := sm.data[key]
+	deleted := exists && !sm.config.ValidateOnGet(key, value)
+	if deleted {
+		sm.deleteLocked(key)
+	}
+	sm.mu.Unlock()
+
+	if deleted && sm.config.AutoShrinkEnabled {
+		sm.TryShrink()
+	}
+}
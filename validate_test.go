@@ -0,0 +1,257 @@
+package shrinkmap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxValueBytes(t *testing.T) {
+	t.Run("Default Sizer", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithMaxValueBytes(8, nil))
+		defer sm.Stop()
+
+		if err := sm.SetChecked("small", "12345678"); err != nil {
+			t.Errorf("Expected value at the limit to be stored, got %v", err)
+		}
+		err := sm.SetChecked("large", "123456789")
+		if !errors.Is(err, ErrValueTooLarge) {
+			t.Errorf("Expected ErrValueTooLarge, got %v", err)
+		}
+		var keyErr *KeyError
+		if !errors.As(err, &keyErr) || keyErr.Key != "large" {
+			t.Errorf("Expected *KeyError for key large, got %v", err)
+		}
+		if _, exists := sm.Get("large"); exists {
+			t.Error("Oversized value must not be stored")
+		}
+
+		metrics := sm.GetMetrics()
+		if metrics.OversizedValues() != 1 {
+			t.Errorf("Expected 1 oversized value, got %d", metrics.OversizedValues())
+		}
+	})
+
+	t.Run("Custom Sizer", func(t *testing.T) {
+		sizer := func(v any) int { return len(v.([]string)) }
+		sm := New[string, []string](DefaultConfig().WithMaxValueBytes(2, sizer))
+		defer sm.Stop()
+
+		if err := sm.SetChecked("a", []string{"x", "y", "z"}); !errors.Is(err, ErrValueTooLarge) {
+			t.Errorf("Expected ErrValueTooLarge, got %v", err)
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithMaxValueBytes(4, nil))
+		defer sm.Stop()
+
+		err := sm.ApplyBatch(BatchOperations[string, string]{Operations: []BatchOperation[string, string]{
+			{Type: BatchSet, Key: "a", Value: "ok"},
+			{Type: BatchSet, Key: "b", Value: strings.Repeat("x", 5)},
+		}})
+		if !errors.Is(err, ErrValueTooLarge) {
+			t.Errorf("Expected ErrValueTooLarge, got %v", err)
+		}
+		if sm.Len() != 0 {
+			t.Error("Rejected batch must not be partially applied")
+		}
+	})
+
+	t.Run("Named Types", func(t *testing.T) {
+		type blob []byte
+		type text string
+		if defaultSizer(blob("abc")) != 3 || defaultSizer(text("abcd")) != 4 || defaultSizer(42) != 0 {
+			t.Error("Unexpected default sizes")
+		}
+	})
+}
+
+func TestValidators(t *testing.T) {
+	errEmpty := errors.New("empty key")
+	errNegative := errors.New("negative value")
+	config := DefaultConfig().WithValidators(
+		func(key any) error {
+			if key.(string) == "" {
+				return errEmpty
+			}
+			return nil
+		},
+		func(value any) error {
+			if value.(int) < 0 {
+				return errNegative
+			}
+			return nil
+		},
+	)
+
+	t.Run("Set", func(t *testing.T) {
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		err := sm.SetChecked("", 1)
+		var validationErr *ValidationError
+		if !errors.Is(err, ErrValidation) || !errors.Is(err, errEmpty) || !errors.As(err, &validationErr) {
+			t.Fatalf("Expected *ValidationError wrapping errEmpty, got %v", err)
+		}
+		if validationErr.Field != "key" || validationErr.Op != "set" {
+			t.Errorf("Expected key field of set, got %q of %q", validationErr.Field, validationErr.Op)
+		}
+
+		if err := sm.SetChecked("a", -1); !errors.Is(err, errNegative) {
+			t.Errorf("Expected errNegative, got %v", err)
+		}
+		if err := sm.SetChecked("a", 1); err != nil {
+			t.Errorf("Expected valid pair to be stored, got %v", err)
+		}
+		if sm.Len() != 1 {
+			t.Errorf("Expected only the valid pair to be stored, got %d", sm.Len())
+		}
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		sm := New[string, int](config)
+		defer sm.Stop()
+
+		err := sm.ApplyBatch(BatchOperations[string, int]{Operations: []BatchOperation[string, int]{
+			{Type: BatchSet, Key: "a", Value: 1},
+			{Type: BatchSet, Key: "b", Value: -1},
+		}})
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected ErrValidation, got %v", err)
+		}
+		if sm.Len() != 0 {
+			t.Error("Rejected batch must not be partially applied")
+		}
+	})
+}
+
+func TestTransformOnSet(t *testing.T) {
+	lower := func(key, value any) any { return strings.ToLower(value.(string)) }
+
+	t.Run("Set And Batch", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithTransformOnSet(lower))
+		defer sm.Stop()
+
+		sm.Set("a", "HeLLo")
+		if v, _ := sm.Get("a"); v != "hello" {
+			t.Errorf("Expected transformed value, got %q", v)
+		}
+
+		batch := BatchOperations[string, string]{Operations: []BatchOperation[string, string]{
+			{Type: BatchSet, Key: "b", Value: "WORLD"},
+		}}
+		if err := sm.ApplyBatch(batch); err != nil {
+			t.Fatalf("ApplyBatch failed: %v", err)
+		}
+		if v, _ := sm.Get("b"); v != "world" {
+			t.Errorf("Expected transformed batch value, got %q", v)
+		}
+		if batch.Operations[0].Value != "WORLD" {
+			t.Error("Caller's batch must not be modified")
+		}
+	})
+
+	t.Run("Validated After Transform", func(t *testing.T) {
+		config := DefaultConfig().
+			WithTransformOnSet(lower).
+			WithValidators(nil, func(value any) error {
+				if value.(string) != strings.ToLower(value.(string)) {
+					return errors.New("not canonical")
+				}
+				return nil
+			})
+		sm := New[string, string](config)
+		defer sm.Stop()
+
+		if err := sm.SetChecked("a", "UPPER"); err != nil {
+			t.Errorf("Expected validation to see the transformed value, got %v", err)
+		}
+	})
+
+	t.Run("Wrong Result Type", func(t *testing.T) {
+		sm := New[string, string](DefaultConfig().WithTransformOnSet(func(key, value any) any { return 1 }))
+		defer sm.Stop()
+
+		if err := sm.SetChecked("a", "x"); err == nil {
+			t.Error("Expected error for transform returning the wrong type")
+		}
+		if sm.Len() != 0 {
+			t.Error("Value must not be stored")
+		}
+	})
+}
+
+func TestValidateOnGet(t *testing.T) {
+	positive := func(key, value any) bool { return value.(int) > 0 }
+
+	t.Run("Treated As Miss", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithValidateOnGet(positive, false))
+		defer sm.Stop()
+		sm.Set("valid", 1)
+		sm.Set("invalid", -1)
+
+		if v, ok := sm.Get("valid"); !ok || v != 1 {
+			t.Errorf("Expected valid entry, got %d, %v", v, ok)
+		}
+		if _, ok := sm.Get("invalid"); ok {
+			t.Error("Expected invalid entry to be a miss")
+		}
+		if _, ok, _ := sm.TryGet("invalid"); ok {
+			t.Error("Expected invalid entry to be a miss for TryGet")
+		}
+		if sm.GetOrDefault("invalid", 7) != 7 {
+			t.Error("Expected default for invalid entry")
+		}
+		if !sm.Contains("invalid") {
+			t.Error("Invalid entry must be kept without DeleteInvalidOnGet")
+		}
+
+		metrics := sm.GetMetrics()
+		if metrics.InvalidReads() != 3 {
+			t.Errorf("Expected 3 invalid reads, got %d", metrics.InvalidReads())
+		}
+	})
+
+	t.Run("Delete Invalid", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig().WithValidateOnGet(positive, true))
+		defer sm.Stop()
+		sm.Set("invalid", -1)
+
+		sm.Get("invalid")
+		if sm.Contains("invalid") {
+			t.Error("Expected invalid entry to be deleted on read")
+		}
+	})
+}
+
+func TestRedactKey(t *testing.T) {
+	redact := func(key any) any { return "<redacted>" }
+	config := DefaultConfig().
+		WithMaxValueBytes(1, nil).
+		WithRejectInsertsUnderPressure(true).
+		WithRedactKey(redact)
+	sm := New[string, string](config)
+	defer sm.Stop()
+
+	var keyErr *KeyError
+	if err := sm.SetChecked("secret", "too long"); !errors.As(err, &keyErr) || keyErr.Key != "<redacted>" {
+		t.Errorf("Expected redacted key for oversized value, got %v", err)
+	}
+	if msg := sm.SetChecked("secret", "too long").Error(); !strings.Contains(msg, "<redacted>") || strings.Contains(msg, "secret") {
+		t.Errorf("Expected the key to be masked in %q", msg)
+	}
+
+	sm.SetMemoryPressure(true)
+	if err := sm.SetChecked("secret", "v"); !errors.As(err, &keyErr) || keyErr.Key != "<redacted>" {
+		t.Errorf("Expected redacted key under memory pressure, got %v", err)
+	}
+
+	batch := BatchOperations[string, string]{
+		Operations: []BatchOperation[string, string]{{Type: BatchSet, Key: "secret", Value: "v"}},
+	}
+	var batchErr *BatchError
+	if err := sm.ApplyBatch(batch); !errors.As(err, &batchErr) || batchErr.Key != "<redacted>" {
+		t.Errorf("Expected redacted key in batch error, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package shrinkmap
+
+import (
+	"math"
+
+	"github.com/jongyunha/shrinkmap/internal/tdigest"
+)
+
+// valueStatsCompression trades t-digest size for percentile accuracy
+const valueStatsCompression = 200
+
+// ValueSummary describes the distribution of the values in a map.
+// Percentiles are estimated with a t-digest and are most accurate near the tails.
+type ValueSummary struct {
+	Count  int64
+	Mean   float64
+	StdDev float64 // population standard deviation
+	Min    float64
+	Max    float64
+	P50    float64
+	P90    float64
+	P95    float64
+	P99    float64
+
+	digest *tdigest.Digest
+}
+
+// Quantile returns the estimated value at quantile q in [0, 1], or NaN if the map was empty
+func (s ValueSummary) Quantile(q float64) float64 {
+	if s.digest == nil {
+		return math.NaN()
+	}
+	return s.digest.Quantile(q)
+}
+
+// ValueStats computes count, mean, standard deviation and percentiles of the
+// values in one pass under the read lock. The zero summary with NaN
+// percentiles is returned for an empty map.
+func ValueStats[K comparable, V Number](sm *ShrinkableMap[K, V]) ValueSummary {
+	digest := tdigest.New(valueStatsCompression)
+	var mean, m2 float64
+
+	sm.mu.RLock()
+	for _, v := range sm.data {
+		// Welford's algorithm keeps the variance numerically stable
+		x := float64(v)
+		digest.Add(x)
+		delta := x - mean
+		mean += delta / digest.Count()
+		m2 += delta * (x - mean)
+	}
+	sm.mu.RUnlock()
+
+	count := int64(digest.Count())
+	if count == 0 {
+		nan := math.NaN()
+		return ValueSummary{Min: nan, Max: nan, P50: nan, P90: nan, P95: nan, P99: nan}
+	}
+	return ValueSummary{
+		Count:  count,
+		Mean:   mean,
+		StdDev: math.Sqrt(m2 / float64(count)),
+		Min:    digest.Quantile(0),
+		Max:    digest.Quantile(1),
+		P50:    digest.Quantile(0.5),
+		P90:    digest.Quantile(0.9),
+		P95:    digest.Quantile(0.95),
+		P99:    digest.Quantile(0.99),
+		digest: digest,
+	}
+}
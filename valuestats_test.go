@@ -0,0 +1,57 @@
+package shrinkmap
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestValueStats(t *testing.T) {
+	t.Run("Uniform", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		for i := 1; i <= 10000; i++ {
+			sm.Set(i, i)
+		}
+
+		s := ValueStats(sm)
+		if s.Count != 10000 || math.Abs(s.Mean-5000.5) > 1e-6 || s.Min != 1 || s.Max != 10000 {
+			t.Errorf("Unexpected summary: %+v", s)
+		}
+		if expected := math.Sqrt((10000*10000 - 1) / 12.0); math.Abs(s.StdDev-expected) > 1e-6 {
+			t.Errorf("Expected stddev %v, got %v", expected, s.StdDev)
+		}
+		for q, got := range map[float64]float64{0.5: s.P50, 0.9: s.P90, 0.95: s.P95, 0.99: s.P99, 0.999: s.Quantile(0.999)} {
+			if expected := q * 10000; math.Abs(got-expected) > 10000*0.005 {
+				t.Errorf("Quantile %v: expected about %v, got %v", q, expected, got)
+			}
+		}
+	})
+
+	t.Run("Skewed Latencies", func(t *testing.T) {
+		sm := New[int, float64](DefaultConfig())
+		defer sm.Stop()
+		rng := rand.New(rand.NewSource(1))
+		values := make([]float64, 50000)
+		for i := range values {
+			values[i] = rng.ExpFloat64() * 10
+			sm.Set(i, values[i])
+		}
+
+		s := ValueStats(sm)
+		// For an exponential distribution the p99 is mean * ln(100)
+		if expected := 10 * math.Log(100); math.Abs(s.P99-expected)/expected > 0.05 {
+			t.Errorf("Expected p99 about %v, got %v", expected, s.P99)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		sm := New[string, float32](DefaultConfig())
+		defer sm.Stop()
+
+		s := ValueStats(sm)
+		if s.Count != 0 || !math.IsNaN(s.P50) || !math.IsNaN(s.Quantile(0.5)) {
+			t.Errorf("Expected empty summary, got %+v", s)
+		}
+	})
+}
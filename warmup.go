@@ -0,0 +1,82 @@
+package shrinkmap
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// WarmupOptions controls WarmupWithOptions
+type WarmupOptions struct {
+	// Maximum number of entries stored per second (0 for unlimited)
+	RatePerSec int
+
+	// Called periodically (about every 10ms of pacing) and at the end with the running totals
+	Progress func(WarmupReport)
+}
+
+// WarmupReport summarizes a warm-up
+type WarmupReport struct {
+	Loaded  int
+	Elapsed time.Duration
+}
+
+// Warmup populates the map from source at no more than ratePerSec entries per
+// second, so warming a cache after a deploy does not starve foreground
+// traffic. source has the shape of iter.Seq2[K, V]. It stops early when ctx
+// is done or a write fails; entries stored until then remain in the map.
+func (sm *ShrinkableMap[K, V]) Warmup(ctx context.Context, source func(yield func(K, V) bool), ratePerSec int) (WarmupReport, error) {
+	return sm.WarmupWithOptions(ctx, source, WarmupOptions{RatePerSec: ratePerSec})
+}
+
+// WarmupWithOptions is Warmup with progress reporting
+func (sm *ShrinkableMap[K, V]) WarmupWithOptions(ctx context.Context, source func(yield func(K, V) bool), opts WarmupOptions) (WarmupReport, error) {
+	if opts.RatePerSec < 0 {
+		return WarmupReport{}, fmt.Errorf("warm-up rate must be non-negative")
+	}
+
+	// Pace in steps of about 10ms worth of entries
+	step := max(opts.RatePerSec/100, 1)
+	start := time.Now()
+	var report WarmupReport
+	var err error
+
+	progress := func() {
+		if opts.Progress != nil {
+			report.Elapsed = time.Since(start)
+			opts.Progress(report)
+		}
+	}
+
+	source(func(key K, value V) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		if err = sm.SetChecked(key, value); err != nil {
+			err = fmt.Errorf("warm-up entry %d: %w", report.Loaded, err)
+			return false
+		}
+		report.Loaded++
+
+		if report.Loaded%step != 0 || opts.RatePerSec == 0 {
+			return true
+		}
+		progress()
+		target := start.Add(time.Duration(report.Loaded) * time.Second / time.Duration(opts.RatePerSec))
+		if wait := time.Until(target); wait > 0 {
+			timer := time.NewTimer(wait)
+			defer timer.Stop()
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+				return false
+			case <-timer.C:
+			}
+		}
+		return true
+	})
+
+	progress()
+	report.Elapsed = time.Since(start)
+	return report, err
+}
@@ -0,0 +1,78 @@
+package shrinkmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func countTo(n int) func(yield func(int, int) bool) {
+	return func(yield func(int, int) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(i, i) {
+				return
+			}
+		}
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	t.Run("Rate Limited", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+
+		var reports []WarmupReport
+		report, err := sm.WarmupWithOptions(context.Background(), countTo(200), WarmupOptions{
+			RatePerSec: 2000,
+			Progress:   func(r WarmupReport) { reports = append(reports, r) },
+		})
+		if err != nil {
+			t.Fatalf("Warmup failed: %v", err)
+		}
+		if report.Loaded != 200 || sm.Len() != 200 {
+			t.Errorf("Expected 200 entries, got %d, len=%d", report.Loaded, sm.Len())
+		}
+		if report.Elapsed < 90*time.Millisecond {
+			t.Errorf("Expected pacing to take about 100ms, took %v", report.Elapsed)
+		}
+		if len(reports) < 10 || reports[len(reports)-1].Loaded != 200 {
+			t.Errorf("Expected periodic progress ending at 200, got %d reports", len(reports))
+		}
+	})
+
+	t.Run("Unlimited", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+
+		report, err := sm.Warmup(context.Background(), countTo(10000), 0)
+		if err != nil || report.Loaded != 10000 {
+			t.Errorf("Expected 10000 entries, got %d, %v", report.Loaded, err)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+		report, err := sm.Warmup(ctx, countTo(10000), 1000)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected deadline error, got %v", err)
+		}
+		if report.Loaded == 0 || report.Loaded >= 10000 || sm.Len() != int64(report.Loaded) {
+			t.Errorf("Expected partial warm-up, got %d, len=%d", report.Loaded, sm.Len())
+		}
+	})
+
+	t.Run("Write Error", func(t *testing.T) {
+		reject := func(any) error { return errors.New("rejected") }
+		sm := New[int, int](DefaultConfig().WithValidators(reject, nil))
+		defer sm.Stop()
+
+		if _, err := sm.Warmup(context.Background(), countTo(10), 0); !errors.Is(err, ErrValidation) {
+			t.Errorf("Expected a validation error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package shrinkmap
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// ErrWatchChannelFull is recorded when a change event is dropped because a watch channel is full
+var ErrWatchChannelFull = errors.New("shrinkmap: watch channel full, change event dropped")
+
+type watcher[K comparable, V any] struct {
+	ch    chan<- ChangeEvent[K, V]
+	match func(K) bool
+}
+
+// Watch delivers change events for the keys accepted by match (all keys if
+// match is nil) to ch. Events are sent without blocking the writer; if ch is
+// full the event is dropped and ErrWatchChannelFull is recorded in the metrics.
+// The returned function cancels the subscription; ch must not be closed before
+// it is called. Subscriptions end when the map is stopped.
+func (sm *ShrinkableMap[K, V]) Watch(ch chan<- ChangeEvent[K, V], match func(K) bool) func() {
+	w := &watcher[K, V]{ch: ch, match: match}
+
+	sm.mu.Lock()
+	if !sm.stopped.Load() {
+		sm.watchers = append(sm.watchers, w)
+	}
+	sm.mu.Unlock()
+
+	return func() {
+		sm.mu.Lock()
+		defer sm.mu.Unlock()
+		for i, other := range sm.watchers {
+			if other == w {
+				sm.watchers = append(sm.watchers[:i:i], sm.watchers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// WatchPrefix is like Watch, but only delivers events for keys starting with
+// prefix. The map must have string keys.
+func (sm *ShrinkableMap[K, V]) WatchPrefix(prefix string, ch chan<- ChangeEvent[K, V]) (func(), error) {
+	if err := requireStringKeys[K](); err != nil {
+		return nil, err
+	}
+	return sm.Watch(ch, func(key K) bool {
+		return strings.HasPrefix(reflect.ValueOf(key).String(), prefix)
+	}), nil
+}
+
+// WatchPattern is like Watch, but only delivers events for keys matching the
+// glob pattern, using the syntax of path.Match. The map must have string keys.
+func (sm *ShrinkableMap[K, V]) WatchPattern(pattern string, ch chan<- ChangeEvent[K, V]) (func(), error) {
+	if err := requireStringKeys[K](); err != nil {
+		return nil, err
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid watch pattern %q: %w", pattern, err)
+	}
+	return sm.Watch(ch, func(key K) bool {
+		matched, _ := path.Match(pattern, reflect.ValueOf(key).String())
+		return matched
+	}), nil
+}
+
+// requireStringKeys fails unless K is string or a named type based on it
+func requireStringKeys[K comparable]() error {
+	if t := reflect.TypeOf((*K)(nil)).Elem(); t.Kind() != reflect.String {
+		return fmt.Errorf("key prefixes and patterns require string keys, not %s", t)
+	}
+	return nil
+}
+
+// notifyWatchers sends event to every matching watcher. Must be called with sm.mu held.
+func (sm *ShrinkableMap[K, V]) notifyWatchers(event ChangeEvent[K, V]) {
+	for _, w := range sm.watchers {
+		if w.match != nil && !w.match(event.Key) {
+			continue
+		}
+		select {
+		case w.ch <- sm.copyEvent(event):
+		default:
+			sm.metrics.RecordError(ErrWatchChannelFull, "")
+		}
+	}
+}
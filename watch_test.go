@@ -0,0 +1,90 @@
+package shrinkmap
+
+import (
+	"testing"
+)
+
+func TestWatch(t *testing.T) {
+	drain := func(ch chan ChangeEvent[string, int]) []string {
+		var keys []string
+		for {
+			select {
+			case e := <-ch:
+				keys = append(keys, e.Type.String()+":"+e.Key)
+			default:
+				return keys
+			}
+		}
+	}
+
+	t.Run("All Keys", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		ch := make(chan ChangeEvent[string, int], 10)
+		cancel := sm.Watch(ch, nil)
+
+		sm.Set("a", 1)
+		sm.Delete("a")
+		cancel()
+		sm.Set("b", 2)
+
+		if keys := drain(ch); len(keys) != 2 || keys[0] != ChangeSet.String()+":a" || keys[1] != ChangeDelete.String()+":a" {
+			t.Errorf("Unexpected events: %v", keys)
+		}
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		ch := make(chan ChangeEvent[string, int], 10)
+		if _, err := sm.WatchPrefix("user:", ch); err != nil {
+			t.Fatalf("WatchPrefix failed: %v", err)
+		}
+
+		sm.Set("user:1", 1)
+		sm.Set("session:1", 1)
+		if keys := drain(ch); len(keys) != 1 || keys[0] != ChangeSet.String()+":user:1" {
+			t.Errorf("Unexpected events: %v", keys)
+		}
+	})
+
+	t.Run("Pattern", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		ch := make(chan ChangeEvent[string, int], 10)
+		if _, err := sm.WatchPattern("user:*:name", ch); err != nil {
+			t.Fatalf("WatchPattern failed: %v", err)
+		}
+		if _, err := sm.WatchPattern("[", ch); err == nil {
+			t.Error("Expected error for malformed pattern")
+		}
+
+		sm.Set("user:1:name", 1)
+		sm.Set("user:1:email", 1)
+		if keys := drain(ch); len(keys) != 1 || keys[0] != ChangeSet.String()+":user:1:name" {
+			t.Errorf("Unexpected events: %v", keys)
+		}
+	})
+
+	t.Run("Non String Keys", func(t *testing.T) {
+		sm := New[int, int](DefaultConfig())
+		defer sm.Stop()
+		if _, err := sm.WatchPrefix("1", make(chan ChangeEvent[int, int])); err == nil {
+			t.Error("Expected error for prefix subscription on int keys")
+		}
+	})
+
+	t.Run("Full Channel", func(t *testing.T) {
+		sm := New[string, int](DefaultConfig())
+		defer sm.Stop()
+		ch := make(chan ChangeEvent[string, int], 1)
+		sm.Watch(ch, nil)
+
+		sm.Set("a", 1)
+		sm.Set("b", 2)
+		metrics := sm.GetMetrics()
+		if metrics.TotalErrors() != 1 {
+			t.Errorf("Expected dropped event to be recorded, got %d errors", metrics.TotalErrors())
+		}
+	})
+}